@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/chenasraf/watchr/internal/completion"
+	"github.com/chenasraf/watchr/internal/config"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/ui"
+	flag "github.com/spf13/pflag"
+)
+
+// command is a watchr subcommand
+type command struct {
+	name, args, usage string
+	words             []string // fixed arguments, for completion
+}
+
+// commands lists watchr's subcommands. Anything else given in place of one
+// is the command to run, as with run.
+var commands = []command{
+	{"run", "<command>", "Run a command and watch its output (the default)", nil},
+	{"daemon", "<command>", "Run the command in the background, without the UI", nil},
+	{"attach", "[command]", "Open the UI on a daemon's latest output", nil},
+	{"snapshot", "[command]", "Print a daemon's latest output", nil},
+	{"replay", "<log dir|file>", "Browse the runs recorded by --log-output or --record", nil},
+	{"preset", "run <name> | list", "Run or list the config file's presets", []string{"run", "list"}},
+	{"config", "[show|get|path|schema]", "Print the loaded configuration, a key's value, the config file's path or its JSON Schema", []string{"show", "get", "path", "schema"}},
+	{"doctor", "", "Check the shell, clipboard, editor and remote tools watchr uses", nil},
+	{"completion", "<shell>", "Print a completion script for " + strings.Join(completion.Shells, ", "), completion.Shells},
+}
+
+// subcommand splits args into the subcommand they start with, run if none,
+// and its arguments.
+func subcommand(args []string) (string, []string) {
+	if len(args) > 0 {
+		for _, c := range commands {
+			if args[0] == c.name {
+				return c.name, args[1:]
+			}
+		}
+	}
+	return "run", args
+}
+
+// printCommands lists the subcommands for the usage text.
+func printCommands(w *os.File) {
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-30s %s\n", strings.TrimSpace(c.name+" "+c.args), c.usage)
+	}
+}
+
+// configCommand prints the loaded configuration or one key's value, the
+// path of the config file it was loaded from, or the config file's JSON
+// Schema.
+func configCommand(args []string) int {
+	switch {
+	case len(args) == 0 || len(args) == 1 && args[0] == "show":
+		config.PrintConfig()
+	case len(args) == 2 && args[0] == "get":
+		value, ok := config.Value(args[1])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Unknown config key %q\n", args[1])
+			return 1
+		}
+		fmt.Println(value)
+	case len(args) == 1 && args[0] == "path":
+		path := config.ConfigFileUsed()
+		if path == "" {
+			fmt.Fprintln(os.Stderr, "Error: No config file loaded")
+			return 1
+		}
+		fmt.Println(path)
+	case len(args) == 1 && args[0] == "schema":
+		_, _ = os.Stdout.Write(config.Schema)
+	default:
+		fmt.Fprintln(os.Stderr, "Error: Usage: watchr config [show | get <key> | path | schema]")
+		return 1
+	}
+	return 0
+}
+
+// doctorCommand checks that the external programs watchr's configuration
+// relies on are available, returning 1 if any is missing.
+func doctorCommand() int {
+	failed := false
+	report := func(name string, ok bool, detail string) {
+		mark := "ok"
+		if !ok {
+			mark, failed = "missing", true
+		}
+		fmt.Printf("  %-10s %-8s %s\n", name, mark, detail)
+	}
+	lookPath := func(name, program string) {
+		path, err := exec.LookPath(program)
+		if err != nil {
+			report(name, false, program+" not found in PATH")
+			return
+		}
+		report(name, true, path)
+	}
+
+	if path := config.ConfigFileUsed(); path != "" {
+		report("config", true, path)
+	} else {
+		report("config", true, "none (using defaults)")
+	}
+	lookPath("shell", config.GetString(config.KeyShell))
+	if args := ui.ClipboardCommand(); args != nil {
+		lookPath("clipboard", args[0])
+	} else {
+		report("clipboard", false, "no clipboard command on this platform")
+	}
+	if os.Getenv("TMUX") != "" {
+		lookPath("tmux", "tmux")
+	}
+	lookPath("editor", ui.EditorCommand()[0])
+	if config.GetString(config.KeySSH) != "" {
+		lookPath("ssh", "ssh")
+	}
+	if config.GetString(config.KeyDocker) != "" {
+		lookPath("docker", "docker")
+	}
+	if config.GetString(config.KeyKubectlPod) != "" {
+		lookPath("kubectl", "kubectl")
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// completionCommand prints the completion script for a shell.
+func completionCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: Usage: watchr completion %s\n", strings.Join(completion.Shells, "|"))
+		return 1
+	}
+	if err := completion.Generate(os.Stdout, args[0], completionOptions()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// completionOptions describes watchr's subcommands and the values its flags
+// accept for completion scripts.
+func completionOptions() completion.Options {
+	subs := make([]completion.Subcommand, len(commands))
+	for i, c := range commands {
+		subs[i] = completion.Subcommand{Name: c.name, Usage: c.usage, Words: c.words}
+		switch c.name {
+		case "config":
+			subs[i].After = map[string][]string{"get": config.Keys()}
+		case "replay":
+			subs[i].Files = true
+		}
+	}
+	return completion.Options{
+		Flags:       flag.CommandLine,
+		Subcommands: subs,
+		Values: map[string][]string{
+			"theme":            ui.ThemeNames(),
+			"border":           ui.BorderNames(),
+			"keymap":           ui.KeymapNames(),
+			"lang":             i18n.Langs(),
+			"preview-position": {"bottom", "top", "left", "right"},
+			"filter-mode":      stringsOf(filter.Kinds),
+			"filter-case":      stringsOf(filter.Cases),
+			"control-chars":    stringsOf(runner.ControlPolicies),
+			"exit-code":        {string(ui.ExitCodeLast), string(ui.ExitCodeAny)},
+		},
+		Files: []string{"config", "record", "log-output"},
+	}
+}
+
+// stringsOf converts a list of string-typed values to plain strings.
+func stringsOf[T ~string](values []T) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = string(v)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+// Package anomaly flags runs whose line count is far off the recent average,
+// for the UI's header badge and alert and for the daemon's alerts.
+package anomaly
+
+import "math"
+
+const (
+	// Window is the number of recent runs a line count is compared against
+	Window = 20
+	// MinSamples is how many runs are needed before anything is flagged
+	MinSamples = 5
+	// Threshold is how many standard deviations from the mean count as an anomaly
+	Threshold = 3.0
+)
+
+// Stats keeps the line counts of recent runs
+type Stats struct {
+	counts []int
+}
+
+// meanStddev returns the mean and standard deviation of the recorded counts
+func (s Stats) meanStddev() (float64, float64) {
+	if len(s.counts) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, c := range s.counts {
+		sum += float64(c)
+	}
+	mean := sum / float64(len(s.counts))
+	var variance float64
+	for _, c := range s.counts {
+		d := float64(c) - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / float64(len(s.counts)))
+}
+
+// Observe reports whether n deviates sharply from the recent counts, then
+// records it. The deviation is at least one line so that a perfectly stable
+// count doesn't flag every tiny change as infinitely unlikely.
+func (s *Stats) Observe(n int) (anomalous bool, mean float64) {
+	mean, stddev := s.meanStddev()
+	if len(s.counts) >= MinSamples {
+		anomalous = math.Abs(float64(n)-mean) > Threshold*max(stddev, 1)
+	}
+	s.counts = append(s.counts, n)
+	if len(s.counts) > Window {
+		s.counts = s.counts[1:]
+	}
+	return anomalous, mean
+}
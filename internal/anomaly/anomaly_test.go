@@ -0,0 +1,46 @@
+package anomaly
+
+import "testing"
+
+func TestObserve(t *testing.T) {
+	var s Stats
+	for _, n := range []int{10, 11, 10, 9, 10} {
+		if anomalous, _ := s.Observe(n); anomalous {
+			t.Errorf("expected no anomaly while warming up, got one for %d", n)
+		}
+	}
+
+	if anomalous, _ := s.Observe(11); anomalous {
+		t.Error("expected a small change not to be flagged")
+	}
+	anomalous, mean := s.Observe(200)
+	if !anomalous {
+		t.Error("expected a spike to be flagged")
+	}
+	if mean < 9 || mean > 11 {
+		t.Errorf("expected mean around 10, got %v", mean)
+	}
+}
+
+func TestObserveStableCount(t *testing.T) {
+	var s Stats
+	for range MinSamples {
+		s.Observe(0)
+	}
+	if anomalous, _ := s.Observe(1); anomalous {
+		t.Error("expected a one-line change in a stable count not to be flagged")
+	}
+	if anomalous, _ := s.Observe(5); !anomalous {
+		t.Error("expected a jump from a stable count of 0 to be flagged")
+	}
+}
+
+func TestObserveWindow(t *testing.T) {
+	var s Stats
+	for i := range Window + 10 {
+		s.Observe(i)
+	}
+	if len(s.counts) != Window {
+		t.Errorf("expected %d counts kept, got %d", Window, len(s.counts))
+	}
+}
@@ -0,0 +1,386 @@
+// Package completion generates shell completion scripts for watchr's flags,
+// subcommands, config keys and preset names.
+package completion
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Shells lists the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Subcommand is a word watchr accepts in place of the command to run
+type Subcommand struct {
+	Name, Usage string
+	Words       []string            // fixed arguments it takes, if any
+	After       map[string][]string // fixed arguments that follow one of Words, e.g. config get's keys
+	Files       bool                // whether its argument is a path
+}
+
+// presetList is the shell command that lists preset names at completion
+// time, so presets added to the config later complete too
+const presetList = "watchr preset list 2>/dev/null"
+
+// Options describe what completes where.
+type Options struct {
+	Flags       *pflag.FlagSet
+	Subcommands []Subcommand
+	Values      map[string][]string // fixed values a flag accepts, by flag name
+	Files       []string            // flags whose value is a path
+}
+
+// flag is a flag as completion scripts need it
+type flag struct {
+	name, short, usage string
+	takesValue         bool
+	values             []string
+	file               bool
+}
+
+// flags lists the visible flags, sorted by name.
+func (o Options) flags() []flag {
+	var flags []flag
+	o.Flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flags = append(flags, flag{
+			name:       f.Name,
+			short:      f.Shorthand,
+			usage:      firstSentence(f.Usage),
+			takesValue: f.Value.Type() != "bool" && f.NoOptDefVal == "",
+			values:     o.Values[f.Name],
+			file:       slices.Contains(o.Files, f.Name),
+		})
+	})
+	return flags
+}
+
+// firstSentence shortens a flag's usage to fit on a completion menu line.
+func firstSentence(usage string) string {
+	if i := strings.Index(usage, " ("); i > 0 {
+		usage = usage[:i]
+	}
+	return usage
+}
+
+// Generate writes the completion script for shell to w.
+func Generate(w io.Writer, shell string, o Options) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bash(o)
+	case "zsh":
+		script = zsh(o)
+	case "fish":
+		script = fish(o)
+	case "powershell":
+		script = powershell(o)
+	default:
+		return fmt.Errorf("unsupported shell %q (expected %s)", shell, strings.Join(Shells, ", "))
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+func (o Options) subcommandNames() string {
+	names := make([]string, len(o.Subcommands))
+	for i, s := range o.Subcommands {
+		names[i] = s.Name
+	}
+	return strings.Join(names, " ")
+}
+
+// sortedKeys returns the words of an After map in order, so scripts are
+// generated the same way every time.
+func sortedKeys(after map[string][]string) []string {
+	return slices.Sorted(maps.Keys(after))
+}
+
+// wordSubcommands returns the subcommands taking fixed arguments, leaving
+// out preset, whose run takes a preset name.
+func (o Options) wordSubcommands() []Subcommand {
+	var subs []Subcommand
+	for _, s := range o.Subcommands {
+		if len(s.Words) > 0 && s.Name != "preset" {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+func bash(o Options) string {
+	var b strings.Builder
+	var names []string
+	b.WriteString("# bash completion for watchr\n")
+	b.WriteString("_watchr() {\n")
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    case \"$prev\" in\n")
+	b.WriteString("        --preset)\n")
+	b.WriteString("            COMPREPLY=($(compgen -W \"$(" + presetList + ")\" -- \"$cur\"))\n")
+	b.WriteString("            return ;;\n")
+	for _, f := range o.flags() {
+		names = append(names, "--"+f.name)
+		if f.short != "" {
+			names = append(names, "-"+f.short)
+		}
+		if !f.takesValue || f.name == "preset" {
+			continue
+		}
+		pattern := "--" + f.name
+		if f.short != "" {
+			pattern += "|-" + f.short
+		}
+		switch {
+		case len(f.values) > 0:
+			fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n            return ;;\n", pattern, strings.Join(f.values, " "))
+		case f.file:
+			fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n            return ;;\n", pattern)
+		default:
+			fmt.Fprintf(&b, "        %s)\n            return ;;\n", pattern)
+		}
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+	b.WriteString("        preset)\n")
+	b.WriteString("            if [[ $COMP_CWORD -eq 2 ]]; then\n")
+	b.WriteString("                COMPREPLY=($(compgen -W \"run list\" -- \"$cur\"))\n")
+	b.WriteString("            elif [[ $COMP_CWORD -eq 3 && ${COMP_WORDS[2]} == run ]]; then\n")
+	b.WriteString("                COMPREPLY=($(compgen -W \"$(" + presetList + ")\" -- \"$cur\"))\n")
+	b.WriteString("            fi\n")
+	b.WriteString("            return ;;\n")
+	for _, s := range o.wordSubcommands() {
+		fmt.Fprintf(&b, "        %s)\n", s.Name)
+		fmt.Fprintf(&b, "            if [[ $COMP_CWORD -eq 2 ]]; then\n")
+		fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(s.Words, " "))
+		for _, word := range sortedKeys(s.After) {
+			fmt.Fprintf(&b, "            elif [[ $COMP_CWORD -eq 3 && ${COMP_WORDS[2]} == %s ]]; then\n", word)
+			fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(s.After[word], " "))
+		}
+		b.WriteString("            fi\n")
+		b.WriteString("            return ;;\n")
+	}
+	for _, s := range o.Subcommands {
+		if s.Files {
+			fmt.Fprintf(&b, "        %s)\n", s.Name)
+			b.WriteString("            [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+			b.WriteString("            return ;;\n")
+		}
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ $cur == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	b.WriteString("    elif [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %q -- \"$cur\") $(compgen -c -- \"$cur\"))\n", o.subcommandNames())
+	b.WriteString("    fi\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -o default -F _watchr watchr\n")
+	return b.String()
+}
+
+// zshQuote escapes a description or value for an _arguments spec.
+func zshQuote(s string) string {
+	s = strings.NewReplacer(`'`, `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+	return s
+}
+
+func zsh(o Options) string {
+	var b strings.Builder
+	b.WriteString("#compdef watchr\n\n")
+	b.WriteString("_watchr_presets() {\n")
+	b.WriteString("    local -a presets\n")
+	b.WriteString("    presets=(${(f)\"$(" + presetList + ")\"})\n")
+	b.WriteString("    _describe 'preset' presets\n")
+	b.WriteString("}\n\n")
+	b.WriteString("_watchr() {\n")
+	b.WriteString("    local state\n")
+	b.WriteString("    _arguments -s \\\n")
+	for _, f := range o.flags() {
+		names := "--" + f.name
+		if f.short != "" {
+			names = fmt.Sprintf("(-%s --%s)'{-%s,--%s}'", f.short, f.name, f.short, f.name)
+		}
+		action := ""
+		if f.takesValue {
+			switch {
+			case f.name == "preset":
+				action = ":preset:_watchr_presets"
+			case len(f.values) > 0:
+				action = fmt.Sprintf(":%s:(%s)", f.name, zshQuote(strings.Join(f.values, " ")))
+			case f.file:
+				action = fmt.Sprintf(":%s:_files", f.name)
+			default:
+				action = fmt.Sprintf(":%s: ", f.name)
+			}
+		}
+		if f.name == "bind" {
+			names = "*" + names
+		}
+		fmt.Fprintf(&b, "        '%s[%s]%s' \\\n", names, zshQuote(f.usage), action)
+	}
+	b.WriteString("        '1: :->first' \\\n")
+	b.WriteString("        '*:: :->rest'\n")
+	b.WriteString("    case $state in\n")
+	b.WriteString("    first)\n")
+	b.WriteString("        local -a subcommands\n")
+	b.WriteString("        subcommands=(\n")
+	for _, s := range o.Subcommands {
+		fmt.Fprintf(&b, "            '%s:%s'\n", s.Name, zshQuote(s.Usage))
+	}
+	b.WriteString("        )\n")
+	b.WriteString("        _describe 'subcommand' subcommands\n")
+	b.WriteString("        _command_names -e ;;\n")
+	b.WriteString("    rest)\n")
+	b.WriteString("        case $words[1] in\n")
+	b.WriteString("        preset)\n")
+	b.WriteString("            if (( CURRENT == 2 )); then\n")
+	b.WriteString("                _values 'action' run list\n")
+	b.WriteString("            elif [[ $words[2] == run ]]; then\n")
+	b.WriteString("                _watchr_presets\n")
+	b.WriteString("            fi ;;\n")
+	for _, s := range o.wordSubcommands() {
+		fmt.Fprintf(&b, "        %s)\n", s.Name)
+		fmt.Fprintf(&b, "            if (( CURRENT == 2 )); then\n")
+		fmt.Fprintf(&b, "                _values '%s' %s\n", s.Name, strings.Join(s.Words, " "))
+		for _, word := range sortedKeys(s.After) {
+			fmt.Fprintf(&b, "            elif (( CURRENT == 3 )) && [[ $words[2] == %s ]]; then\n", word)
+			fmt.Fprintf(&b, "                _values '%s' %s\n", word, strings.Join(s.After[word], " "))
+		}
+		b.WriteString("            fi ;;\n")
+	}
+	for _, s := range o.Subcommands {
+		if s.Files {
+			fmt.Fprintf(&b, "        %s) (( CURRENT == 2 )) && _files ;;\n", s.Name)
+		}
+	}
+	b.WriteString("        *) _normal ;;\n")
+	b.WriteString("        esac ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	b.WriteString("_watchr \"$@\"\n")
+	return b.String()
+}
+
+// fishQuote quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fish(o Options) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for watchr\n")
+	b.WriteString("complete -c watchr -f\n")
+	for _, f := range o.flags() {
+		line := "complete -c watchr -l " + f.name
+		if f.short != "" {
+			line += " -s " + f.short
+		}
+		if f.takesValue {
+			switch {
+			case f.name == "preset":
+				line += " -x -a '(" + presetList + ")'"
+			case len(f.values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(f.values, " "))
+			case f.file:
+				line += " -r -F"
+			default:
+				line += " -x"
+			}
+		}
+		b.WriteString(line + " -d " + fishQuote(f.usage) + "\n")
+	}
+	for _, s := range o.Subcommands {
+		fmt.Fprintf(&b, "complete -c watchr -n '__fish_use_subcommand' -a %s -d %s\n", s.Name, fishQuote(s.Usage))
+	}
+	b.WriteString("complete -c watchr -n '__fish_use_subcommand' -a '(__fish_complete_command)'\n")
+	b.WriteString("complete -c watchr -n '__fish_seen_subcommand_from preset; and not __fish_seen_subcommand_from run list' -a 'run list'\n")
+	b.WriteString("complete -c watchr -n '__fish_seen_subcommand_from preset; and __fish_seen_subcommand_from run' -a '(" + presetList + ")'\n")
+	for _, s := range o.wordSubcommands() {
+		words := strings.Join(s.Words, " ")
+		fmt.Fprintf(&b, "complete -c watchr -n '__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s' -a %s\n", s.Name, words, fishQuote(words))
+		for _, word := range sortedKeys(s.After) {
+			fmt.Fprintf(&b, "complete -c watchr -n '__fish_seen_subcommand_from %s; and __fish_seen_subcommand_from %s' -a %s\n", s.Name, word, fishQuote(strings.Join(s.After[word], " ")))
+		}
+	}
+	for _, s := range o.Subcommands {
+		if s.Files {
+			fmt.Fprintf(&b, "complete -c watchr -n '__fish_seen_subcommand_from %s' -F\n", s.Name)
+		}
+	}
+	return b.String()
+}
+
+// psQuote quotes s for PowerShell.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func powershell(o Options) string {
+	var b strings.Builder
+	b.WriteString("# PowerShell completion for watchr\n")
+	b.WriteString("Register-ArgumentCompleter -Native -CommandName watchr -ScriptBlock {\n")
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	b.WriteString("    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })\n")
+	b.WriteString("    $prev = if ($wordToComplete) { $words[-2] } else { $words[-1] }\n")
+	b.WriteString("    $flags = @(\n")
+	var values strings.Builder
+	for _, f := range o.flags() {
+		fmt.Fprintf(&b, "        @('--%s', %s)\n", f.name, psQuote(f.usage))
+		if f.short != "" {
+			fmt.Fprintf(&b, "        @('-%s', %s)\n", f.short, psQuote(f.usage))
+		}
+		if len(f.values) > 0 {
+			quoted := make([]string, len(f.values))
+			for i, v := range f.values {
+				quoted[i] = psQuote(v)
+			}
+			fmt.Fprintf(&values, "        '--%s' = @(%s)\n", f.name, strings.Join(quoted, ", "))
+		}
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    $values = @{\n")
+	b.WriteString(values.String())
+	b.WriteString("    }\n")
+	b.WriteString("    $candidates = if ($prev -eq '--preset' -or ($words.Count -ge 3 -and $words[1] -eq 'preset' -and $prev -eq 'run')) {\n")
+	b.WriteString("        @(watchr preset list 2>$null | ForEach-Object { @($_, $_) })\n")
+	b.WriteString("    } elseif ($values.ContainsKey($prev)) {\n")
+	b.WriteString("        @($values[$prev] | ForEach-Object { , @($_, $_) })\n")
+	b.WriteString("    } elseif ($prev -eq 'preset') {\n")
+	b.WriteString("        @(@('run', 'Run a preset'), @('list', 'List presets'))\n")
+	for _, s := range o.wordSubcommands() {
+		for _, word := range sortedKeys(s.After) {
+			fmt.Fprintf(&b, "    } elseif ($words.Count -ge 3 -and $words[1] -eq %s -and $prev -eq %s) {\n", psQuote(s.Name), psQuote(word))
+			quoted := make([]string, len(s.After[word]))
+			for i, w := range s.After[word] {
+				quoted[i] = fmt.Sprintf("@(%s, %s)", psQuote(w), psQuote(w))
+			}
+			fmt.Fprintf(&b, "        @(%s)\n", strings.Join(quoted, ", "))
+		}
+		fmt.Fprintf(&b, "    } elseif ($prev -eq %s) {\n", psQuote(s.Name))
+		quoted := make([]string, len(s.Words))
+		for i, w := range s.Words {
+			quoted[i] = fmt.Sprintf("@(%s, %s)", psQuote(w), psQuote(w))
+		}
+		fmt.Fprintf(&b, "        @(%s)\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("    } elseif ($wordToComplete -like '-*') {\n")
+	b.WriteString("        $flags\n")
+	b.WriteString("    } elseif ($words.Count -le 2) {\n")
+	subs := make([]string, len(o.Subcommands))
+	for i, s := range o.Subcommands {
+		subs[i] = fmt.Sprintf("@(%s, %s)", psQuote(s.Name), psQuote(s.Usage))
+	}
+	fmt.Fprintf(&b, "        @(%s)\n", strings.Join(subs, ", "))
+	b.WriteString("    }\n")
+	b.WriteString("    $candidates | Where-Object { $_[0] -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("        [System.Management.Automation.CompletionResult]::new($_[0], $_[0], 'ParameterValue', $_[1])\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
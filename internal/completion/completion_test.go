@@ -0,0 +1,57 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func testOptions() Options {
+	flags := pflag.NewFlagSet("watchr", pflag.ContinueOnError)
+	flags.StringP("theme", "T", "default", "Color theme (e.g., dark)")
+	flags.String("preset", "", "Run a named preset")
+	flags.StringP("config", "c", "", "Load config from specified path")
+	flags.Bool("wrap", false, "Soft-wrap long lines")
+	flags.String("hidden", "", "Not shown")
+	_ = flags.MarkHidden("hidden")
+	return Options{
+		Flags: flags,
+		Subcommands: []Subcommand{
+			{Name: "preset", Usage: "Run or list presets", Words: []string{"run", "list"}},
+			{Name: "config", Usage: "Print the configuration", Words: []string{"show", "get"}, After: map[string][]string{"get": {"refresh", "shell"}}},
+			{Name: "replay", Usage: "Browse recorded runs", Files: true},
+			{Name: "completion", Usage: "Print a completion script", Words: Shells},
+		},
+		Values: map[string][]string{"theme": {"default", "light"}},
+		Files:  []string{"config"},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, shell := range Shells {
+		var b strings.Builder
+		if err := Generate(&b, shell, testOptions()); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		script := b.String()
+		for _, want := range []string{"theme", "wrap", "config", "light", "completion", "powershell", "refresh", "replay", presetList[:len("watchr preset list")]} {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s: expected the script to mention %q", shell, want)
+			}
+		}
+		if strings.Contains(script, "hidden") {
+			t.Errorf("%s: expected hidden flags to be left out", shell)
+		}
+		if strings.Contains(script, "e.g.") {
+			t.Errorf("%s: expected usage to be cut before its examples", shell)
+		}
+	}
+}
+
+func TestGenerateUnknownShell(t *testing.T) {
+	var b strings.Builder
+	if err := Generate(&b, "tcsh", testOptions()); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
@@ -1,12 +1,18 @@
 package config
 
 import (
+	_ "embed"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -18,30 +24,264 @@ const (
 	KeyShell            = "shell"
 	KeyPreviewSize      = "preview-size"
 	KeyPreviewPosition  = "preview-position"
+	KeyAutoPreview      = "auto-preview"
+	KeyPreviewOpen      = "preview-open"
+	KeyPreviewCommand   = "preview-command"
+	KeyPreviewContext   = "preview-context"
+	KeyFilePreview      = "file-preview"
 	KeyLineNumbers      = "line-numbers"
 	KeyLineWidth        = "line-width"
+	KeyDifferences      = "differences"
+	KeyRelativeNumbers  = "relative-line-numbers"
+	KeyDualNumbers      = "dual-line-numbers"
 	KeyPrompt           = "prompt"
 	KeyRefresh          = "refresh"
 	KeyRefreshFromStart = "refresh-from-start"
+	KeyPrecise          = "precise"
+	KeyRefreshJitter    = "refresh-jitter"
 	KeyInteractive      = "interactive"
+	KeyProgressFrames   = "keep-progress-frames"
+	KeyInitialRun       = "initial-run"
+	KeyMaxRuns          = "max-runs"
+	KeyFor              = "for"
+	KeyExitOnLimit      = "exit-on-limit"
+	KeyJournal          = "journal"
+	KeyControlChars     = "control-chars"
+	KeyAnomalyAlert     = "anomaly-alert"
+	KeySSH              = "ssh"
+	KeyDocker           = "docker"
+	KeyKubectlPod       = "kubectl-pod"
+	KeyLogOutput        = "log-output"
+	KeyMinInterval      = "min-interval"
+	KeyRecord           = "record"
+	KeyExitCode         = "exit-code"
+	KeyPreRun           = "pre-run"
+	KeyPostRun          = "post-run"
+	KeyTransitionBell   = "transition-bell"
+	KeyOnTransition     = "on-transition"
+	KeyWebhookURL       = "webhook.url"
+	KeyWebhookOnChange  = "webhook.on-change"
+	KeyWebhookMatch     = "webhook.match"
+	KeyMouse            = "mouse"
+	KeyWrap             = "wrap"
+	KeyAppend           = "append"
+	KeyAppendRuns       = "append-runs"
+	KeyFilter           = "filter"
+	KeyFilterMode       = "filter-mode"
+	KeyFilterCase       = "filter-case"
+	KeyScrollOff        = "scrolloff"
+	KeyScrollbar        = "scrollbar"
+	KeyBind             = "bind"
+	KeyDelimiter        = "delimiter"
+	KeyWithNth          = "with-nth"
+	KeyYankDisplayed    = "yank-displayed"
+	KeyTable            = "table"
+	KeyHeaderLines      = "header-lines"
+	KeyYankFormat       = "yank-format"
+	KeyTimeFormat       = "time-format"
+	KeyTheme            = "theme.name"
+	KeyThemeColors      = "theme"
+	KeyBorder           = "border"
+	KeyCompact          = "compact"
+	KeyHeaderTemplate   = "header-template"
+	KeyTitle            = "title"
+	KeyHints            = "hints"
+	KeyStatusBar        = "status-bar"
+	KeyHighlightNew     = "highlight-new"
+	KeyDimAge           = "dim-age"
+	KeyLogLevels        = "log-levels"
+	KeyTabWidth         = "tab-width"
+	KeyPresets          = "presets"
+	KeyPreset           = "preset"
+	KeyInclude          = "include"
+	KeyRemember         = "remember"
+	KeyKeymap           = "keymap"
+	KeyLang             = "lang"
+	KeyEllipsis         = "ellipsis"
+	KeyWrapMarker       = "wrap-marker"
+	KeyFiller           = "filler"
 )
 
+// Schema is the JSON Schema of the config file, for editors' YAML language
+// servers to complete and validate it with
+//
+//go:embed schema.json
+var Schema []byte
+
+// Keys returns the options a config file can set, sorted, as listed in
+// Schema.
+func Keys() []string {
+	var schema struct {
+		Definitions struct {
+			Options struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"options"`
+		} `json:"definitions"`
+	}
+	_ = json.Unmarshal(Schema, &schema)
+	return slices.Sorted(maps.Keys(schema.Definitions.Options.Properties))
+}
+
+// Value returns the loaded value of a config key, taking the inverted no-
+// flags into account, and false if the key is unknown.
+func Value(key string) (any, bool) {
+	if !slices.Contains(Keys(), key) {
+		return nil, false
+	}
+	inverted := map[string]func() bool{
+		KeyLineNumbers: ShowLineNumbers,
+		KeyInitialRun:  InitialRun,
+		KeyMouse:       Mouse,
+		KeyFilePreview: FilePreview,
+		KeyScrollbar:   Scrollbar,
+		KeyTitle:       Title,
+		KeyHints:       Hints,
+		KeyLogLevels:   LogLevels,
+		KeyRemember:    Remember,
+	}
+	if get, ok := inverted[key]; ok {
+		return get(), true
+	}
+	return viper.Get(key), true
+}
+
 // setDefaults sets the default configuration values.
 func setDefaults() {
-	viper.SetDefault(KeyShell, "sh")
+	viper.SetDefault(KeyShell, DefaultShell())
 	viper.SetDefault(KeyPreviewSize, "40%")
 	viper.SetDefault(KeyPreviewPosition, "bottom")
+	viper.SetDefault(KeyAutoPreview, false)
+	viper.SetDefault(KeyPreviewOpen, false)
+	viper.SetDefault(KeyPreviewCommand, "")
+	viper.SetDefault(KeyPreviewContext, 5)
+	viper.SetDefault(KeyFilePreview, true)
 	viper.SetDefault(KeyLineNumbers, true)
 	viper.SetDefault(KeyLineWidth, 6)
+	viper.SetDefault(KeyDifferences, false)
+	viper.SetDefault(KeyRelativeNumbers, false)
+	viper.SetDefault(KeyDualNumbers, false)
 	viper.SetDefault(KeyPrompt, "watchr> ")
 	viper.SetDefault(KeyRefresh, "0")
 	viper.SetDefault(KeyRefreshFromStart, false)
+	viper.SetDefault(KeyPrecise, false)
+	viper.SetDefault(KeyRefreshJitter, "0")
 	viper.SetDefault(KeyInteractive, false)
+	viper.SetDefault(KeyProgressFrames, false)
+	viper.SetDefault(KeyInitialRun, true)
+	viper.SetDefault(KeyMaxRuns, 0)
+	viper.SetDefault(KeyFor, "0")
+	viper.SetDefault(KeyExitOnLimit, false)
+	viper.SetDefault(KeyJournal, true)
+	viper.SetDefault(KeyControlChars, "strip")
+	viper.SetDefault(KeyAnomalyAlert, false)
+	viper.SetDefault(KeySSH, "")
+	viper.SetDefault(KeyDocker, "")
+	viper.SetDefault(KeyKubectlPod, "")
+	viper.SetDefault(KeyLogOutput, "")
+	viper.SetDefault(KeyMinInterval, "0")
+	viper.SetDefault(KeyRecord, "")
+	viper.SetDefault(KeyExitCode, "")
+	viper.SetDefault(KeyPreRun, "")
+	viper.SetDefault(KeyPostRun, "")
+	viper.SetDefault(KeyTransitionBell, false)
+	viper.SetDefault(KeyOnTransition, "")
+	viper.SetDefault(KeyWebhookURL, "")
+	viper.SetDefault(KeyWebhookOnChange, true)
+	viper.SetDefault(KeyWebhookMatch, "")
+	viper.SetDefault(KeyMouse, true)
+	viper.SetDefault(KeyWrap, false)
+	viper.SetDefault(KeyAppend, false)
+	viper.SetDefault(KeyAppendRuns, 50)
+	viper.SetDefault(KeyFilter, "")
+	viper.SetDefault(KeyFilterMode, "substring")
+	viper.SetDefault(KeyFilterCase, "smart")
+	viper.SetDefault(KeyScrollOff, -1)
+	viper.SetDefault(KeyScrollbar, true)
+	viper.SetDefault(KeyDelimiter, "")
+	viper.SetDefault(KeyWithNth, "")
+	viper.SetDefault(KeyYankDisplayed, false)
+	viper.SetDefault(KeyTable, false)
+	viper.SetDefault(KeyHeaderLines, 0)
+	viper.SetDefault(KeyYankFormat, "plain")
+	viper.SetDefault(KeyTimeFormat, "")
+	viper.SetDefault(KeyTheme, "default")
+	viper.SetDefault(KeyBorder, "rounded")
+	viper.SetDefault(KeyCompact, false)
+	viper.SetDefault(KeyHeaderTemplate, "")
+	viper.SetDefault(KeyTitle, true)
+	viper.SetDefault(KeyHints, true)
+	viper.SetDefault(KeyStatusBar, "")
+	viper.SetDefault(KeyHighlightNew, "0")
+	viper.SetDefault(KeyDimAge, "")
+	viper.SetDefault(KeyLogLevels, true)
+	viper.SetDefault(KeyTabWidth, 8)
+	viper.SetDefault(KeyPreset, "")
+	viper.SetDefault(KeyRemember, true)
+	viper.SetDefault(KeyKeymap, "vim")
+	viper.SetDefault(KeyLang, "en")
+	viper.SetDefault(KeyEllipsis, "…")
+	viper.SetDefault(KeyWrapMarker, "")
+	viper.SetDefault(KeyFiller, "")
+}
+
+// DefaultShell returns the shell commands run with unless one is configured:
+// sh, or on Windows PowerShell if it is installed and cmd otherwise.
+func DefaultShell() string {
+	return defaultShell(runtime.GOOS, exec.LookPath)
+}
+
+func defaultShell(goos string, lookPath func(string) (string, error)) string {
+	if goos != "windows" {
+		return "sh"
+	}
+	for _, shell := range []string{"pwsh", "powershell"} {
+		if _, err := lookPath(shell); err == nil {
+			return shell
+		}
+	}
+	return "cmd"
+}
+
+// RemoteShell returns the shell commands run with on --ssh, --docker and
+// --kubectl-pod targets. Those are usually Linux whatever the local OS is, so
+// it's sh unless a shell was chosen with --shell, WATCHR_SHELL, the config
+// file or a preset.
+func RemoteShell(flags *pflag.FlagSet) string {
+	if flags.Changed("shell") || viper.InConfig(KeyShell) || os.Getenv(EnvPrefix+"_SHELL") != "" {
+		return GetString(KeyShell)
+	}
+	return "sh"
+}
+
+// EnvPrefix prefixes the environment variables config keys can be set with
+const EnvPrefix = "WATCHR"
+
+// hookEnvKeys are the config keys whose variable watchr itself sets for the
+// hooks it runs (WATCHR_EXIT_CODE). A watchr started from a hook inherits
+// it, so these keys aren't read from the environment.
+var hookEnvKeys = []string{KeyExitCode}
+
+// bindEnv lets every config key be set from the environment, as WATCHR_ and
+// the key in upper case with dashes and dots as underscores (e.g.
+// WATCHR_REFRESH, WATCHR_THEME_NAME). Environment variables override config
+// files but not flags.
+func bindEnv() {
+	var pairs []string
+	for _, key := range hookEnvKeys {
+		// Keeping the dash leaves a name no environment variable has
+		name := strings.ToUpper(EnvPrefix + "_" + key)
+		pairs = append(pairs, name, name)
+	}
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(append(pairs, "-", "_", ".", "_")...))
+	viper.AutomaticEnv()
 }
 
-// Init initializes Viper with config file paths and defaults.
-func Init() {
+// Init initializes Viper with config file paths and defaults. Only errors in
+// the files a config file includes are returned.
+func Init() error {
 	setDefaults()
+	bindEnv()
 
 	// Config file name (without extension)
 	viper.SetConfigName("watchr")
@@ -57,18 +297,77 @@ func Init() {
 	viper.AddConfigPath(".")
 
 	// Try to read config file (errors are ignored if file doesn't exist)
-	_ = viper.ReadInConfig()
+	if err := viper.ReadInConfig(); err != nil {
+		return nil
+	}
+	return applyIncludes()
 }
 
 // InitWithFile initializes Viper with a specific config file path.
 func InitWithFile(path string) error {
 	setDefaults()
+	bindEnv()
 
 	viper.SetConfigFile(path)
 	if err := viper.ReadInConfig(); err != nil {
 		return err
 	}
-	return nil
+	return applyIncludes()
+}
+
+// applyIncludes layers the config files the loaded one lists under include
+// beneath it, so a shared base config can be overridden by personal settings.
+func applyIncludes() error {
+	if len(viper.GetStringSlice(KeyInclude)) == 0 {
+		return nil
+	}
+	path, err := filepath.Abs(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	settings, err := readWithIncludes(path, nil)
+	if err != nil {
+		return err
+	}
+	return viper.MergeConfigMap(settings)
+}
+
+// readWithIncludes reads the config file at path over the files it includes,
+// each over the ones before it. Included paths are relative to the file that
+// includes them. chain holds the files including this one, to detect cycles.
+func readWithIncludes(path string, chain []string) (map[string]any, error) {
+	if slices.Contains(chain, path) {
+		return nil, fmt.Errorf("config include cycle: %s", strings.Join(append(chain, path), " -> "))
+	}
+	chain = append(chain, path)
+
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	merged := viper.New()
+	for _, include := range file.GetStringSlice(KeyInclude) {
+		if rest, ok := strings.CutPrefix(include, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				include = filepath.Join(home, rest)
+			}
+		}
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		settings, err := readWithIncludes(filepath.Clean(include), chain)
+		if err != nil {
+			return nil, err
+		}
+		if err := merged.MergeConfigMap(settings); err != nil {
+			return nil, err
+		}
+	}
+	if err := merged.MergeConfigMap(file.AllSettings()); err != nil {
+		return nil, err
+	}
+	return merged.AllSettings(), nil
 }
 
 // BindFlags binds pflags to Viper. Should be called after flag definitions
@@ -78,14 +377,94 @@ func BindFlags(flags *pflag.FlagSet) {
 	_ = viper.BindPFlag(KeyShell, flags.Lookup("shell"))
 	_ = viper.BindPFlag(KeyPreviewSize, flags.Lookup("preview-size"))
 	_ = viper.BindPFlag(KeyPreviewPosition, flags.Lookup("preview-position"))
+	_ = viper.BindPFlag(KeyAutoPreview, flags.Lookup("auto-preview"))
+	_ = viper.BindPFlag(KeyPreviewOpen, flags.Lookup("preview-open"))
+	_ = viper.BindPFlag(KeyPreviewCommand, flags.Lookup("preview"))
+	_ = viper.BindPFlag(KeyPreviewContext, flags.Lookup("preview-context"))
 	_ = viper.BindPFlag(KeyLineWidth, flags.Lookup("line-width"))
+	_ = viper.BindPFlag(KeyDifferences, flags.Lookup("differences"))
+	_ = viper.BindPFlag(KeyRelativeNumbers, flags.Lookup("relative-line-numbers"))
+	_ = viper.BindPFlag(KeyDualNumbers, flags.Lookup("dual-line-numbers"))
 	_ = viper.BindPFlag(KeyPrompt, flags.Lookup("prompt"))
 	_ = viper.BindPFlag(KeyRefresh, flags.Lookup("refresh"))
 	_ = viper.BindPFlag(KeyRefreshFromStart, flags.Lookup("refresh-from-start"))
+	_ = viper.BindPFlag(KeyPrecise, flags.Lookup("precise"))
+	_ = viper.BindPFlag(KeyRefreshJitter, flags.Lookup("refresh-jitter"))
 	_ = viper.BindPFlag(KeyInteractive, flags.Lookup("interactive"))
+	_ = viper.BindPFlag(KeyProgressFrames, flags.Lookup("keep-progress-frames"))
+	_ = viper.BindPFlag(KeyMaxRuns, flags.Lookup("max-runs"))
+	_ = viper.BindPFlag(KeyFor, flags.Lookup("for"))
+	_ = viper.BindPFlag(KeyExitOnLimit, flags.Lookup("exit-on-limit"))
+	_ = viper.BindPFlag(KeyControlChars, flags.Lookup("control-chars"))
+	_ = viper.BindPFlag(KeyTabWidth, flags.Lookup("tab-width"))
+	_ = viper.BindPFlag(KeyPreset, flags.Lookup("preset"))
+	_ = viper.BindPFlag(KeyAnomalyAlert, flags.Lookup("anomaly-alert"))
+	_ = viper.BindPFlag(KeySSH, flags.Lookup("ssh"))
+	_ = viper.BindPFlag(KeyDocker, flags.Lookup("docker"))
+	_ = viper.BindPFlag(KeyKubectlPod, flags.Lookup("kubectl-pod"))
+	_ = viper.BindPFlag(KeyLogOutput, flags.Lookup("log-output"))
+	_ = viper.BindPFlag(KeyMinInterval, flags.Lookup("min-interval"))
+	_ = viper.BindPFlag(KeyRecord, flags.Lookup("record"))
+	_ = viper.BindPFlag(KeyExitCode, flags.Lookup("exit-code"))
+	_ = viper.BindPFlag(KeyPreRun, flags.Lookup("pre-run"))
+	_ = viper.BindPFlag(KeyPostRun, flags.Lookup("post-run"))
+	_ = viper.BindPFlag(KeyTransitionBell, flags.Lookup("transition-bell"))
+	_ = viper.BindPFlag(KeyOnTransition, flags.Lookup("on-transition"))
+	_ = viper.BindPFlag(KeyWrap, flags.Lookup("wrap"))
+	_ = viper.BindPFlag(KeyAppend, flags.Lookup("append"))
+	_ = viper.BindPFlag(KeyAppendRuns, flags.Lookup("append-runs"))
+	_ = viper.BindPFlag(KeyFilter, flags.Lookup("filter"))
+	_ = viper.BindPFlag(KeyFilterMode, flags.Lookup("filter-mode"))
+	_ = viper.BindPFlag(KeyFilterCase, flags.Lookup("filter-case"))
+	_ = viper.BindPFlag(KeyScrollOff, flags.Lookup("scrolloff"))
+	_ = viper.BindPFlag(KeyDelimiter, flags.Lookup("delimiter"))
+	_ = viper.BindPFlag(KeyWithNth, flags.Lookup("with-nth"))
+	_ = viper.BindPFlag(KeyYankDisplayed, flags.Lookup("yank-displayed"))
+	_ = viper.BindPFlag(KeyTable, flags.Lookup("table"))
+	_ = viper.BindPFlag(KeyHeaderLines, flags.Lookup("header-lines"))
+	_ = viper.BindPFlag(KeyYankFormat, flags.Lookup("yank-format"))
+	_ = viper.BindPFlag(KeyTimeFormat, flags.Lookup("time-format"))
+	_ = viper.BindPFlag(KeyTheme, flags.Lookup("theme"))
+	_ = viper.BindPFlag(KeyBorder, flags.Lookup("border"))
+	_ = viper.BindPFlag(KeyKeymap, flags.Lookup("keymap"))
+	_ = viper.BindPFlag(KeyLang, flags.Lookup("lang"))
+	_ = viper.BindPFlag(KeyEllipsis, flags.Lookup("ellipsis"))
+	_ = viper.BindPFlag(KeyWrapMarker, flags.Lookup("wrap-marker"))
+	_ = viper.BindPFlag(KeyFiller, flags.Lookup("filler"))
+	_ = viper.BindPFlag(KeyCompact, flags.Lookup("compact"))
+	_ = viper.BindPFlag(KeyHeaderTemplate, flags.Lookup("header-template"))
+	_ = viper.BindPFlag(KeyStatusBar, flags.Lookup("status-bar"))
+	_ = viper.BindPFlag(KeyHighlightNew, flags.Lookup("highlight-new"))
+	_ = viper.BindPFlag(KeyDimAge, flags.Lookup("dim-age"))
+	_ = viper.BindPFlag(KeyWebhookURL, flags.Lookup("webhook"))
+	_ = viper.BindPFlag(KeyWebhookMatch, flags.Lookup("webhook-match"))
 
 	// line-numbers is inverted (no-line-numbers flag)
 	_ = viper.BindPFlag("no-line-numbers", flags.Lookup("no-line-numbers"))
+
+	// initial-run is inverted (no-initial-run flag)
+	_ = viper.BindPFlag("no-initial-run", flags.Lookup("no-initial-run"))
+
+	// mouse is inverted (no-mouse flag)
+	_ = viper.BindPFlag("no-mouse", flags.Lookup("no-mouse"))
+
+	// file-preview is inverted (no-file-preview flag)
+	_ = viper.BindPFlag("no-file-preview", flags.Lookup("no-file-preview"))
+
+	// scrollbar is inverted (no-scrollbar flag)
+	_ = viper.BindPFlag("no-scrollbar", flags.Lookup("no-scrollbar"))
+
+	// title is inverted (no-title flag)
+	_ = viper.BindPFlag("no-title", flags.Lookup("no-title"))
+
+	// hints is inverted (no-hints flag)
+	_ = viper.BindPFlag("no-hints", flags.Lookup("no-hints"))
+
+	// log-levels is inverted (no-log-levels flag)
+	_ = viper.BindPFlag("no-log-levels", flags.Lookup("no-log-levels"))
+
+	// remember is inverted (no-remember flag)
+	_ = viper.BindPFlag("no-remember", flags.Lookup("no-remember"))
 }
 
 // GetString returns a string config value.
@@ -114,6 +493,143 @@ func ShowLineNumbers() bool {
 	return viper.GetBool(KeyLineNumbers)
 }
 
+// InitialRun returns whether the command should run as soon as the UI starts.
+// This handles the inverted no-initial-run flag.
+func InitialRun() bool {
+	if viper.GetBool("no-initial-run") {
+		return false
+	}
+	return viper.GetBool(KeyInitialRun)
+}
+
+// Mouse returns whether mouse events should be captured.
+// This handles the inverted no-mouse flag.
+func Mouse() bool {
+	if viper.GetBool("no-mouse") {
+		return false
+	}
+	return viper.GetBool(KeyMouse)
+}
+
+// FilePreview returns whether lines naming a file preview its contents.
+// This handles the inverted no-file-preview flag.
+func FilePreview() bool {
+	if viper.GetBool("no-file-preview") {
+		return false
+	}
+	return viper.GetBool(KeyFilePreview)
+}
+
+// Scrollbar returns whether a scrollbar is drawn beside the list.
+// This handles the inverted no-scrollbar flag.
+func Scrollbar() bool {
+	if viper.GetBool("no-scrollbar") {
+		return false
+	}
+	return viper.GetBool(KeyScrollbar)
+}
+
+// Title returns whether the title line with the command is shown.
+// This handles the inverted no-title flag.
+func Title() bool {
+	if viper.GetBool("no-title") {
+		return false
+	}
+	return viper.GetBool(KeyTitle)
+}
+
+// Hints returns whether the prompt line shows the help hint.
+// This handles the inverted no-hints flag.
+func Hints() bool {
+	if viper.GetBool("no-hints") {
+		return false
+	}
+	return viper.GetBool(KeyHints)
+}
+
+// LogLevels returns whether lines are colored by their log level.
+// This handles the inverted no-log-levels flag.
+func LogLevels() bool {
+	if viper.GetBool("no-log-levels") {
+		return false
+	}
+	return viper.GetBool(KeyLogLevels)
+}
+
+// Remember returns whether each command's filter, preview and selected line
+// are restored the next time it is watched.
+// This handles the inverted no-remember flag.
+func Remember() bool {
+	if viper.GetBool("no-remember") {
+		return false
+	}
+	return viper.GetBool(KeyRemember)
+}
+
+// Bindings returns the key bindings from the config file's bind map, with
+// "key:action" entries from the --bind flag added on top.
+func Bindings(flagBinds []string) (map[string]string, error) {
+	bindings := viper.GetStringMapString(KeyBind)
+	for _, b := range flagBinds {
+		key, action, ok := strings.Cut(b, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid binding %q (expected key:action)", b)
+		}
+		bindings[key] = action
+	}
+	return bindings, nil
+}
+
+// PresetNames returns the names of the presets in the config file's presets
+// section, sorted.
+func PresetNames() []string {
+	names := make([]string, 0)
+	for name := range viper.GetStringMap(KeyPresets) {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ApplyPreset layers the named preset's options over the config file's, so
+// environment variables and flags still override them, and returns its
+// command. A preset sets a command and any config file options, e.g.:
+//
+//	presets:
+//	  pods:
+//	    command: kubectl get pods
+//	    refresh: 5s
+//	    preview-command: kubectl describe pod {1}
+func ApplyPreset(name string) (string, error) {
+	raw, ok := viper.GetStringMap(KeyPresets)[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("no preset named %q (have: %s)", name, strings.Join(PresetNames(), ", "))
+	}
+	preset, ok := raw.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("preset %q must be a map of options", name)
+	}
+	options := make(map[string]any, len(preset))
+	for key, value := range preset {
+		if key != "command" {
+			options[key] = value
+		}
+	}
+	if err := viper.MergeConfigMap(options); err != nil {
+		return "", err
+	}
+	command, _ := preset["command"].(string)
+	return command, nil
+}
+
+// ThemeColors returns the color overrides from the config file's theme
+// section, by color name.
+func ThemeColors() map[string]string {
+	colors := viper.GetStringMapString(KeyThemeColors)
+	delete(colors, "name")
+	return colors
+}
+
 // ConfigFileUsed returns the config file path if one was loaded.
 func ConfigFileUsed() string {
 	return viper.ConfigFileUsed()
@@ -132,12 +648,80 @@ func PrintConfig() {
 	fmt.Printf("  %-20s %s\n", KeyShell+":", GetString(KeyShell))
 	fmt.Printf("  %-20s %s\n", KeyPreviewSize+":", GetString(KeyPreviewSize))
 	fmt.Printf("  %-20s %s\n", KeyPreviewPosition+":", GetString(KeyPreviewPosition))
+	fmt.Printf("  %-20s %v\n", KeyAutoPreview+":", GetBool(KeyAutoPreview))
+	fmt.Printf("  %-20s %v\n", KeyPreviewOpen+":", GetBool(KeyPreviewOpen))
+	fmt.Printf("  %-20s %q\n", KeyPreviewCommand+":", GetString(KeyPreviewCommand))
+	fmt.Printf("  %-20s %d\n", KeyPreviewContext+":", GetInt(KeyPreviewContext))
+	fmt.Printf("  %-20s %v\n", KeyFilePreview+":", FilePreview())
 	fmt.Printf("  %-20s %v\n", KeyLineNumbers+":", GetBool(KeyLineNumbers))
 	fmt.Printf("  %-20s %d\n", KeyLineWidth+":", GetInt(KeyLineWidth))
+	fmt.Printf("  %-20s %v\n", KeyDifferences+":", GetBool(KeyDifferences))
+	fmt.Printf("  %-20s %v\n", KeyRelativeNumbers+":", GetBool(KeyRelativeNumbers))
+	fmt.Printf("  %-20s %v\n", KeyDualNumbers+":", GetBool(KeyDualNumbers))
 	fmt.Printf("  %-20s %q\n", KeyPrompt+":", GetString(KeyPrompt))
 	fmt.Printf("  %-20s %s\n", KeyRefresh+":", GetString(KeyRefresh))
 	fmt.Printf("  %-20s %v\n", KeyRefreshFromStart+":", GetBool(KeyRefreshFromStart))
+	fmt.Printf("  %-20s %v\n", KeyPrecise+":", GetBool(KeyPrecise))
+	fmt.Printf("  %-20s %s\n", KeyRefreshJitter+":", GetString(KeyRefreshJitter))
 	fmt.Printf("  %-20s %v\n", KeyInteractive+":", GetBool(KeyInteractive))
+	fmt.Printf("  %-20s %v\n", KeyProgressFrames+":", GetBool(KeyProgressFrames))
+	fmt.Printf("  %-20s %v\n", KeyInitialRun+":", InitialRun())
+	fmt.Printf("  %-20s %v\n", KeyMouse+":", Mouse())
+	fmt.Printf("  %-20s %v\n", KeyWrap+":", GetBool(KeyWrap))
+	fmt.Printf("  %-20s %v\n", KeyAppend+":", GetBool(KeyAppend))
+	fmt.Printf("  %-20s %d\n", KeyAppendRuns+":", GetInt(KeyAppendRuns))
+	fmt.Printf("  %-20s %s\n", KeyFilter+":", GetString(KeyFilter))
+	fmt.Printf("  %-20s %s\n", KeyFilterMode+":", GetString(KeyFilterMode))
+	fmt.Printf("  %-20s %s\n", KeyFilterCase+":", GetString(KeyFilterCase))
+	fmt.Printf("  %-20s %d\n", KeyScrollOff+":", GetInt(KeyScrollOff))
+	fmt.Printf("  %-20s %v\n", KeyScrollbar+":", Scrollbar())
+	fmt.Printf("  %-20s %q\n", KeyDelimiter+":", GetString(KeyDelimiter))
+	fmt.Printf("  %-20s %s\n", KeyWithNth+":", GetString(KeyWithNth))
+	fmt.Printf("  %-20s %v\n", KeyYankDisplayed+":", GetBool(KeyYankDisplayed))
+	fmt.Printf("  %-20s %v\n", KeyTable+":", GetBool(KeyTable))
+	fmt.Printf("  %-20s %d\n", KeyHeaderLines+":", GetInt(KeyHeaderLines))
+	fmt.Printf("  %-20s %s\n", KeyYankFormat+":", GetString(KeyYankFormat))
+	fmt.Printf("  %-20s %q\n", KeyTimeFormat+":", GetString(KeyTimeFormat))
+	fmt.Printf("  %-20s %s\n", KeyTheme+":", GetString(KeyTheme))
+	fmt.Printf("  %-20s %v\n", KeyThemeColors+":", ThemeColors())
+	fmt.Printf("  %-20s %s\n", KeyBorder+":", GetString(KeyBorder))
+	fmt.Printf("  %-20s %s\n", KeyKeymap+":", GetString(KeyKeymap))
+	fmt.Printf("  %-20s %s\n", KeyLang+":", GetString(KeyLang))
+	fmt.Printf("  %-20s %q\n", KeyEllipsis+":", GetString(KeyEllipsis))
+	fmt.Printf("  %-20s %q\n", KeyWrapMarker+":", GetString(KeyWrapMarker))
+	fmt.Printf("  %-20s %q\n", KeyFiller+":", GetString(KeyFiller))
+	fmt.Printf("  %-20s %v\n", KeyCompact+":", GetBool(KeyCompact))
+	fmt.Printf("  %-20s %q\n", KeyHeaderTemplate+":", GetString(KeyHeaderTemplate))
+	fmt.Printf("  %-20s %v\n", KeyTitle+":", Title())
+	fmt.Printf("  %-20s %v\n", KeyHints+":", Hints())
+	fmt.Printf("  %-20s %q\n", KeyStatusBar+":", GetString(KeyStatusBar))
+	fmt.Printf("  %-20s %s\n", KeyHighlightNew+":", GetString(KeyHighlightNew))
+	fmt.Printf("  %-20s %s\n", KeyDimAge+":", GetString(KeyDimAge))
+	fmt.Printf("  %-20s %v\n", KeyLogLevels+":", LogLevels())
+	fmt.Printf("  %-20s %d\n", KeyMaxRuns+":", GetInt(KeyMaxRuns))
+	fmt.Printf("  %-20s %s\n", KeyFor+":", GetString(KeyFor))
+	fmt.Printf("  %-20s %v\n", KeyExitOnLimit+":", GetBool(KeyExitOnLimit))
+	fmt.Printf("  %-20s %v\n", KeyJournal+":", GetBool(KeyJournal))
+	fmt.Printf("  %-20s %v\n", KeyRemember+":", Remember())
+	fmt.Printf("  %-20s %s\n", KeyControlChars+":", GetString(KeyControlChars))
+	fmt.Printf("  %-20s %d\n", KeyTabWidth+":", GetInt(KeyTabWidth))
+	fmt.Printf("  %-20s %v\n", KeyAnomalyAlert+":", GetBool(KeyAnomalyAlert))
+	fmt.Printf("  %-20s %s\n", KeySSH+":", GetString(KeySSH))
+	fmt.Printf("  %-20s %s\n", KeyDocker+":", GetString(KeyDocker))
+	fmt.Printf("  %-20s %s\n", KeyKubectlPod+":", GetString(KeyKubectlPod))
+	fmt.Printf("  %-20s %s\n", KeyLogOutput+":", GetString(KeyLogOutput))
+	fmt.Printf("  %-20s %s\n", KeyMinInterval+":", GetString(KeyMinInterval))
+	fmt.Printf("  %-20s %s\n", KeyRecord+":", GetString(KeyRecord))
+	fmt.Printf("  %-20s %s\n", KeyExitCode+":", GetString(KeyExitCode))
+	fmt.Printf("  %-20s %q\n", KeyPreRun+":", GetString(KeyPreRun))
+	fmt.Printf("  %-20s %q\n", KeyPostRun+":", GetString(KeyPostRun))
+	fmt.Printf("  %-20s %v\n", KeyTransitionBell+":", GetBool(KeyTransitionBell))
+	fmt.Printf("  %-20s %q\n", KeyOnTransition+":", GetString(KeyOnTransition))
+	fmt.Printf("  %-20s %q\n", KeyWebhookURL+":", GetString(KeyWebhookURL))
+	fmt.Printf("  %-20s %v\n", KeyWebhookOnChange+":", GetBool(KeyWebhookOnChange))
+	fmt.Printf("  %-20s %q\n", KeyWebhookMatch+":", GetString(KeyWebhookMatch))
+	fmt.Printf("  %-20s %v\n", KeyBind+":", viper.GetStringMapString(KeyBind))
+	fmt.Printf("  %-20s %v\n", KeyPresets+":", PresetNames())
 }
 
 // getConfigDir returns the appropriate config directory for the OS.
@@ -167,6 +751,7 @@ var durationRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)(ms|s|m|h)?$`)
 //   - "500ms", "1500ms" - explicit milliseconds
 //   - "5m", "1.5m" - explicit minutes
 //   - "1h", "0.5h" - explicit hours
+//   - "1h30m", "2m30s" - Go duration strings combining units
 //
 // Returns 0 if the input is empty or "0".
 func ParseDuration(s string) (time.Duration, error) {
@@ -176,7 +761,10 @@ func ParseDuration(s string) (time.Duration, error) {
 
 	matches := durationRegex.FindStringSubmatch(s)
 	if matches == nil {
-		return 0, fmt.Errorf("invalid duration format: %q (expected number, Xms, Xs, Xm, or Xh)", s)
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 && !strings.HasPrefix(s, "+") {
+			return d, nil
+		}
+		return 0, fmt.Errorf("invalid duration format: %q (expected number, Xms, Xs, Xm, Xh, or e.g. 1h30m)", s)
 	}
 
 	value, err := strconv.ParseFloat(matches[1], 64)
@@ -210,3 +798,24 @@ func GetDuration(key string) time.Duration {
 	}
 	return d
 }
+
+// ParseDurations parses a comma-separated list of durations (e.g. "10s,1m"),
+// each as ParseDuration does, sorted from shortest to longest.
+func ParseDurations(s string) ([]time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var durations []time.Duration
+	for part := range strings.SplitSeq(s, ",") {
+		d, err := ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid duration: %q (must be positive)", part)
+		}
+		durations = append(durations, d)
+	}
+	slices.Sort(durations)
+	return durations, nil
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -119,6 +122,135 @@ func TestShowLineNumbers(t *testing.T) {
 	}
 }
 
+func TestInitialRun(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+
+	// Default: command runs on startup
+	if got := InitialRun(); got != true {
+		t.Errorf("expected InitialRun() true by default, got %v", got)
+	}
+
+	// When no-initial-run is set
+	viper.Set("no-initial-run", true)
+	if got := InitialRun(); got != false {
+		t.Errorf("expected InitialRun() false when no-initial-run=true, got %v", got)
+	}
+
+	// Config file can disable it too
+	viper.Set("no-initial-run", false)
+	viper.Set(KeyInitialRun, false)
+	if got := InitialRun(); got != false {
+		t.Errorf("expected InitialRun() false when initial-run=false, got %v", got)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+
+	if got := Mouse(); got != true {
+		t.Errorf("expected Mouse() true by default, got %v", got)
+	}
+
+	viper.Set("no-mouse", true)
+	if got := Mouse(); got != false {
+		t.Errorf("expected Mouse() false when no-mouse=true, got %v", got)
+	}
+
+	viper.Set("no-mouse", false)
+	viper.Set(KeyMouse, false)
+	if got := Mouse(); got != false {
+		t.Errorf("expected Mouse() false when mouse=false, got %v", got)
+	}
+}
+
+func TestFilePreview(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+
+	if got := FilePreview(); got != true {
+		t.Errorf("expected FilePreview() true by default, got %v", got)
+	}
+
+	viper.Set("no-file-preview", true)
+	if got := FilePreview(); got != false {
+		t.Errorf("expected FilePreview() false when no-file-preview=true, got %v", got)
+	}
+
+	viper.Set("no-file-preview", false)
+	viper.Set(KeyFilePreview, false)
+	if got := FilePreview(); got != false {
+		t.Errorf("expected FilePreview() false when file-preview=false, got %v", got)
+	}
+}
+
+func TestScrollbar(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+
+	if got := Scrollbar(); got != true {
+		t.Errorf("expected Scrollbar() true by default, got %v", got)
+	}
+
+	viper.Set("no-scrollbar", true)
+	if got := Scrollbar(); got != false {
+		t.Errorf("expected Scrollbar() false when no-scrollbar=true, got %v", got)
+	}
+}
+
+func TestKeysAndValue(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+
+	keys := Keys()
+	if !slices.IsSorted(keys) || !slices.Contains(keys, KeyRefresh) || !slices.Contains(keys, KeyMouse) {
+		t.Errorf("expected the sorted schema keys, got %v", keys)
+	}
+
+	if got, ok := Value(KeyShell); !ok || got != DefaultShell() {
+		t.Errorf("expected the default shell, got %v (%v)", got, ok)
+	}
+
+	viper.Set("no-mouse", true)
+	if got, ok := Value(KeyMouse); !ok || got != false {
+		t.Errorf("expected mouse to follow no-mouse, got %v (%v)", got, ok)
+	}
+
+	if _, ok := Value("nonexistent"); ok {
+		t.Error("expected an unknown key to be reported")
+	}
+}
+
+func TestRemoteShell(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("shell", "pwsh", "")
+	Init()
+	BindFlags(flags)
+
+	if got := RemoteShell(flags); got != "sh" {
+		t.Errorf("expected sh when no shell was chosen, got %q", got)
+	}
+
+	_ = flags.Set("shell", "bash")
+	if got := RemoteShell(flags); got != "bash" {
+		t.Errorf("expected the --shell value, got %q", got)
+	}
+}
+
 func TestBindFlags(t *testing.T) {
 	_, cleanup := isolateConfig(t)
 	defer cleanup()
@@ -487,8 +619,15 @@ func TestParseDuration(t *testing.T) {
 		{"0.5h", 30 * time.Minute, false},
 		{"1.5h", 90 * time.Minute, false},
 
+		// Go duration strings combining units
+		{"1h30m", 90 * time.Minute, false},
+		{"2m30s", 150 * time.Second, false},
+		{"1m0.5s", 60500 * time.Millisecond, false},
+
 		// Invalid formats
 		{"abc", 0, true},
+		{"-1h30m", 0, true},
+		{"1h30", 0, true},
 		{"1d", 0, true},  // days not supported
 		{"1w", 0, true},  // weeks not supported
 		{"-1", 0, true},  // negative not supported
@@ -661,6 +800,69 @@ func TestRefreshFromStartFlagOverridesConfig(t *testing.T) {
 	}
 }
 
+func TestEnvIgnoresHookVariables(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	// Set for post-run and on-transition hooks, which may run watchr
+	t.Setenv("WATCHR_EXIT_CODE", "1")
+	t.Setenv("WATCHR_SHELL", "bash")
+
+	Init()
+
+	if got := GetString(KeyExitCode); got != "" {
+		t.Errorf("expected WATCHR_EXIT_CODE not to set exit-code, got %q", got)
+	}
+	if got := GetString(KeyShell); got != "bash" {
+		t.Errorf("expected other keys to still be read from the environment, got shell %q", got)
+	}
+}
+
+func TestEnvOverridesConfig(t *testing.T) {
+	tmpDir, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	configPath := filepath.Join(tmpDir, "watchr.yaml")
+	configContent := `shell: zsh
+refresh: 5s
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("WATCHR_REFRESH", "2s")
+	t.Setenv("WATCHR_PREVIEW_SIZE", "30%")
+	t.Setenv("WATCHR_THEME_NAME", "light")
+	t.Setenv("WATCHR_NO_TITLE", "true")
+
+	Init()
+
+	if got := GetString(KeyShell); got != "zsh" {
+		t.Errorf("expected shell 'zsh' from the config file, got %q", got)
+	}
+	if got := GetDuration(KeyRefresh); got != 2*time.Second {
+		t.Errorf("expected refresh 2s from the environment, got %v", got)
+	}
+	if got := GetString(KeyPreviewSize); got != "30%" {
+		t.Errorf("expected preview-size '30%%' from the environment, got %q", got)
+	}
+	if got := GetString(KeyTheme); got != "light" {
+		t.Errorf("expected theme 'light' from the environment, got %q", got)
+	}
+	if Title() {
+		t.Error("expected WATCHR_NO_TITLE to hide the title")
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("refresh", "0", "")
+	if err := flags.Parse([]string{"--refresh=1s"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	BindFlags(flags)
+	if got := GetDuration(KeyRefresh); got != time.Second {
+		t.Errorf("expected the flag to override the environment, got %v", got)
+	}
+}
+
 func TestRefreshDurationFromConfigFile(t *testing.T) {
 	tmpDir, cleanup := isolateConfig(t)
 	defer cleanup()
@@ -715,3 +917,227 @@ func TestRefreshDurationFromConfigFile(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDurations(t *testing.T) {
+	got, err := ParseDurations("1m, 10s,5m")
+	want := []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("ParseDurations = %v, %v; want %v", got, err, want)
+	}
+	if got, err := ParseDurations(""); err != nil || got != nil {
+		t.Errorf("expected no durations for an empty list, got %v, %v", got, err)
+	}
+	for _, input := range []string{"10s,abc", "0,1m", "1m,"} {
+		if _, err := ParseDurations(input); err == nil {
+			t.Errorf("ParseDurations(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestApplyPreset(t *testing.T) {
+	tmpDir, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	configPath := filepath.Join(tmpDir, "watchr.yaml")
+	configContent := `shell: zsh
+refresh: 10s
+presets:
+  pods:
+    command: kubectl get pods
+    refresh: 5s
+    preview-command: kubectl describe pod {1}
+  disk:
+    command: df -h
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	Init()
+
+	if names := PresetNames(); !slices.Equal(names, []string{"disk", "pods"}) {
+		t.Errorf("expected presets [disk pods], got %v", names)
+	}
+	if _, err := ApplyPreset("nope"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("preview", "", "")
+	if err := flags.Parse([]string{"--preview=cat {}"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	BindFlags(flags)
+
+	command, err := ApplyPreset("pods")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if command != "kubectl get pods" {
+		t.Errorf("expected the preset's command, got %q", command)
+	}
+	if got := GetDuration(KeyRefresh); got != 5*time.Second {
+		t.Errorf("expected the preset's refresh to override the config file, got %v", got)
+	}
+	if got := GetString(KeyShell); got != "zsh" {
+		t.Errorf("expected options the preset doesn't set to be kept, got shell %q", got)
+	}
+	if got := GetString(KeyPreviewCommand); got != "cat {}" {
+		t.Errorf("expected the flag to override the preset, got %q", got)
+	}
+}
+
+func TestConfigIncludes(t *testing.T) {
+	tmpDir, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	files := map[string]string{
+		"watchr.yaml": `include: [team/base.yaml, extra.yaml]
+shell: zsh
+`,
+		"team/base.yaml": `include: [colors.yaml]
+shell: bash
+refresh: 10s
+theme:
+  name: light
+`,
+		"team/colors.yaml": `refresh: 1m
+theme:
+  accent: '212'
+`,
+		"extra.yaml": `refresh: 30s
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+	}
+
+	if err := Init(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetString(KeyShell); got != "zsh" {
+		t.Errorf("expected the including file to override its includes, got shell %q", got)
+	}
+	if got := GetDuration(KeyRefresh); got != 30*time.Second {
+		t.Errorf("expected later includes to override earlier ones, got refresh %v", got)
+	}
+	if got := GetString(KeyTheme); got != "light" {
+		t.Errorf("expected the theme from the base config, got %q", got)
+	}
+	if got := ThemeColors()["accent"]; got != "212" {
+		t.Errorf("expected nested includes to be read, got accent %q", got)
+	}
+
+	// A file including one that includes it back is a cycle
+	cycle := filepath.Join(tmpDir, "team/colors.yaml")
+	if err := os.WriteFile(cycle, []byte("include: [base.yaml]\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	resetViper()
+	if err := Init(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected an include cycle error, got %v", err)
+	}
+}
+
+func TestBindings(t *testing.T) {
+	_, cleanup := isolateConfig(t)
+	defer cleanup()
+
+	Init()
+	viper.Set(KeyBind, map[string]string{"ctrl-o": "execute(less {})", "ctrl-l": "pager(cat {})"})
+
+	bindings, err := Bindings([]string{"ctrl-o:execute(vim {1})", "alt-r:reload(ls {})"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"ctrl-o": "execute(vim {1})",
+		"ctrl-l": "pager(cat {})",
+		"alt-r":  "reload(ls {})",
+	}
+	if len(bindings) != len(want) {
+		t.Errorf("expected %d bindings, got %v", len(want), bindings)
+	}
+	for key, action := range want {
+		if bindings[key] != action {
+			t.Errorf("%s: expected %q, got %q", key, action, bindings[key])
+		}
+	}
+
+	if _, err := Bindings([]string{"execute(ls)"}); err == nil {
+		t.Error("expected an error for a binding without a key")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	var schema struct {
+		Properties  map[string]json.RawMessage `json:"properties"`
+		Definitions struct {
+			Options struct {
+				Properties map[string]struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"properties"`
+			} `json:"options"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	resetViper()
+	setDefaults()
+	options := schema.Definitions.Options.Properties
+	for _, key := range viper.AllKeys() {
+		section, name, nested := strings.Cut(key, ".")
+		if _, ok := schema.Properties[key]; ok {
+			continue
+		}
+		if _, ok := options[section]; !ok {
+			t.Errorf("schema is missing %q", key)
+		} else if _, ok := options[section].Properties[name]; nested && !ok {
+			t.Errorf("schema is missing %q", key)
+		}
+	}
+	for _, key := range []string{KeyInclude, KeyPresets} {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("schema is missing %q", key)
+		}
+	}
+	for _, key := range []string{KeyBind, KeyThemeColors} {
+		if _, ok := options[key]; !ok {
+			t.Errorf("schema is missing %q", key)
+		}
+	}
+}
+
+func TestDefaultShell(t *testing.T) {
+	found := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(names, name) {
+				return `C:\bin\` + name + ".exe", nil
+			}
+			return "", os.ErrNotExist
+		}
+	}
+	tests := []struct {
+		goos     string
+		lookPath func(string) (string, error)
+		want     string
+	}{
+		{"linux", found("pwsh"), "sh"},
+		{"darwin", found(), "sh"},
+		{"windows", found("pwsh", "powershell"), "pwsh"},
+		{"windows", found("powershell"), "powershell"},
+		{"windows", found(), "cmd"},
+	}
+	for _, tt := range tests {
+		if got := defaultShell(tt.goos, tt.lookPath); got != tt.want {
+			t.Errorf("defaultShell(%s) = %q, want %q", tt.goos, got, tt.want)
+		}
+	}
+}
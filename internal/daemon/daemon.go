@@ -0,0 +1,269 @@
+// Package daemon runs a command's refresh loop without a terminal and serves
+// the latest output over a unix socket, so a TUI can attach to it later.
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/anomaly"
+	"github.com/chenasraf/watchr/internal/runlog"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+// ErrRunning is returned when another daemon is already listening on the socket
+var ErrRunning = errors.New("a daemon is already running")
+
+// Snapshot is the output of a single run of the watched command
+type Snapshot struct {
+	Command  string        `json:"command"`
+	Run      int           `json:"run"`
+	ExitCode int           `json:"exit_code"`
+	Lines    []string      `json:"lines"`
+	Time     time.Time     `json:"time"`
+	Interval time.Duration `json:"interval"`
+	Alerts   []string      `json:"alerts,omitempty"` // alert rules the run triggered
+	Error    string        `json:"error,omitempty"`  // set when the command or a hook failed to run
+}
+
+// Dir returns the directory daemons keep their sockets and logs in, or "" if
+// no state directory is available.
+func Dir() string {
+	dir := state.Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "daemons")
+}
+
+// name names a daemon's socket and log by a hash of its command, so daemons
+// watching different commands can run side by side.
+func name(command string) string {
+	sum := sha256.Sum256([]byte(command))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SocketPath returns the socket path of the daemon watching command, or "" if
+// no state directory is available.
+func SocketPath(command string) string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name(command)+".sock")
+}
+
+// LogPath returns the snapshot log path of the daemon watching command, or ""
+// if no state directory is available.
+func LogPath(command string) string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name(command)+".log")
+}
+
+// Find returns the socket path of the daemon watching command or, if command
+// is empty, of the only daemon running in dir.
+func Find(dir, command string) (string, error) {
+	if command != "" {
+		return filepath.Join(dir, name(command)+".sock"), nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.sock"))
+	var running, commands []string
+	for _, path := range paths {
+		if snap, err := Fetch(path); err == nil {
+			running = append(running, path)
+			commands = append(commands, strconv.Quote(snap.Command))
+		}
+	}
+	switch len(running) {
+	case 0:
+		return "", errors.New("no daemon running")
+	case 1:
+		return running[0], nil
+	default:
+		return "", fmt.Errorf("%d daemons are running, name the command to pick one: %s", len(running), strings.Join(commands, ", "))
+	}
+}
+
+// Daemon re-runs a command on an interval and keeps its latest snapshot
+type Daemon struct {
+	Runner       *runner.Runner
+	Interval     time.Duration
+	Socket       string
+	Log          io.Writer // if set, every snapshot is appended as a JSON line
+	LogDir       string    // if set, every run is also saved to its own file here (see runlog)
+	OnTransition string    // hook run when the command starts failing or passes again
+
+	mu           sync.Mutex
+	latest       Snapshot
+	lineCounts   anomaly.Stats // line counts of recent runs, for anomaly alerts
+	finishedRuns int
+	lastExitCode int
+}
+
+// Run listens on the socket and runs the refresh loop until ctx is cancelled.
+func (d *Daemon) Run(ctx context.Context) error {
+	if d.Interval <= 0 {
+		return errors.New("daemon requires a refresh interval")
+	}
+	ln, err := listen(d.Socket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ln.Close() }()
+	d.latest = Snapshot{Command: d.Runner.Command, Interval: d.Interval}
+	go d.serve(ln)
+
+	for run := 1; ; run++ {
+		if err := d.runOnce(ctx, run); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(d.Interval):
+		}
+	}
+}
+
+// Latest returns the most recent snapshot.
+func (d *Daemon) Latest() Snapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.latest
+}
+
+func (d *Daemon) runOnce(ctx context.Context, run int) error {
+	start := time.Now()
+	// Command and hook failures are recorded in the snapshot rather than
+	// stopping the daemon
+	result, runErr := d.Runner.Run(ctx)
+
+	lines := make([]string, len(result.Lines))
+	for i, line := range result.Lines {
+		lines[i] = line.Content
+	}
+	snap := Snapshot{
+		Command:  d.Runner.Command,
+		Run:      run,
+		ExitCode: result.ExitCode,
+		Lines:    lines,
+		Time:     time.Now(),
+		Interval: d.Interval,
+	}
+	if ctx.Err() == nil {
+		snap.Alerts = d.alerts(ctx, snap)
+	}
+	if runErr != nil {
+		snap.Error = runErr.Error()
+	}
+
+	if d.Log != nil {
+		if err := json.NewEncoder(d.Log).Encode(snap); err != nil {
+			return fmt.Errorf("failed to log snapshot: %w", err)
+		}
+	}
+	if d.LogDir != "" {
+		_, err := runlog.Write(d.LogDir, runlog.Run{
+			Command:  snap.Command,
+			Start:    start,
+			Duration: snap.Time.Sub(start),
+			ExitCode: snap.ExitCode,
+			Lines:    snap.Lines,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to log output: %w", err)
+		}
+	}
+
+	d.mu.Lock()
+	d.latest = snap
+	d.mu.Unlock()
+	return nil
+}
+
+// alerts evaluates the alert rules against a finished run: a line count far
+// off the recent average, and the command starting to fail or passing again,
+// which also runs the OnTransition hook.
+func (d *Daemon) alerts(ctx context.Context, snap Snapshot) []string {
+	var alerts []string
+	if anomalous, mean := d.lineCounts.Observe(len(snap.Lines)); anomalous {
+		alerts = append(alerts, fmt.Sprintf("line count anomaly: %d lines, usually ~%.0f", len(snap.Lines), mean))
+	}
+
+	prev, hadRun := d.lastExitCode, d.finishedRuns > 0
+	d.finishedRuns++
+	d.lastExitCode = snap.ExitCode
+	if !hadRun || (prev == 0) == (snap.ExitCode == 0) {
+		return alerts
+	}
+	if snap.ExitCode == 0 {
+		alerts = append(alerts, "command is passing again")
+	} else {
+		alerts = append(alerts, fmt.Sprintf("command started failing (exit %d)", snap.ExitCode))
+	}
+	if err := d.Runner.RunTransitionHook(ctx, d.OnTransition, prev, snap.ExitCode); err != nil {
+		alerts = append(alerts, err.Error())
+	}
+	return alerts
+}
+
+// serve writes the latest snapshot to every connection and closes it
+func (d *Daemon) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(d.Latest())
+		_ = conn.Close()
+	}
+}
+
+// listen opens the unix socket, replacing a stale socket left by a daemon
+// that did not shut down cleanly.
+func listen(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("no socket path available")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil, ErrRunning
+		}
+		_ = os.Remove(path)
+	}
+	return net.Listen("unix", path)
+}
+
+// Fetch returns the latest snapshot from the daemon listening on path.
+func Fetch(path string) (Snapshot, error) {
+	var snap Snapshot
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return snap, fmt.Errorf("no daemon running: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if err := json.NewDecoder(conn).Decode(&snap); err != nil {
+		return snap, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return snap, nil
+}
@@ -0,0 +1,236 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/anomaly"
+	"github.com/chenasraf/watchr/internal/runlog"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// socketPath returns a short socket path; unix socket paths are length-limited
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "watchr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func startDaemon(t *testing.T, d *Daemon) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitForRun(t *testing.T, path string) Snapshot {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if snap, err := Fetch(path); err == nil && snap.Run > 0 {
+			return snap
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("daemon did not produce a snapshot")
+	return Snapshot{}
+}
+
+func TestDaemonServesSnapshot(t *testing.T) {
+	path := socketPath(t)
+	var log bytes.Buffer
+	d := &Daemon{
+		Runner:   runner.NewRunner("sh", "echo hello; echo world; exit 3"),
+		Interval: time.Hour,
+		Socket:   path,
+		Log:      &log,
+	}
+	startDaemon(t, d)
+
+	snap := waitForRun(t, path)
+	if len(snap.Lines) != 2 || snap.Lines[0] != "hello" || snap.Lines[1] != "world" {
+		t.Errorf("unexpected lines: %v", snap.Lines)
+	}
+	if snap.ExitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", snap.ExitCode)
+	}
+	if snap.Interval != time.Hour {
+		t.Errorf("expected interval to be reported, got %v", snap.Interval)
+	}
+	if !bytes.Contains(log.Bytes(), []byte(`"hello"`)) {
+		t.Errorf("expected snapshot to be logged, got %q", log.String())
+	}
+}
+
+func TestDaemonAlreadyRunning(t *testing.T) {
+	path := socketPath(t)
+	startDaemon(t, &Daemon{Runner: runner.NewRunner("sh", "true"), Interval: time.Hour, Socket: path})
+	waitForRun(t, path)
+
+	d := &Daemon{Runner: runner.NewRunner("sh", "true"), Interval: time.Hour, Socket: path}
+	if err := d.Run(context.Background()); !errors.Is(err, ErrRunning) {
+		t.Errorf("expected ErrRunning, got %v", err)
+	}
+}
+
+func TestDaemonReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	startDaemon(t, &Daemon{Runner: runner.NewRunner("sh", "echo ok"), Interval: time.Hour, Socket: path})
+	if snap := waitForRun(t, path); len(snap.Lines) != 1 {
+		t.Errorf("expected 1 line, got %v", snap.Lines)
+	}
+}
+
+func TestDaemonRequiresInterval(t *testing.T) {
+	d := &Daemon{Runner: runner.NewRunner("sh", "true"), Socket: socketPath(t)}
+	if err := d.Run(context.Background()); err == nil {
+		t.Error("expected error without a refresh interval")
+	}
+}
+
+func TestFetchNoDaemon(t *testing.T) {
+	if _, err := Fetch(socketPath(t)); err == nil {
+		t.Error("expected error when no daemon is running")
+	}
+}
+
+func TestSocketPathPerCommand(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if SocketPath("ps aux") == SocketPath("df -h") {
+		t.Error("expected daemons watching different commands to use different sockets")
+	}
+	if filepath.Dir(SocketPath("ps aux")) != Dir() || filepath.Dir(LogPath("ps aux")) != Dir() {
+		t.Errorf("expected the socket and log in %s, got %s and %s", Dir(), SocketPath("ps aux"), LogPath("ps aux"))
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir, err := os.MkdirTemp("", "watchr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	if _, err := Find(dir, ""); err == nil {
+		t.Error("expected an error with no daemon running")
+	}
+
+	first := filepath.Join(dir, name("echo one")+".sock")
+	startDaemon(t, &Daemon{Runner: runner.NewRunner("sh", "echo one"), Interval: time.Hour, Socket: first})
+	waitForRun(t, first)
+	if got, err := Find(dir, ""); err != nil || got != first {
+		t.Errorf("expected the only daemon running, got %q (err %v)", got, err)
+	}
+
+	second := filepath.Join(dir, name("echo two")+".sock")
+	startDaemon(t, &Daemon{Runner: runner.NewRunner("sh", "echo two"), Interval: time.Hour, Socket: second})
+	waitForRun(t, second)
+	if _, err := Find(dir, ""); err == nil || !strings.Contains(err.Error(), `"echo two"`) {
+		t.Errorf("expected an error naming the running daemons, got %v", err)
+	}
+	if got, err := Find(dir, "echo two"); err != nil || got != second {
+		t.Errorf("expected the daemon watching the command, got %q (err %v)", got, err)
+	}
+}
+
+func TestLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemons", "d.log")
+	log, err := OpenLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = log.Close() }()
+	log.maxSize = 10
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := log.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != "third\n" {
+		t.Errorf("expected the log to restart after rotating, got %q", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "second\n" {
+		t.Errorf("expected only the previous log to be kept, got %q", data)
+	}
+}
+
+func TestDaemonAlerts(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "failing")
+	hookOut := filepath.Join(dir, "hook")
+	d := &Daemon{
+		Runner:       runner.NewRunner("sh", fmt.Sprintf("test ! -e %s", marker)),
+		Interval:     time.Hour,
+		OnTransition: fmt.Sprintf("echo $WATCHR_TRANSITION $WATCHR_PREV_EXIT_CODE >> %s", hookOut),
+	}
+	ctx := context.Background()
+
+	if err := d.runOnce(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if alerts := d.Latest().Alerts; len(alerts) != 0 {
+		t.Errorf("expected no alerts for the first run, got %v", alerts)
+	}
+
+	if err := os.WriteFile(marker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.runOnce(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if alerts := d.Latest().Alerts; len(alerts) != 1 || !strings.Contains(alerts[0], "started failing") {
+		t.Errorf("expected a failing alert, got %v", alerts)
+	}
+	if out, _ := os.ReadFile(hookOut); string(out) != "fail 0\n" {
+		t.Errorf("expected the on-transition hook to run, got %q", out)
+	}
+
+	for run := 3; run < 3+anomaly.MinSamples; run++ {
+		if err := d.runOnce(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.Runner.Command = "seq 100"
+	if err := d.runOnce(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	alerts := d.Latest().Alerts
+	if len(alerts) != 2 || !strings.Contains(alerts[0], "anomaly: 100 lines") || !strings.Contains(alerts[1], "passing again") {
+		t.Errorf("expected anomaly and passing alerts, got %v", alerts)
+	}
+}
+
+func TestDaemonLogDir(t *testing.T) {
+	path := socketPath(t)
+	logDir := t.TempDir()
+	startDaemon(t, &Daemon{Runner: runner.NewRunner("sh", "echo logged"), Interval: time.Hour, Socket: path, LogDir: logDir})
+	waitForRun(t, path)
+
+	paths, err := runlog.List(logDir)
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("expected 1 logged run, got %v (err %v)", paths, err)
+	}
+	run, err := runlog.Read(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(run.Lines) != 1 || run.Lines[0] != "logged" {
+		t.Errorf("expected [logged], got %q", run.Lines)
+	}
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// MaxLogSize is the size past which a snapshot log is rotated: it's renamed
+// with a .1 suffix, replacing the previous one, and a new log is started.
+const MaxLogSize = 10 << 20
+
+// Log is a snapshot log file that rotates itself at MaxLogSize, so a daemon
+// left running for days keeps at most two logs' worth of snapshots.
+type Log struct {
+	path    string
+	file    *os.File
+	size    int64
+	maxSize int64
+}
+
+// OpenLog opens the snapshot log at path for appending, creating it and its
+// directory if needed.
+func OpenLog(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	l := &Log{path: path, maxSize: MaxLogSize}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Write appends p to the log, rotating it first if p would take it past its
+// maximum size. A single write is never split across logs.
+func (l *Log) Write(p []byte) (int, error) {
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// rotate moves the log aside to path.1 and starts a new one.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	return l.file.Close()
+}
@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+)
+
+// corpus returns a deterministic set of log- and path-like lines shared by all
+// matcher benchmarks, so algorithms are compared on the same input.
+func corpus() []string {
+	levels := []string{"INFO", "WARN", "ERROR", "DEBUG"}
+	dirs := []string{"internal/ui", "internal/runner", "internal/config", "cmd"}
+	lines := make([]string, 0, 10000)
+	for i := range 5000 {
+		lines = append(lines,
+			fmt.Sprintf("2026-01-02T15:04:%02dZ %-5s request id=%d path=/api/v1/items/%d took=%dms", i%60, levels[i%len(levels)], i, i*7, i%500),
+			fmt.Sprintf("./%s/file_%d_test.go", dirs[i%len(dirs)], i),
+		)
+	}
+	return lines
+}
+
+// benchPatterns holds a representative pattern for each matcher kind
+var benchPatterns = map[Kind]string{
+	Substring: "error",
+	Regex:     `ERROR.*took=\d{3}ms`,
+	Fuzzy:     "errtook",
+	Glob:      "internal/**/*_test.go",
+}
+
+func TestCorpusMatches(t *testing.T) {
+	lines := corpus()
+	for _, kind := range Kinds {
+		m, err := New(kind, benchPatterns[kind])
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", kind, err)
+		}
+		n := 0
+		for _, line := range lines {
+			if m.Match(line) {
+				n++
+			}
+		}
+		if n == 0 || n == len(lines) {
+			t.Errorf("%s pattern %q matched %d of %d lines; expected a selective match", kind, benchPatterns[kind], n, len(lines))
+		}
+	}
+}
+
+func BenchmarkMatchers(b *testing.B) {
+	lines := corpus()
+	for _, kind := range Kinds {
+		b.Run(string(kind), func(b *testing.B) {
+			m, err := New(kind, benchPatterns[kind])
+			if err != nil {
+				b.Fatalf("New(%q) returned error: %v", kind, err)
+			}
+			b.ResetTimer()
+			for range b.N {
+				for _, line := range lines {
+					m.Match(line)
+				}
+			}
+		})
+	}
+}
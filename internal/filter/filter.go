@@ -0,0 +1,156 @@
+// Package filter provides the line matching algorithms used by the filter
+// prompt. Each algorithm implements Matcher, so new ones can be added and
+// benchmarked against the shared corpus without touching the UI.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Kind identifies a matching algorithm
+type Kind string
+
+const (
+	Substring Kind = "substring"
+	Regex     Kind = "regex"
+	Fuzzy     Kind = "fuzzy"
+	Glob      Kind = "glob"
+)
+
+// Kinds lists every available matching algorithm
+var Kinds = []Kind{Substring, Regex, Fuzzy, Glob}
+
+// Case controls how a pattern's letter case is matched
+type Case string
+
+const (
+	IgnoreCase    Case = "ignore"
+	SmartCase     Case = "smart" // case-sensitive only if the pattern has an uppercase letter
+	SensitiveCase Case = "sensitive"
+)
+
+// Cases lists every case mode, in the order the UI cycles through them
+var Cases = []Case{IgnoreCase, SmartCase, SensitiveCase}
+
+// Sensitive reports whether pattern is matched case-sensitively in mode c.
+func (c Case) Sensitive(pattern string) bool {
+	switch c {
+	case SensitiveCase:
+		return true
+	case SmartCase:
+		return strings.IndexFunc(pattern, unicode.IsUpper) >= 0
+	default:
+		return false
+	}
+}
+
+// Matcher reports whether a line matches a compiled pattern
+type Matcher interface {
+	Match(line string) bool
+}
+
+// Locator is implemented by matchers that can report where a line matches,
+// for highlighting. Ranges are [start, end) byte offsets into line, in order.
+type Locator interface {
+	Locate(line string) [][]int
+}
+
+// Scorer is implemented by matchers that rank their matches, so the best
+// matches can be listed first. Higher scores are better.
+type Scorer interface {
+	Matcher
+	Score(line string) (score int, ok bool)
+}
+
+// New compiles pattern into a case-insensitive Matcher of the given kind.
+// An empty pattern matches every line.
+func New(kind Kind, pattern string) (Matcher, error) {
+	return NewCase(kind, pattern, IgnoreCase)
+}
+
+// NewCase is like New, matching letter case according to c.
+func NewCase(kind Kind, pattern string, c Case) (Matcher, error) {
+	if pattern == "" {
+		return matchAll{}, nil
+	}
+	sensitive := c.Sensitive(pattern)
+	switch kind {
+	case Substring:
+		return newSubstring(pattern, sensitive), nil
+	case Regex:
+		return newRegex(pattern, sensitive)
+	case Fuzzy:
+		return newFuzzy(pattern, sensitive), nil
+	case Glob:
+		return newGlob(pattern, sensitive)
+	default:
+		return nil, fmt.Errorf("unknown matcher: %q", kind)
+	}
+}
+
+// matchAll matches every line (used for empty patterns)
+type matchAll struct{}
+
+func (matchAll) Match(string) bool { return true }
+
+// substringMatcher matches lines containing the pattern
+type substringMatcher struct {
+	pattern   string
+	sensitive bool
+	re        *regexp.Regexp // for Locate, which needs offsets into the original line
+}
+
+func newSubstring(pattern string, sensitive bool) substringMatcher {
+	if sensitive {
+		return substringMatcher{pattern: pattern, sensitive: true, re: regexp.MustCompile(regexp.QuoteMeta(pattern))}
+	}
+	return substringMatcher{
+		pattern: strings.ToLower(pattern),
+		re:      regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern)),
+	}
+}
+
+func (m substringMatcher) Match(line string) bool {
+	if m.sensitive {
+		return strings.Contains(line, m.pattern)
+	}
+	return strings.Contains(strings.ToLower(line), m.pattern)
+}
+
+func (m substringMatcher) Locate(line string) [][]int {
+	return m.re.FindAllStringIndex(line, -1)
+}
+
+// regexMatcher matches lines against a regular expression
+type regexMatcher struct {
+	re *regexp.Regexp
+}
+
+func newRegex(pattern string, sensitive bool) (regexMatcher, error) {
+	if !sensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return regexMatcher{}, err
+	}
+	return regexMatcher{re: re}, nil
+}
+
+func (m regexMatcher) Match(line string) bool {
+	return m.re.MatchString(line)
+}
+
+// Locate skips empty matches (e.g. of "x*"), which have nothing to highlight
+func (m regexMatcher) Locate(line string) [][]int {
+	var ranges [][]int
+	for _, r := range m.re.FindAllStringIndex(line, -1) {
+		if r[1] > r[0] {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
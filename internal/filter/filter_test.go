@@ -0,0 +1,168 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, kind := range Kinds {
+		m, err := New(kind, "")
+		if err != nil {
+			t.Fatalf("New(%q, \"\") returned error: %v", kind, err)
+		}
+		if !m.Match("anything") {
+			t.Errorf("expected empty %s pattern to match every line", kind)
+		}
+	}
+
+	if _, err := New("nope", "x"); err == nil {
+		t.Error("expected error for unknown matcher kind")
+	}
+	if _, err := New(Regex, "[invalid"); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		kind    Kind
+		pattern string
+		line    string
+		want    bool
+	}{
+		{Substring, "hello", "Hello World", true},
+		{Substring, "WORLD", "hello world", true},
+		{Substring, "xyz", "hello world", false},
+
+		{Regex, "^hel+o", "Hello world", true},
+		{Regex, `\d{3}`, "code 404", true},
+		{Regex, `^\d+$`, "code 404", false},
+
+		{Fuzzy, "wtr", "watchr", true},
+		{Fuzzy, "WCH", "watchr", true},
+		{Fuzzy, "rw", "watchr", false},
+		{Fuzzy, "héo", "hélloo", true},
+		{Fuzzy, "kblt pd", "kubelet pod ready", true},
+		{Fuzzy, "pd kblt", "kubelet pod ready", true},
+		{Fuzzy, "kblt xyz", "kubelet pod ready", false},
+
+		{Glob, "*.go", "main.go", true},
+		{Glob, "*.go", "./internal/ui/view.go", true},
+		{Glob, "*.go", "go.mod", false},
+		{Glob, "*_test.go", "internal/ui/view_test.go", true},
+		{Glob, "internal/*.go", "internal/ui/view.go", false},
+		{Glob, "internal/**/*.go", "internal/ui/view.go", true},
+		{Glob, "internal/**/*.go", "internal/main.go", true},
+		{Glob, "pkg/**/*_test.go", "pkg/a/b/c_test.go", true},
+		{Glob, "file?.txt", "file1.txt", true},
+		{Glob, "file?.txt", "file10.txt", false},
+		{Glob, "[ab]*.txt", "b.txt", true},
+		{Glob, "[!ab]*.txt", "b.txt", false},
+		{Glob, "a+b.txt", "a+b.txt", true},
+	}
+
+	for _, tt := range tests {
+		m, err := New(tt.kind, tt.pattern)
+		if err != nil {
+			t.Fatalf("New(%q, %q) returned error: %v", tt.kind, tt.pattern, err)
+		}
+		if got := m.Match(tt.line); got != tt.want {
+			t.Errorf("%s %q Match(%q) = %v, want %v", tt.kind, tt.pattern, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	m, _ := New(Fuzzy, "pod")
+	scorer, ok := m.(Scorer)
+	if !ok {
+		t.Fatal("expected the fuzzy matcher to rank matches")
+	}
+
+	tests := []struct{ better, worse string }{
+		{"kube-pod ready", "kubepod ready"},          // word start beats mid-word
+		{"kubepod ready", "pending on disk space"},   // adjacent beats spread out
+		{"pod on disk", "pending on disk"},           // the tightest window is scored
+		{"pending on disk", "p          o        d"}, // shorter gaps beat longer ones
+	}
+	for _, tt := range tests {
+		better, ok1 := scorer.Score(tt.better)
+		worse, ok2 := scorer.Score(tt.worse)
+		if !ok1 || !ok2 {
+			t.Fatalf("expected %q and %q to match", tt.better, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("expected %q (%d) to score above %q (%d)", tt.better, better, tt.worse, worse)
+		}
+	}
+
+	if _, ok := scorer.Score("nothing here"); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		kind    Kind
+		pattern string
+		line    string
+		want    [][]int
+	}{
+		{Substring, "foo", "Foo bar foo", [][]int{{0, 3}, {8, 11}}},
+		{Substring, "a.b", "axb a.b", [][]int{{4, 7}}},
+		{Regex, `\d+`, "code 404 at 12", [][]int{{5, 8}, {12, 14}}},
+		{Regex, `x*`, "abc", nil},
+		{Fuzzy, "wtr", "watchr", [][]int{{0, 1}, {2, 3}, {5, 6}}},
+		{Fuzzy, "pod ku", "kubelet pod", [][]int{{0, 2}, {8, 11}}},
+		{Fuzzy, "éo", "héllo", [][]int{{1, 3}, {5, 6}}},
+	}
+	for _, tt := range tests {
+		m, err := New(tt.kind, tt.pattern)
+		if err != nil {
+			t.Fatalf("New(%q, %q): %v", tt.kind, tt.pattern, err)
+		}
+		got := m.(Locator).Locate(tt.line)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s %q Locate(%q) = %v, want %v", tt.kind, tt.pattern, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestNewCase(t *testing.T) {
+	tests := []struct {
+		kind    Kind
+		pattern string
+		c       Case
+		line    string
+		want    bool
+	}{
+		{Substring, "error", SmartCase, "ERROR: x", true},
+		{Substring, "Error", SmartCase, "ERROR: x", false},
+		{Substring, "Error", SmartCase, "Error: x", true},
+		{Substring, "error", SensitiveCase, "ERROR: x", false},
+		{Substring, "Error", IgnoreCase, "ERROR: x", true},
+		{Regex, "^Err", SmartCase, "error", false},
+		{Regex, "^err", SmartCase, "Error", true},
+		{Glob, "*.Go", SmartCase, "main.go", false},
+		{Glob, "*.go", SensitiveCase, "main.GO", false},
+		{Fuzzy, "kP", SmartCase, "kubelet pod", false},
+		{Fuzzy, "kP", SmartCase, "kubelet Pod", true},
+		{Fuzzy, "kp", SensitiveCase, "Kubelet pod", false},
+	}
+	for _, tt := range tests {
+		m, err := NewCase(tt.kind, tt.pattern, tt.c)
+		if err != nil {
+			t.Fatalf("NewCase(%q, %q, %q): %v", tt.kind, tt.pattern, tt.c, err)
+		}
+		if got := m.Match(tt.line); got != tt.want {
+			t.Errorf("%s %q (%s) Match(%q) = %v, want %v", tt.kind, tt.pattern, tt.c, tt.line, got, tt.want)
+		}
+	}
+
+	// Locate follows the case mode too
+	m, _ := NewCase(Substring, "Foo", SensitiveCase)
+	if got := fmt.Sprint(m.(Locator).Locate("foo Foo")); got != "[[4 7]]" {
+		t.Errorf("expected only the exact-case match, got %s", got)
+	}
+}
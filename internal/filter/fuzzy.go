@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fuzzy scoring weights, loosely following fzf: every matched character
+// scores, with bonuses for runs of adjacent matches and for matches at the
+// start of a word, and a penalty for each gap between matched characters.
+const (
+	scoreMatch          = 16
+	bonusConsecutive    = 8
+	bonusBoundary       = 8
+	penaltyGapStart     = 3
+	penaltyGapExtension = 1
+)
+
+// fuzzyMatcher matches lines containing each space-separated term's
+// characters in order, not necessarily adjacent (e.g. "wtr" matches "watchr",
+// "kblt pd" matches "kubelet pod"). Terms may match in any order.
+type fuzzyMatcher struct {
+	terms     [][]rune
+	sensitive bool
+}
+
+func newFuzzy(pattern string, sensitive bool) fuzzyMatcher {
+	if !sensitive {
+		pattern = strings.ToLower(pattern)
+	}
+	var terms [][]rune
+	for term := range strings.FieldsSeq(pattern) {
+		terms = append(terms, []rune(term))
+	}
+	return fuzzyMatcher{terms: terms, sensitive: sensitive}
+}
+
+// fold prepares a line for matching against the terms
+func (m fuzzyMatcher) fold(line string) []rune {
+	if m.sensitive {
+		return []rune(line)
+	}
+	return []rune(strings.ToLower(line))
+}
+
+func (m fuzzyMatcher) Match(line string) bool {
+	_, ok := m.Score(line)
+	return ok
+}
+
+// Score ranks a matching line: tighter matches, and matches at word starts,
+// score higher.
+func (m fuzzyMatcher) Score(line string) (int, bool) {
+	runes := m.fold(line)
+	total := 0
+	for _, term := range m.terms {
+		score, _, ok := scoreTerm(runes, term)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// Locate returns the matched characters of every term.
+func (m fuzzyMatcher) Locate(line string) [][]int {
+	runes := m.fold(line)
+	matched := make([]bool, len(runes))
+	for _, term := range m.terms {
+		_, positions, ok := scoreTerm(runes, term)
+		if !ok {
+			return nil
+		}
+		for _, p := range positions {
+			matched[p] = true
+		}
+	}
+
+	// Convert rune positions to byte ranges, joining adjacent characters
+	var ranges [][]int
+	i := 0
+	for offset, r := range line {
+		if matched[i] {
+			end := offset + utf8.RuneLen(r)
+			if n := len(ranges); n > 0 && ranges[n-1][1] == offset {
+				ranges[n-1][1] = end
+			} else {
+				ranges = append(ranges, []int{offset, end})
+			}
+		}
+		i++
+	}
+	return ranges
+}
+
+// scoreTerm finds the shortest window of line ending at the earliest complete
+// match of term, and scores the match within it. It also returns the rune
+// positions of the matched characters.
+func scoreTerm(line, term []rune) (int, []int, bool) {
+	// Forward scan: where does the earliest complete match end?
+	end, i := -1, 0
+	for j, r := range line {
+		if r == term[i] {
+			i++
+			if i == len(term) {
+				end = j
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Backward scan from the end: the latest start gives the tightest window
+	start := end
+	i = len(term) - 1
+	for j := end; j >= 0; j-- {
+		if line[j] == term[i] {
+			i--
+			if i < 0 {
+				start = j
+				break
+			}
+		}
+	}
+
+	score, prev := 0, -2
+	positions := make([]int, 0, len(term))
+	i = 0
+	for j := start; j <= end && i < len(term); j++ {
+		if line[j] != term[i] {
+			continue
+		}
+		score += scoreMatch
+		switch {
+		case j == prev+1:
+			score += bonusConsecutive
+		case prev >= 0:
+			score -= penaltyGapStart + (j-prev-2)*penaltyGapExtension
+		}
+		if j == 0 || !isWordRune(line[j-1]) {
+			score += bonusBoundary
+		}
+		positions = append(positions, j)
+		prev = j
+		i++
+	}
+	return score, positions, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// globMatcher matches lines against a shell-style glob such as "*.go" or
+// "pkg/**/*_test.go". "*" and "?" don't cross "/", while "**" does. Patterns
+// without a "/" are matched against the last path segment of the line, so
+// "*.go" matches "internal/ui/view.go".
+type globMatcher struct {
+	re       *regexp.Regexp
+	basename bool
+}
+
+func newGlob(pattern string, sensitive bool) (globMatcher, error) {
+	flags := "(?i)"
+	if sensitive {
+		flags = ""
+	}
+	re, err := regexp.Compile(flags + "^" + globToRegex(pattern) + "$")
+	if err != nil {
+		return globMatcher{}, err
+	}
+	return globMatcher{re: re, basename: !strings.Contains(pattern, "/")}, nil
+}
+
+func (m globMatcher) Match(line string) bool {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "./")
+	if m.basename {
+		line = line[strings.LastIndex(line, "/")+1:]
+	}
+	return m.re.MatchString(line)
+}
+
+// globToRegex translates a glob pattern into an unanchored regular expression.
+func globToRegex(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			// Character classes pass through; "[!...]" is negation in globs
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+// Package i18n looks up the strings watchr shows in its interface, in the
+// language picked with lang. Translations are embedded JSON files mapping a
+// message key to a fmt format string; a key missing from a translation falls
+// back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Default is the language used when none is set, and the fallback for keys a
+// translation leaves out
+const Default = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	fallback = mustLoad(Default)
+	messages = fallback
+)
+
+// Langs returns the names of the embedded translations, sorted.
+func Langs() []string {
+	entries, _ := locales.ReadDir("locales")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetLang switches the interface to lang. An empty lang means Default.
+func SetLang(lang string) error {
+	if lang == "" {
+		lang = Default
+	}
+	if !slices.Contains(Langs(), lang) {
+		return fmt.Errorf("unknown language %q (expected %s)", lang, strings.Join(Langs(), ", "))
+	}
+	m, err := load(lang)
+	if err != nil {
+		return err
+	}
+	messages = m
+	return nil
+}
+
+// T returns the message for key in the current language, formatted with
+// args. An unknown key is returned as is, so a missing string shows up
+// rather than disappearing.
+func T(key string, args ...any) string {
+	format, ok := messages[key]
+	if !ok {
+		if format, ok = fallback[key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func load(lang string) (map[string]string, error) {
+	data, err := locales.ReadFile(path.Join("locales", lang+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("translation %s: %w", lang, err)
+	}
+	return m, nil
+}
+
+func mustLoad(lang string) map[string]string {
+	m, err := load(lang)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	if got := T("status.copied"); got != "Copied to clipboard" {
+		t.Errorf("T(status.copied) = %q", got)
+	}
+	if got := T("status.wrote", 3, "out.txt"); got != "Wrote 3 lines to out.txt" {
+		t.Errorf("T(status.wrote) = %q", got)
+	}
+	if got := T("status.preview_percent", 40); got != "Preview size: 40%" {
+		t.Errorf("T(status.preview_percent) = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key should be returned as is, got %q", got)
+	}
+}
+
+func TestSetLang(t *testing.T) {
+	if !slices.Contains(Langs(), Default) {
+		t.Fatalf("Langs() = %v, missing %s", Langs(), Default)
+	}
+	if err := SetLang(""); err != nil {
+		t.Errorf("empty lang should select the default: %v", err)
+	}
+	if err := SetLang("xx"); err == nil {
+		t.Error("expected an error for an unknown language")
+	}
+	if got := T("bar.help"); got != "h for help" {
+		t.Errorf("a failed SetLang should keep the current language, got %q", got)
+	}
+}
+
+// TestKeys checks that every message key the interface uses has an English
+// string, and that English has no strings nothing uses.
+func TestKeys(t *testing.T) {
+	files, err := filepath.Glob("../ui/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRe := regexp.MustCompile(`"((?:bar|columns|confirm|error|help|input|palette|preview|registers|status|time|view)\.[a-z_]+)"`)
+	used := map[string]bool{}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range keyRe.FindAllStringSubmatch(string(data), -1) {
+			used[m[1]] = true
+		}
+	}
+	if len(used) == 0 {
+		t.Fatal("found no message keys in the ui package")
+	}
+	for key := range used {
+		if _, ok := fallback[key]; !ok {
+			t.Errorf("key %q has no English string", key)
+		}
+	}
+	for key := range fallback {
+		if !used[key] {
+			t.Errorf("English string %q is not used", key)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package runlog records the output of each run to a directory as an audit
+// trail, and reads the recorded runs back.
+package runlog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fileTimeFormat names run files so they sort chronologically
+const fileTimeFormat = "20060102-150405.000"
+
+// Run is the recorded output of a single run
+type Run struct {
+	Command  string        `json:"command"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"` // nanoseconds
+	ExitCode int           `json:"exit_code"`
+	Lines    []string      `json:"lines"`
+}
+
+// Write saves run to a new file in dir and returns its path. The file starts
+// with a "# key: value" header followed by a blank line and the output.
+func Write(dir string, run Run) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# command: %s\n", run.Command)
+	fmt.Fprintf(&b, "# started: %s\n", run.Start.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "# duration: %s\n", run.Duration)
+	fmt.Fprintf(&b, "# exit code: %d\n", run.ExitCode)
+	b.WriteString("\n")
+	for _, line := range run.Lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	path := filepath.Join(dir, "run-"+run.Start.Format(fileTimeFormat)+".log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Read loads a run written by Write.
+func Read(path string) (Run, error) {
+	var run Run
+	f, err := os.Open(path)
+	if err != nil {
+		return run, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	inHeader := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !inHeader {
+			run.Lines = append(run.Lines, line)
+			continue
+		}
+		if line == "" {
+			inHeader = false
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "# "), ": ")
+		if !ok || !strings.HasPrefix(line, "# ") {
+			return run, fmt.Errorf("%s: invalid header line %q", path, line)
+		}
+		switch key {
+		case "command":
+			run.Command = value
+		case "started":
+			run.Start, err = time.Parse(time.RFC3339Nano, value)
+		case "duration":
+			run.Duration, err = time.ParseDuration(value)
+		case "exit code":
+			run.ExitCode, err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return run, fmt.Errorf("%s: invalid %s: %w", path, key, err)
+		}
+	}
+	return run, scanner.Err()
+}
+
+// List returns the paths of the runs recorded in dir, oldest first.
+func List(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "run-*.log"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
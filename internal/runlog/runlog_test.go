@@ -0,0 +1,73 @@
+package runlog
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	run := Run{
+		Command:  "df -h",
+		Start:    time.Date(2026, 3, 4, 5, 6, 7, 890_000_000, time.UTC),
+		Duration: 1500 * time.Millisecond,
+		ExitCode: 2,
+		Lines:    []string{"first", "", "# not a header"},
+	}
+
+	path, err := Write(dir, run)
+	if err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if filepath.Base(path) != "run-20260304-050607.890.log" {
+		t.Errorf("unexpected file name %q", filepath.Base(path))
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if got.Command != run.Command || !got.Start.Equal(run.Start) || got.Duration != run.Duration || got.ExitCode != run.ExitCode {
+		t.Errorf("Read() = %+v, want %+v", got, run)
+	}
+	if !slices.Equal(got.Lines, run.Lines) {
+		t.Errorf("expected lines %q, got %q", run.Lines, got.Lines)
+	}
+}
+
+func TestWriteReadEmptyOutput(t *testing.T) {
+	path, err := Write(t.TempDir(), Run{Command: "true", Start: time.Now()})
+	if err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(got.Lines) != 0 {
+		t.Errorf("expected no lines, got %q", got.Lines)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{2 * time.Second, 0, time.Second} {
+		if _, err := Write(dir, Run{Command: "date", Start: base.Add(offset)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(paths))
+	}
+	if !slices.IsSorted(paths) {
+		t.Errorf("expected runs oldest first, got %q", paths)
+	}
+}
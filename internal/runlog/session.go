@@ -0,0 +1,123 @@
+package runlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SessionVersion is the version of the session file format written by CreateSession
+const SessionVersion = 1
+
+// A session file records a whole live session as JSON Lines: a "session"
+// record with metadata, followed by one "run" record per finished run.
+const (
+	recordSession = "session"
+	recordRun     = "run"
+)
+
+// SessionInfo is the metadata at the start of a session file
+type SessionInfo struct {
+	Version int       `json:"version"`
+	Command string    `json:"command"`
+	Shell   string    `json:"shell"`
+	Started time.Time `json:"started"`
+}
+
+// recordType is the field every line of a session file starts with
+type recordType struct {
+	Type string `json:"type"`
+}
+
+// CreateSession starts a new session file at path, replacing any existing
+// file, and writes its metadata record.
+func CreateSession(path string, info SessionInfo) error {
+	info.Version = SessionVersion
+	rec := struct {
+		recordType
+		SessionInfo
+	}{recordType{recordSession}, info}
+	return writeRecord(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, rec)
+}
+
+// AppendRun adds a run to the session file at path. The file is reopened for
+// every run so everything recorded so far survives a crash.
+func AppendRun(path string, run Run) error {
+	rec := struct {
+		recordType
+		Run
+	}{recordType{recordRun}, run}
+	return writeRecord(path, os.O_WRONLY|os.O_APPEND, rec)
+}
+
+func writeRecord(path string, flag int, rec any) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadSession loads a session file written by CreateSession and AppendRun.
+func ReadSession(path string) (SessionInfo, []Run, error) {
+	var info SessionInfo
+	var runs []Run
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec recordType
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return info, nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		switch {
+		case n == 1 && rec.Type != recordSession:
+			return info, nil, fmt.Errorf("%s: not a watchr session file", path)
+		case rec.Type == recordSession:
+			if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+				return info, nil, fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+			if info.Version > SessionVersion {
+				return info, nil, fmt.Errorf("%s: unsupported session version %d", path, info.Version)
+			}
+		case rec.Type == recordRun:
+			var run Run
+			if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+				return info, nil, fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+			runs = append(runs, run)
+		}
+	}
+	return info, runs, scanner.Err()
+}
+
+// IsSession reports whether the file at path looks like a session file
+// rather than a single run written by Write.
+func IsSession(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+	first := make([]byte, 1)
+	n, _ := f.Read(first)
+	return n == 1 && first[0] == '{'
+}
@@ -0,0 +1,78 @@
+package runlog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.watchr")
+	started := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	if err := CreateSession(path, SessionInfo{Command: "kubectl get pods", Shell: "bash", Started: started}); err != nil {
+		t.Fatalf("CreateSession() error: %v", err)
+	}
+	runs := []Run{
+		{Command: "kubectl get pods", Start: started, Duration: time.Second, Lines: []string{"pod-a Running"}},
+		{Command: "kubectl get pods", Start: started.Add(time.Minute), Duration: 2 * time.Second, ExitCode: 1, Lines: []string{}},
+	}
+	for _, run := range runs {
+		if err := AppendRun(path, run); err != nil {
+			t.Fatalf("AppendRun() error: %v", err)
+		}
+	}
+
+	info, got, err := ReadSession(path)
+	if err != nil {
+		t.Fatalf("ReadSession() error: %v", err)
+	}
+	if info.Version != SessionVersion || info.Command != "kubectl get pods" || info.Shell != "bash" || !info.Started.Equal(started) {
+		t.Errorf("unexpected session info: %+v", info)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(got))
+	}
+	if got[0].Command != "kubectl get pods" || !slices.Equal(got[0].Lines, runs[0].Lines) || got[1].ExitCode != 1 || got[1].Duration != 2*time.Second {
+		t.Errorf("unexpected runs: %+v", got)
+	}
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 {
+		t.Errorf("expected 3 JSON lines, got %d", len(lines))
+	}
+}
+
+func TestCreateSessionTruncates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.watchr")
+	_ = CreateSession(path, SessionInfo{Command: "old"})
+	_ = AppendRun(path, Run{Command: "old"})
+	_ = CreateSession(path, SessionInfo{Command: "new"})
+
+	info, runs, err := ReadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Command != "new" || len(runs) != 0 {
+		t.Errorf("expected a fresh session, got %+v with %d runs", info, len(runs))
+	}
+}
+
+func TestReadSessionRejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	runPath, _ := Write(dir, Run{Command: "date", Start: time.Now()})
+	if IsSession(runPath) {
+		t.Error("expected a run file not to be detected as a session")
+	}
+
+	jsonPath := filepath.Join(dir, "other.json")
+	_ = os.WriteFile(jsonPath, []byte(`{"type":"other"}`+"\n"), 0o644)
+	if !IsSession(jsonPath) {
+		t.Error("expected JSON file to look like a session")
+	}
+	if _, _, err := ReadSession(jsonPath); err == nil {
+		t.Error("expected error for a JSON file that isn't a session")
+	}
+}
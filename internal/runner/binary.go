@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hexdumpWidth is the number of bytes shown per hexdump line
+const hexdumpWidth = 16
+
+// isBinary reports whether a chunk of output looks like binary data rather
+// than text: it contains a NUL byte, or more than 10% of its bytes are
+// control characters that text output (including ANSI styling) doesn't use.
+func isBinary(chunk []byte) bool {
+	if len(chunk) == 0 {
+		return false
+	}
+	control := 0
+	for _, b := range chunk {
+		switch {
+		case b == 0:
+			return true
+		case b < 0x20 && b != '\a' && b != '\n' && b != '\r' && b != '\t' && b != '\f' && b != '\b' && b != 0x1b:
+			control++
+		case b == 0x7f:
+			control++
+		}
+	}
+	return control*10 > len(chunk)
+}
+
+// hexdumpLine formats a chunk of up to hexdumpWidth bytes in `hexdump -C`
+// style: offset, hex bytes in two groups of eight, and printable ASCII.
+func hexdumpLine(offset int, chunk []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%08x  ", offset)
+	for i := range hexdumpWidth {
+		if i < len(chunk) {
+			fmt.Fprintf(&b, "%02x ", chunk[i])
+		} else {
+			b.WriteString("   ")
+		}
+		if i == hexdumpWidth/2-1 {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteString(" |")
+	for _, c := range chunk {
+		if c >= 0x20 && c < 0x7f {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('.')
+		}
+	}
+	b.WriteByte('|')
+	return b.String()
+}
+
+// scanOutput reads pipe and calls emit for each line of output. If the first
+// chunk read looks binary, the whole stream is rendered as a hexdump instead
+// of text, so raw bytes never reach the terminal. With KeepCRFrames, each
+// frame of a "\r"-redrawn line is emitted as its own line. Returns whether
+// the output was treated as binary.
+func (r *Runner) scanOutput(pipe io.Reader, emit func(content string)) bool {
+	reader := bufio.NewReader(pipe)
+
+	// Inspect whatever the first read returns; this doesn't block waiting for
+	// more output, so slow streaming commands are unaffected
+	_, _ = reader.Peek(1)
+	first, _ := reader.Peek(reader.Buffered())
+	if !isBinary(first) {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			if !r.KeepCRFrames {
+				emit(sanitizeLine(scanner.Text(), r.ControlChars, r.TabWidth))
+				continue
+			}
+			for _, frame := range crFrames(scanner.Text()) {
+				emit(sanitizeLine(frame, r.ControlChars, r.TabWidth))
+			}
+		}
+		return false
+	}
+
+	chunk := make([]byte, hexdumpWidth)
+	offset := 0
+	for {
+		n, err := io.ReadFull(reader, chunk)
+		if n > 0 {
+			emit(hexdumpLine(offset, chunk[:n]))
+			offset += n
+		}
+		if err != nil {
+			return true
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+import "strings"
+
+// ControlPolicy controls how control characters in command output are handled
+type ControlPolicy string
+
+const (
+	// ControlStrip removes control characters and non-color escape sequences
+	ControlStrip ControlPolicy = "strip"
+	// ControlCaret renders them as visible placeholders, like cat -v (^G, ^[[2J)
+	ControlCaret ControlPolicy = "caret"
+	// ControlRaw passes them through to the terminal unchanged
+	ControlRaw ControlPolicy = "raw"
+)
+
+// ControlPolicies lists the valid control character policies
+var ControlPolicies = []ControlPolicy{ControlStrip, ControlCaret, ControlRaw}
+
+// sanitizeControl applies policy to the control characters in s. Color (SGR)
+// escape sequences are always kept since the UI renders them; the empty
+// policy behaves like ControlStrip.
+func sanitizeControl(s string, policy ControlPolicy) string {
+	if policy == ControlRaw || !hasControl(s) {
+		return s
+	}
+	caret := policy == ControlCaret
+
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\x1b':
+			end := escapeEnd(runes, i)
+			seq := string(runes[i:end])
+			switch {
+			case isSGR(seq):
+				b.WriteString(seq)
+			case caret:
+				b.WriteString("^[" + string(runes[i+1:end]))
+			}
+			i = end - 1
+		case r == '\n':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			if caret {
+				b.WriteString(caretNotation(r))
+			}
+		case r >= 0x80 && r < 0xa0:
+			if caret {
+				b.WriteString("M-" + caretNotation(r-0x80))
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hasControl reports whether s contains any C0 (other than newline), DEL or C1
+// control characters
+func hasControl(s string) bool {
+	for _, r := range s {
+		if (r < 0x20 && r != '\n') || (r >= 0x7f && r < 0xa0) {
+			return true
+		}
+	}
+	return false
+}
+
+// caretNotation returns the ^X form of a C0 control character or DEL
+func caretNotation(r rune) string {
+	if r == 0x7f {
+		return "^?"
+	}
+	return "^" + string(r+'@')
+}
+
+// escapeEnd returns the index just past the escape sequence starting at start
+func escapeEnd(runes []rune, start int) int {
+	i := start + 1
+	if i >= len(runes) {
+		return i
+	}
+	switch runes[i] {
+	case '[':
+		// CSI: parameter and intermediate bytes, then a final byte in @-~
+		for i++; i < len(runes); i++ {
+			if runes[i] >= 0x40 && runes[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return i
+	case ']', 'P', '_', '^', 'X':
+		// OSC and other strings, terminated by BEL or ST (ESC \)
+		for i++; i < len(runes); i++ {
+			if runes[i] == '\a' {
+				return i + 1
+			}
+			if runes[i] == '\x1b' && i+1 < len(runes) && runes[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return i
+	default:
+		return i + 1
+	}
+}
+
+// isSGR reports whether seq is a Select Graphic Rendition (color/style) sequence
+func isSGR(seq string) bool {
+	if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+		return false
+	}
+	for _, r := range seq[2 : len(seq)-1] {
+		if (r < '0' || r > '9') && r != ';' && r != ':' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// exitCodeEnv passes the main command's exit code to the post-run hook
+const exitCodeEnv = "WATCHR_EXIT_CODE"
+
+// RunHook runs a hook command such as pre-run or post-run with the runner's
+// shell on the local machine, adding env to the command environment. Hook
+// output is only shown when the hook fails.
+func (r *Runner) RunHook(ctx context.Context, name, command string, env ...string) error {
+	if command == "" {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, r.Shell, ShellFlag(r.Shell), command)
+	cmd.Env = append(commandEnv(), env...)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("%s hook failed: %w: %s", name, err, msg)
+	}
+	return fmt.Errorf("%s hook failed: %w", name, err)
+}
+
+// RunTransitionHook runs the on-transition hook for the command going from
+// exit code prev to code, with WATCHR_TRANSITION set to fail or pass.
+func (r *Runner) RunTransitionHook(ctx context.Context, command string, prev, code int) error {
+	transition := "fail"
+	if code == 0 {
+		transition = "pass"
+	}
+	return r.RunHook(ctx, "on-transition", command,
+		"WATCHR_TRANSITION="+transition,
+		fmt.Sprintf("%s=%d", exitCodeEnv, code),
+		fmt.Sprintf("WATCHR_PREV_EXIT_CODE=%d", prev),
+	)
+}
+
+// runPostHook runs the post-run hook with the main command's exit code. It is
+// skipped when the run was cancelled.
+func (r *Runner) runPostHook(ctx context.Context, exitCode int) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return r.RunHook(ctx, "post-run", r.PostRun, fmt.Sprintf("%s=%d", exitCodeEnv, exitCode))
+}
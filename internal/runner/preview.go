@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern matches {} and field placeholders like {2} or {-1} in
+// preview and bound commands
+var placeholderPattern = regexp.MustCompile(`\{(-?\d+)?\}`)
+
+// PreviewCommand substitutes line, quoted for shell, for each {} in template,
+// and its Nth whitespace-separated field for each {N}. {-N} counts fields
+// from the end, and a field past either end is empty.
+func PreviewCommand(shell, template, line string) string {
+	fields := strings.Fields(line)
+	return placeholderPattern.ReplaceAllStringFunc(template, func(p string) string {
+		if p == "{}" {
+			return QuoteFor(shell, line)
+		}
+		n, _ := strconv.Atoi(p[1 : len(p)-1])
+		if n < 0 {
+			n += len(fields) + 1
+		}
+		if n < 1 || n > len(fields) {
+			return QuoteFor(shell, "")
+		}
+		return QuoteFor(shell, fields[n-1])
+	})
+}
+
+// QuoteFor quotes s as a single argument for shell: in double quotes for cmd,
+// in single quotes with ' doubled for PowerShell, and POSIX-style otherwise.
+func QuoteFor(shell, s string) string {
+	switch ShellFlag(shell) {
+	case "/C":
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	case "-Command":
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		return shellQuote(s)
+	}
+}
+
+// RunPreview runs a preview command with the runner's shell on the local
+// machine and returns its combined output, sanitized like the main command's.
+// The output is returned even when the command fails, since it usually
+// explains why.
+func (r *Runner) RunPreview(ctx context.Context, command string) (string, error) {
+	return r.RunPipe(ctx, command, "")
+}
+
+// RunPipe is like RunPreview, but feeds input to the command's stdin.
+func (r *Runner) RunPipe(ctx context.Context, command, input string) (string, error) {
+	cmd := exec.CommandContext(ctx, r.Shell, ShellFlag(r.Shell), command)
+	cmd.Env = commandEnv()
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	lines := splitLines(string(out))
+	for i, line := range lines {
+		lines[i] = sanitizeLine(line, r.ControlChars, r.TabWidth)
+	}
+	return strings.Join(lines, "\n"), err
+}
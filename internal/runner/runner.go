@@ -1,7 +1,6 @@
 package runner
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
@@ -10,21 +9,113 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/mattn/go-runewidth"
 )
 
+// DefaultTabWidth is the distance between tab stops when none is configured
+const DefaultTabWidth = 8
+
 // sanitizeLine removes control sequences that can corrupt terminal rendering
-func sanitizeLine(s string) string {
-	// Remove carriage returns
-	s = strings.ReplaceAll(s, "\r", "")
+func sanitizeLine(s string, policy ControlPolicy, tabWidth int) string {
+	// Apply carriage returns as in-place updates (progress bars, spinners)
+	s = collapseCR(s)
 	// Convert tabs to spaces (tabs cause width calculation issues)
-	s = strings.ReplaceAll(s, "\t", "        ")
-	return s
+	s = expandTabs(s, tabWidth)
+	// Strip or reveal the remaining control characters (bell, backspace, cursor movement)
+	return sanitizeControl(s, policy)
+}
+
+// expandTabs replaces each tab with spaces up to the next tab stop, every
+// tabWidth columns (DefaultTabWidth if 0), so tab-separated columns line up
+// as they would in a terminal. ANSI escape sequences take up no columns and
+// wide characters two.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	if tabWidth <= 0 {
+		tabWidth = DefaultTabWidth
+	}
+	var b strings.Builder
+	col, inEscape := 0, false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			// A CSI sequence ends at its final byte, in the range 0x40-0x7E
+			inEscape = r == '[' || r < 0x40 || r > 0x7e
+		case r == '\x1b':
+			inEscape = true
+		case r == '\t':
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		default:
+			col += runewidth.RuneWidth(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// collapseCR applies carriage returns the way a terminal would: each "\r"
+// moves back to the start of the line and the following text overwrites what
+// was there, so only the final state of a redrawn progress line is kept.
+// Windows "\r\n" line endings are treated as plain newlines.
+func collapseCR(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if !strings.Contains(s, "\r") {
+		return s
+	}
+
+	frames := strings.Split(s, "\r")
+	if strings.Contains(s, "\x1b") {
+		// Overwriting styled text rune by rune would split escape sequences,
+		// so keep the last frame that drew anything
+		for i := len(frames) - 1; i >= 0; i-- {
+			if frames[i] != "" {
+				return frames[i]
+			}
+		}
+		return ""
+	}
+
+	var screen []rune
+	for _, frame := range frames {
+		for i, r := range []rune(frame) {
+			if i < len(screen) {
+				screen[i] = r
+			} else {
+				screen = append(screen, r)
+			}
+		}
+	}
+	return string(screen)
+}
+
+// crFrames splits a line redrawn with carriage returns into each of its
+// intermediate frames, for when every progress update should be kept.
+func crFrames(s string) []string {
+	s = strings.TrimRight(s, "\r")
+	if !strings.Contains(s, "\r") {
+		return []string{s}
+	}
+	var frames []string
+	for frame := range strings.SplitSeq(s, "\r") {
+		if frame != "" {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
 }
 
 // Line represents a single line of output with its line number
 type Line struct {
 	Number  int
 	Content string
+	Time    time.Time // when the line was read
 }
 
 // FormatLine returns the formatted line with line number
@@ -37,9 +128,18 @@ func (l Line) FormatLine(width int, showLineNum bool) string {
 
 // Runner executes commands and captures output
 type Runner struct {
-	Shell       string
-	Command     string
-	Interactive bool
+	Shell        string
+	Command      string
+	Interactive  bool
+	KeepCRFrames bool          // keep every frame of "\r"-redrawn lines instead of only the last
+	ControlChars ControlPolicy // how control characters in output are handled
+	TabWidth     int           // columns between tab stops (DefaultTabWidth if 0)
+	SSH          string        // if set, the command runs on this host ([user@]host) over ssh
+	Docker       string        // if set, the command runs in this container with docker exec
+	KubectlPod   string        // if set, the command runs in this pod with kubectl exec
+	RemoteShell  string        // shell the command runs with on those targets, if not Shell
+	PreRun       string        // hook command run locally before each run; if it fails the run is skipped
+	PostRun      string        // hook command run locally after each run, with WATCHR_EXIT_CODE set
 }
 
 // NewRunner creates a new Runner
@@ -60,11 +160,32 @@ func NewInteractiveRunner(shell, command string) *Runner {
 	}
 }
 
+// ShellFlag returns the flag that makes shell run a command string: /C for
+// cmd, -Command for PowerShell, and -c for everything else.
+func ShellFlag(shell string) string {
+	name := strings.ToLower(filepath.Base(shell))
+	switch strings.TrimSuffix(name, ".exe") {
+	case "cmd":
+		return "/C"
+	case "powershell", "pwsh":
+		return "-Command"
+	default:
+		return "-c"
+	}
+}
+
 // buildCommand returns the shell arguments for executing the command.
 // If Interactive is true, it wraps the command to source the appropriate rc file.
 func (r *Runner) buildCommand() []string {
-	if !r.Interactive {
-		return []string{"-c", r.Command}
+	flag := ShellFlag(r.shell())
+	if !r.Interactive || flag != "-c" {
+		// cmd has no rc file, and PowerShell loads its profile by itself
+		return []string{flag, r.Command}
+	}
+
+	if r.remote() {
+		// The rc files live on the remote host, so let a login shell load its profile there
+		return []string{"-l", "-c", r.Command}
 	}
 
 	// For interactive mode, source the appropriate rc file before running the command
@@ -78,6 +199,115 @@ func (r *Runner) buildCommand() []string {
 	return []string{"-c", r.Command}
 }
 
+// shell returns the shell the command itself runs with, which for a remote
+// target is RemoteShell if set
+func (r *Runner) shell() string {
+	if r.remote() && r.RemoteShell != "" {
+		return r.RemoteShell
+	}
+	return r.Shell
+}
+
+// remote reports whether the command runs somewhere other than the local machine
+func (r *Runner) remote() bool {
+	return r.SSH != "" || r.Docker != "" || r.KubectlPod != ""
+}
+
+// Target describes where a remote command runs (e.g., "user@host" or
+// "docker:web"), or returns "" for local commands.
+func (r *Runner) Target() string {
+	switch {
+	case r.SSH != "":
+		return r.SSH
+	case r.Docker != "":
+		return "docker:" + r.Docker
+	case r.KubectlPod != "":
+		return "kubectl:" + r.KubectlPod
+	default:
+		return ""
+	}
+}
+
+// execArgs returns the program and arguments that run the command. Remote
+// commands wrap the shell invocation in ssh, docker exec or kubectl exec,
+// passing WATCHR through env since the local environment doesn't reach them.
+func (r *Runner) execArgs() (string, []string) {
+	args := r.buildCommand()
+	remote := append([]string{"env", watchrEnv, r.shell()}, args...)
+
+	switch {
+	case r.SSH != "":
+		// ssh joins its arguments into a single string that the remote login
+		// shell parses again, so each argument is quoted. BatchMode keeps ssh
+		// from prompting for passwords on the terminal the UI is drawn on.
+		quoted := make([]string, len(remote))
+		for i, arg := range remote {
+			quoted[i] = shellQuote(arg)
+		}
+		return "ssh", []string{"-T", "-o", "BatchMode=yes", "--", r.SSH, strings.Join(quoted, " ")}
+	case r.Docker != "":
+		return "docker", append([]string{"exec", r.Docker}, remote...)
+	case r.KubectlPod != "":
+		return "kubectl", append([]string{"exec", r.KubectlPod, "--"}, remote...)
+	default:
+		return r.Shell, args
+	}
+}
+
+// watchrEnv is added to the inherited environment of every command run
+const watchrEnv = "WATCHR=1"
+
+// commandEnv returns the environment used for command execution
+func commandEnv() []string {
+	return append(os.Environ(), watchrEnv)
+}
+
+// Invocation describes exactly how a command will be executed
+type Invocation struct {
+	Shell string
+	Args  []string
+	Env   []string // variables added on top of the inherited environment
+	Dir   string   // working directory the command runs in
+	PTY   bool     // whether the command is attached to a pseudo-terminal
+}
+
+// Invocation returns the effective invocation for the runner's command
+// without executing it.
+func (r *Runner) Invocation() Invocation {
+	dir, _ := os.Getwd()
+	name, args := r.execArgs()
+	return Invocation{
+		Shell: name,
+		Args:  args,
+		Env:   []string{watchrEnv},
+		Dir:   dir,
+		PTY:   false,
+	}
+}
+
+// CommandLine returns the invocation as a copy-pasteable shell command line.
+func (inv Invocation) CommandLine() string {
+	parts := make([]string, 0, len(inv.Args)+1)
+	parts = append(parts, shellQuote(inv.Shell))
+	for _, arg := range inv.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s for POSIX shells, leaving simple words untouched.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // getRCFile returns the path to the shell's rc file based on the shell being used.
 func (r *Runner) getRCFile() string {
 	home, err := os.UserHomeDir()
@@ -120,13 +350,19 @@ func (r *Runner) getRCFile() string {
 type Result struct {
 	Lines    []Line
 	ExitCode int
+	Binary   bool // output was binary and is rendered as a hexdump
 }
 
-// Run executes the command and returns output lines with exit code
+// Run executes the command and returns output lines with exit code. A failing
+// post-run hook is returned as an error along with the full result.
 func (r *Runner) Run(ctx context.Context) (Result, error) {
-	args := r.buildCommand()
-	cmd := exec.CommandContext(ctx, r.Shell, args...)
-	cmd.Env = append(os.Environ(), "WATCHR=1")
+	if err := r.RunHook(ctx, "pre-run", r.PreRun); err != nil {
+		return Result{}, err
+	}
+
+	name, args := r.execArgs()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = commandEnv()
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -144,25 +380,19 @@ func (r *Runner) Run(ctx context.Context) (Result, error) {
 
 	var lines []Line
 	lineNum := 1
-
-	// Read stdout
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
+	emit := func(content string) {
 		lines = append(lines, Line{
 			Number:  lineNum,
-			Content: sanitizeLine(scanner.Text()),
+			Content: content,
+			Time:    time.Now(),
 		})
 		lineNum++
 	}
 
-	// Read stderr
-	stderrScanner := bufio.NewScanner(stderr)
-	for stderrScanner.Scan() {
-		lines = append(lines, Line{
-			Number:  lineNum,
-			Content: sanitizeLine(stderrScanner.Text()),
-		})
-		lineNum++
+	// Read stdout, then stderr
+	binary := r.scanOutput(stdout, emit)
+	if r.scanOutput(stderr, emit) {
+		binary = true
 	}
 
 	// Wait for command to finish and get exit code
@@ -173,7 +403,8 @@ func (r *Runner) Run(ctx context.Context) (Result, error) {
 		}
 	}
 
-	return Result{Lines: lines, ExitCode: exitCode}, nil
+	result := Result{Lines: lines, ExitCode: exitCode, Binary: binary}
+	return result, r.runPostHook(ctx, exitCode)
 }
 
 // StreamingResult holds the state of a streaming command
@@ -182,8 +413,9 @@ type StreamingResult struct {
 	ExitCode         int
 	Done             bool
 	Error            error
-	PrevLineCount    int // Number of lines from previous run (for trimming)
-	CurrentLineCount int // Number of lines written by current run
+	PrevLineCount    int  // Number of lines from previous run (for trimming)
+	CurrentLineCount int  // Number of lines written by current run
+	Binary           bool // Output was binary and is rendered as a hexdump
 	mu               sync.RWMutex
 }
 
@@ -216,6 +448,13 @@ func (s *StreamingResult) IsDone() bool {
 	return s.Done
 }
 
+// IsBinary returns whether the output is being rendered as a hexdump (thread-safe)
+func (s *StreamingResult) IsBinary() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Binary
+}
+
 // GetCurrentLineCount returns the number of lines written by the current run (thread-safe)
 func (s *StreamingResult) GetCurrentLineCount() int {
 	s.mu.RLock()
@@ -240,9 +479,17 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 	}
 
 	go func() {
-		args := r.buildCommand()
-		cmd := exec.CommandContext(ctx, r.Shell, args...)
-		cmd.Env = append(os.Environ(), "WATCHR=1")
+		if err := r.RunHook(ctx, "pre-run", r.PreRun); err != nil {
+			result.mu.Lock()
+			result.Error = err
+			result.Done = true
+			result.mu.Unlock()
+			return
+		}
+
+		name, args := r.execArgs()
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Env = commandEnv()
 
 		stdout, err := cmd.StdoutPipe()
 		if err != nil {
@@ -279,8 +526,7 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 
 		readPipe := func(pipe io.Reader) {
 			defer wg.Done()
-			scanner := bufio.NewScanner(pipe)
-			for scanner.Scan() {
+			binary := r.scanOutput(pipe, func(content string) {
 				lineNumMu.Lock()
 				currentLineNum := lineNum
 				lineIdx := lineNum - 1 // 0-indexed
@@ -289,7 +535,8 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 
 				newLine := Line{
 					Number:  currentLineNum,
-					Content: sanitizeLine(scanner.Text()),
+					Content: content,
+					Time:    time.Now(),
 				}
 
 				result.mu.Lock()
@@ -305,6 +552,11 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 					result.CurrentLineCount = currentLineNum
 				}
 				result.mu.Unlock()
+			})
+			if binary {
+				result.mu.Lock()
+				result.Binary = true
+				result.mu.Unlock()
 			}
 		}
 
@@ -324,8 +576,11 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 			}
 		}
 
+		hookErr := r.runPostHook(ctx, exitCode)
+
 		result.mu.Lock()
 		result.ExitCode = exitCode
+		result.Error = hookErr
 		result.Done = true
 		result.mu.Unlock()
 	}()
@@ -335,9 +590,9 @@ func (r *Runner) RunStreaming(ctx context.Context, prevLines []Line) *StreamingR
 
 // RunSimple executes the command and returns output as string slice
 func (r *Runner) RunSimple(ctx context.Context) ([]string, error) {
-	args := r.buildCommand()
-	cmd := exec.CommandContext(ctx, r.Shell, args...)
-	cmd.Env = append(os.Environ(), "WATCHR=1")
+	name, args := r.execArgs()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = commandEnv()
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Still return output even on error (non-zero exit)
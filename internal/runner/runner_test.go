@@ -2,6 +2,9 @@ package runner
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -61,6 +64,20 @@ func TestRunner_buildCommand(t *testing.T) {
 			interactive: true,
 			wantFirst:   "-c",
 		},
+		{
+			name:        "cmd",
+			shell:       "cmd.exe",
+			command:     "dir",
+			interactive: false,
+			wantFirst:   "/C",
+		},
+		{
+			name:        "interactive powershell",
+			shell:       "pwsh",
+			command:     "Get-Process",
+			interactive: true,
+			wantFirst:   "-Command",
+		},
 	}
 
 	for _, tt := range tests {
@@ -335,7 +352,7 @@ func TestSanitizeLine(t *testing.T) {
 		{
 			name:  "tabs converted to spaces",
 			input: "col1\tcol2\tcol3",
-			want:  "col1        col2        col3",
+			want:  "col1    col2    col3",
 		},
 		{
 			name:  "carriage returns removed",
@@ -343,9 +360,29 @@ func TestSanitizeLine(t *testing.T) {
 			want:  "line with\nwindows ending",
 		},
 		{
-			name:  "carriage return only removed",
+			name:  "carriage return overwrites in place",
 			input: "progress\roverwrite",
-			want:  "progressoverwrite",
+			want:  "overwrite",
+		},
+		{
+			name:  "progress frames collapse to final state",
+			input: " 10%\r 50%\r100%",
+			want:  "100%",
+		},
+		{
+			name:  "shorter frame keeps tail of longer one",
+			input: "downloading\rdone",
+			want:  "doneloading",
+		},
+		{
+			name:  "trailing carriage return keeps line",
+			input: "done\r",
+			want:  "done",
+		},
+		{
+			name:  "styled frames keep the last one",
+			input: "\x1b[33m 50%\x1b[0m\r\x1b[32m100%\x1b[0m",
+			want:  "\x1b[32m100%\x1b[0m",
 		},
 		{
 			name:  "ANSI color codes preserved",
@@ -355,7 +392,7 @@ func TestSanitizeLine(t *testing.T) {
 		{
 			name:  "mixed tabs and colors",
 			input: "\x1b[1m?\x1b[0m\tpackage\t[no test files]",
-			want:  "\x1b[1m?\x1b[0m        package        [no test files]",
+			want:  "\x1b[1m?\x1b[0m       package [no test files]",
 		},
 		{
 			name:  "empty string",
@@ -371,7 +408,7 @@ func TestSanitizeLine(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := sanitizeLine(tt.input)
+			got := sanitizeLine(tt.input, ControlStrip, 0)
 			if got != tt.want {
 				t.Errorf("sanitizeLine(%q) = %q, want %q", tt.input, got, tt.want)
 			}
@@ -522,3 +559,418 @@ func TestStreamingResultThreadSafety(t *testing.T) {
 		t.Errorf("expected 100 lines, got %d", result.LineCount())
 	}
 }
+
+func TestRunner_Invocation(t *testing.T) {
+	r := NewRunner("sh", "echo 'hi' | grep h")
+	inv := r.Invocation()
+
+	if inv.Shell != "sh" {
+		t.Errorf("expected shell 'sh', got %q", inv.Shell)
+	}
+	if len(inv.Args) != 2 || inv.Args[0] != "-c" || inv.Args[1] != "echo 'hi' | grep h" {
+		t.Errorf("unexpected args: %q", inv.Args)
+	}
+	if len(inv.Env) != 1 || inv.Env[0] != "WATCHR=1" {
+		t.Errorf("expected env [WATCHR=1], got %q", inv.Env)
+	}
+	if inv.PTY {
+		t.Error("expected PTY to be false")
+	}
+
+	want := `sh -c 'echo '\''hi'\'' | grep h'`
+	if got := inv.CommandLine(); got != want {
+		t.Errorf("CommandLine() = %q, want %q", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "''"},
+		{"simple", "simple"},
+		{"/bin/sh", "/bin/sh"},
+		{"-c", "-c"},
+		{"with space", "'with space'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.input); got != tt.expected {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected bool
+	}{
+		{"empty", nil, false},
+		{"plain text", []byte("hello world\n"), false},
+		{"ansi colors", []byte("\x1b[31mred\x1b[0m\n"), false},
+		{"progress line", []byte("50%\r100%\n"), false},
+		{"nul byte", []byte("abc\x00def"), true},
+		{"mostly control", []byte{0x01, 0x02, 0x03, 'a', 'b'}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.input); got != tt.expected {
+				t.Errorf("isBinary(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHexdumpLine(t *testing.T) {
+	got := hexdumpLine(16, []byte("Hello, world!\x00\x01\n"))
+	want := "00000010  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 00 01 0a  |Hello, world!...|"
+	if got != want {
+		t.Errorf("hexdumpLine() =\n%q\nwant\n%q", got, want)
+	}
+
+	got = hexdumpLine(0, []byte("ab"))
+	want = "00000000  61 62                                             |ab|"
+	if got != want {
+		t.Errorf("hexdumpLine() short =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRunner_RunBinaryOutput(t *testing.T) {
+	r := NewRunner("sh", `printf 'ab\000cd'`)
+	result, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Binary {
+		t.Error("expected output to be detected as binary")
+	}
+	if len(result.Lines) != 1 {
+		t.Fatalf("expected 1 hexdump line, got %d", len(result.Lines))
+	}
+	if !strings.HasPrefix(result.Lines[0].Content, "00000000  61 62 00 63 64") {
+		t.Errorf("unexpected hexdump line: %q", result.Lines[0].Content)
+	}
+}
+
+func TestCRFrames(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"plain", []string{"plain"}},
+		{" 10%\r 50%\r100%", []string{" 10%", " 50%", "100%"}},
+		{"done\r", []string{"done"}},
+		{"\ra\r\rb", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		got := crFrames(tt.input)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("crFrames(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRunner_RunKeepCRFrames(t *testing.T) {
+	r := NewRunner("sh", `printf ' 10%%\r100%%\nnext\n'`)
+	result, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Lines) != 2 || result.Lines[0].Content != "100%" {
+		t.Errorf("expected collapsed progress line, got %v", result.Lines)
+	}
+
+	r.KeepCRFrames = true
+	result, err = r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Lines) != 3 || result.Lines[0].Content != " 10%" || result.Lines[1].Content != "100%" {
+		t.Errorf("expected every progress frame, got %v", result.Lines)
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		input    string
+		tabWidth int
+		want     string
+	}{
+		{"a\tb", 0, "a       b"},
+		{"name\tsize\nx", 4, "name    size\nx"},
+		{"ab\tc", 4, "ab  c"},
+		{"\x1b[31mab\x1b[0m\tc", 4, "\x1b[31mab\x1b[0m  c"},
+		{"\t", 2, "  "},
+		{"no tabs", 4, "no tabs"},
+	}
+	for _, tt := range tests {
+		if got := expandTabs(tt.input, tt.tabWidth); got != tt.want {
+			t.Errorf("expandTabs(%q, %d) = %q, want %q", tt.input, tt.tabWidth, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		policy ControlPolicy
+		want   string
+	}{
+		{"plain text untouched", "hello", ControlStrip, "hello"},
+		{"bell stripped", "done\a", ControlStrip, "done"},
+		{"bell as caret", "done\a", ControlCaret, "done^G"},
+		{"bell raw", "done\a", ControlRaw, "done\a"},
+		{"backspace as caret", "ab\bc", ControlCaret, "ab^Hc"},
+		{"delete as caret", "a\x7f", ControlCaret, "a^?"},
+		{"color kept when stripping", "\x1b[31mred\x1b[0m", ControlStrip, "\x1b[31mred\x1b[0m"},
+		{"color kept as caret", "\x1b[1;32mok\x1b[m", ControlCaret, "\x1b[1;32mok\x1b[m"},
+		{"clear screen stripped", "\x1b[2Jtext", ControlStrip, "text"},
+		{"clear screen as caret", "\x1b[2Jtext", ControlCaret, "^[[2Jtext"},
+		{"cursor movement stripped", "a\x1b[3Ab", ControlStrip, "ab"},
+		{"osc title stripped", "\x1b]0;title\atext", ControlStrip, "text"},
+		{"osc with st stripped", "\x1b]8;;http://x\x1b\\link", ControlStrip, "link"},
+		{"two-byte escape stripped", "\x1b7saved", ControlStrip, "saved"},
+		{"trailing escape stripped", "text\x1b", ControlStrip, "text"},
+		{"c1 control as caret", "a\u0085b", ControlCaret, "aM-^Eb"},
+		{"newline kept", "a\nb", ControlStrip, "a\nb"},
+		{"empty policy strips", "x\ay", "", "xy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeControl(tt.input, tt.policy)
+			if got != tt.want {
+				t.Errorf("sanitizeControl(%q, %q) = %q, want %q", tt.input, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_RunControlChars(t *testing.T) {
+	r := NewRunner("sh", `printf 'a\007b\n'`)
+	r.ControlChars = ControlCaret
+
+	result, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Lines) != 1 || result.Lines[0].Content != "a^Gb" {
+		t.Errorf("expected [a^Gb], got %v", result.Lines)
+	}
+}
+
+func TestRunner_ExecArgsSSH(t *testing.T) {
+	r := NewRunner("bash", "echo 'hi' | grep h")
+	r.SSH = "user@example.com"
+
+	name, args := r.execArgs()
+	if name != "ssh" {
+		t.Errorf("expected program 'ssh', got %q", name)
+	}
+	want := []string{"-T", "-o", "BatchMode=yes", "--", "user@example.com", `env WATCHR=1 bash -c 'echo '\''hi'\'' | grep h'`}
+	if strings.Join(args, "\x00") != strings.Join(want, "\x00") {
+		t.Errorf("execArgs() = %q, want %q", args, want)
+	}
+
+	r.Interactive = true
+	if _, args := r.execArgs(); !strings.HasSuffix(args[len(args)-1], "bash -l -c 'echo '\\''hi'\\'' | grep h'") {
+		t.Errorf("expected remote interactive command to use a login shell, got %q", args[len(args)-1])
+	}
+}
+
+func TestRunner_RunSSH(t *testing.T) {
+	// A fake ssh that runs its last argument locally, the way the remote shell would
+	dir := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r := NewRunner("sh", `echo "it's $WATCHR"`)
+	r.SSH = "host"
+	result, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Lines) != 1 || result.Lines[0].Content != "it's 1" {
+		t.Errorf("expected [it's 1], got %v", result.Lines)
+	}
+}
+
+func TestRunner_ExecArgsContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(r *Runner)
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "docker",
+			setup:    func(r *Runner) { r.Docker = "web" },
+			wantName: "docker",
+			wantArgs: []string{"exec", "web", "env", "WATCHR=1", "sh", "-c", "ls 'my dir'"},
+		},
+		{
+			name:     "kubectl",
+			setup:    func(r *Runner) { r.KubectlPod = "deploy/api" },
+			wantName: "kubectl",
+			wantArgs: []string{"exec", "deploy/api", "--", "env", "WATCHR=1", "sh", "-c", "ls 'my dir'"},
+		},
+		{
+			name:     "remote shell",
+			setup:    func(r *Runner) { r.Shell, r.RemoteShell, r.Docker = "pwsh", "sh", "web" },
+			wantName: "docker",
+			wantArgs: []string{"exec", "web", "env", "WATCHR=1", "sh", "-c", "ls 'my dir'"},
+		},
+		{
+			name:     "local",
+			setup:    func(r *Runner) { r.RemoteShell = "bash" },
+			wantName: "sh",
+			wantArgs: []string{"-c", "ls 'my dir'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRunner("sh", "ls 'my dir'")
+			tt.setup(r)
+			name, args := r.execArgs()
+			if name != tt.wantName {
+				t.Errorf("expected program %q, got %q", tt.wantName, name)
+			}
+			if strings.Join(args, "\x00") != strings.Join(tt.wantArgs, "\x00") {
+				t.Errorf("execArgs() = %q, want %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRunner_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "post")
+	r := NewRunner("sh", "cat "+filepath.Join(dir, "pre")+"; exit 4")
+	r.PreRun = "echo prepared > " + filepath.Join(dir, "pre")
+	r.PostRun = `echo "$WATCHR_EXIT_CODE" > ` + marker
+
+	result, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Lines) != 1 || result.Lines[0].Content != "prepared" {
+		t.Errorf("expected pre-run hook to run first, got %v", result.Lines)
+	}
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("expected post-run hook to run: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "4" {
+		t.Errorf("expected WATCHR_EXIT_CODE=4, got %q", data)
+	}
+}
+
+func TestRunner_PreRunHookFailure(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	r := NewRunner("sh", "touch "+marker)
+	r.PreRun = "echo no cache >&2; exit 1"
+
+	_, err := r.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "pre-run hook failed") || !strings.Contains(err.Error(), "no cache") {
+		t.Errorf("expected pre-run hook error with its output, got %v", err)
+	}
+	if _, statErr := os.Stat(marker); statErr == nil {
+		t.Error("expected command not to run after a failing pre-run hook")
+	}
+}
+
+func TestRunner_StreamingHooks(t *testing.T) {
+	r := NewRunner("sh", "echo out")
+	r.PostRun = "exit 2"
+
+	result := r.RunStreaming(context.Background(), nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for !result.IsDone() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if result.LineCount() != 1 {
+		t.Errorf("expected output to be kept, got %d lines", result.LineCount())
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "post-run hook failed") {
+		t.Errorf("expected post-run hook error, got %v", result.Error)
+	}
+}
+
+func TestPreviewCommand(t *testing.T) {
+	tests := []struct {
+		template, line, want string
+	}{
+		{"cat {}", "main.go", "cat main.go"},
+		{"cat {}", "my file.txt", "cat 'my file.txt'"},
+		{"diff {} {}.orig", "a", "diff a a.orig"},
+		{"echo fixed", "ignored", "echo fixed"},
+		{"echo {}", "it's", `echo 'it'\''s'`},
+		{"kubectl describe pod {1} -n {2}", "api-7f  default  Running", "kubectl describe pod api-7f -n default"},
+		{"echo {-1} {-3}", "a b c", "echo c a"},
+		{"echo {4} {0} {-4}", "a b c", "echo '' '' ''"},
+		{"echo {x}", "a", "echo {x}"},
+	}
+	for _, tt := range tests {
+		if got := PreviewCommand("sh", tt.template, tt.line); got != tt.want {
+			t.Errorf("PreviewCommand(%q, %q) = %q, want %q", tt.template, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteFor(t *testing.T) {
+	tests := []struct {
+		shell, s, want string
+	}{
+		{"sh", "it's", `'it'\''s'`},
+		{"bash", "main.go", "main.go"},
+		{"cmd", `say "hi"`, `"say ""hi"""`},
+		{"cmd.exe", "a b", `"a b"`},
+		{"pwsh", "it's", `'it''s'`},
+		{"powershell.exe", "", "''"},
+		{"cmd", "", `""`},
+	}
+	for _, tt := range tests {
+		if got := QuoteFor(tt.shell, tt.s); got != tt.want {
+			t.Errorf("QuoteFor(%q, %q) = %q, want %q", tt.shell, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRunner_RunPipe(t *testing.T) {
+	r := NewRunner("sh", "true")
+	out, err := r.RunPipe(context.Background(), "sort -r", "a\nc\nb\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "c\nb\na" {
+		t.Errorf("expected sorted input, got %q", out)
+	}
+}
+
+func TestRunner_RunPreview(t *testing.T) {
+	r := NewRunner("sh", "true")
+	out, err := r.RunPreview(context.Background(), PreviewCommand("sh", "printf '%s\\tend\\n' {}", "a b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "a b     end" {
+		t.Errorf("expected sanitized output, got %q", out)
+	}
+
+	out, err = r.RunPreview(context.Background(), "echo missing >&2; exit 3")
+	if err == nil || out != "missing" {
+		t.Errorf("expected error with the command's output, got %q, %v", out, err)
+	}
+}
@@ -0,0 +1,300 @@
+// Package state persists lightweight session state so an interrupted watchr
+// session (terminal closed, machine crashed) can be resumed with --resume.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// maxCommands caps how many commands' view state, session journals and
+// column layouts are remembered; the least recently saved are dropped first
+const maxCommands = 100
+
+// Session is the investigation context saved to the journal
+type Session struct {
+	Command     string            `json:"command"`
+	Shell       string            `json:"shell"`
+	Interactive bool              `json:"interactive"`
+	Filter      string            `json:"filter"`
+	FilterKind  string            `json:"filter_kind"`
+	Pinned      []Filter          `json:"pinned,omitempty"`   // filters pinned to the filter stack
+	Notes       map[string]string `json:"notes,omitempty"`    // line notes keyed by content hash
+	Marks       []string          `json:"marks,omitempty"`    // marked lines, by content hash
+	Baseline    string            `json:"baseline,omitempty"` // content hash of the last finished run's output
+	SavedAt     time.Time         `json:"saved_at"`
+}
+
+// Layout is the table mode column layout saved for a profile
+type Layout struct {
+	Columns []Column  `json:"columns"`
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// Column is a table mode column's place in a layout, found by its name
+type Column struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden,omitempty"`
+	Width  int    `json:"width,omitempty"` // 0 fits the column to its widest cell
+}
+
+// Filter is a saved filter pattern and its matching algorithm
+type Filter struct {
+	Text string `json:"text"`
+	Kind string `json:"kind"`
+}
+
+// Command is the view state remembered for a command, restored the next
+// time the same command is watched
+type Command struct {
+	Filter     string    `json:"filter,omitempty"`
+	FilterKind string    `json:"filter_kind,omitempty"`
+	Preview    bool      `json:"preview,omitempty"`
+	Anchor     string    `json:"anchor,omitempty"` // plain content of the selected line
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// Dir returns the directory watchr stores state in.
+func Dir() string {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "watchr")
+		}
+		return ""
+	default:
+		// Use XDG_STATE_HOME if set, otherwise ~/.local/state
+		if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+			return filepath.Join(xdg, "watchr")
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "state", "watchr")
+		}
+		return ""
+	}
+}
+
+// SessionsDir returns the directory session journals are kept in, or "" if
+// no state directory is available.
+func SessionsDir() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "sessions")
+}
+
+// SessionPath returns the path of the session journal for command, or "" if
+// no state directory is available. Journals are named by a hash of the
+// command, so sessions watching different commands don't overwrite each
+// other.
+func SessionPath(command string) string {
+	dir := SessionsDir()
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(command))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// CommandsPath returns the path of the per-command view state, or "" if no
+// state directory is available.
+func CommandsPath() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "commands.json")
+}
+
+// LayoutsPath returns the path of the saved table mode column layouts, or ""
+// if no state directory is available.
+func LayoutsPath() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "layouts.json")
+}
+
+// Save writes the session to path atomically, so a crash mid-write never
+// leaves a corrupt journal. Beyond maxCommands journals in its directory,
+// the least recently saved are removed.
+func Save(path string, s Session) error {
+	if err := writeJSON(path, s); err != nil {
+		return err
+	}
+	pruneJournals(filepath.Dir(path))
+	return nil
+}
+
+// pruneJournals removes the least recently modified journals in dir beyond
+// maxCommands. Errors are ignored; a leftover journal is harmless.
+func pruneJournals(dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(paths) <= maxCommands {
+		return
+	}
+	modified := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			modified[path] = info.ModTime()
+		}
+	}
+	slices.SortFunc(paths, func(a, b string) int {
+		return modified[b].Compare(modified[a])
+	})
+	for _, path := range paths[maxCommands:] {
+		_ = os.Remove(path)
+	}
+}
+
+// Latest reads the most recently saved session journal in dir.
+func Latest(dir string) (Session, error) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	var latest Session
+	found := false
+	for _, path := range paths {
+		s, err := Load(path)
+		if err != nil {
+			continue
+		}
+		if !found || s.SavedAt.After(latest.SavedAt) {
+			latest, found = s, true
+		}
+	}
+	if !found {
+		return latest, errors.New("no saved sessions")
+	}
+	return latest, nil
+}
+
+// writeJSON writes v to path as JSON atomically: it is written to a temp
+// file and renamed into place.
+func writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Load reads the session saved at path.
+func Load(path string) (Session, error) {
+	var s Session
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// LoadCommand reads the view state remembered for command from the file at
+// path, reporting whether there was any.
+func LoadCommand(path, command string) (Command, bool) {
+	commands, err := loadMap[Command](path)
+	if err != nil {
+		return Command{}, false
+	}
+	c, ok := commands[command]
+	return c, ok
+}
+
+// SaveCommand remembers the view state for command in the file at path,
+// keeping the state of the other commands last saved. An unreadable file is
+// replaced.
+func SaveCommand(path, command string, c Command) error {
+	commands, _ := loadMap[Command](path)
+	if commands == nil {
+		commands = make(map[string]Command)
+	}
+	commands[command] = c
+	dropOldest(commands, func(c Command) time.Time { return c.SavedAt })
+	return writeJSON(path, commands)
+}
+
+// LoadLayout reads the table mode column layout saved for profile from the
+// file at path, reporting whether there was any.
+func LoadLayout(path, profile string) ([]Column, bool) {
+	layouts, err := loadMap[Layout](path)
+	if err != nil {
+		return nil, false
+	}
+	l, ok := layouts[profile]
+	return l.Columns, ok
+}
+
+// SaveLayout saves the table mode column layout for profile in the file at
+// path, keeping the layouts of the other profiles last saved. An unreadable
+// file is replaced.
+func SaveLayout(path, profile string, columns []Column) error {
+	layouts, _ := loadMap[Layout](path)
+	if layouts == nil {
+		layouts = make(map[string]Layout)
+	}
+	layouts[profile] = Layout{Columns: columns, SavedAt: time.Now()}
+	dropOldest(layouts, func(l Layout) time.Time { return l.SavedAt })
+	return writeJSON(path, layouts)
+}
+
+// dropOldest removes the least recently saved entries beyond maxCommands.
+func dropOldest[T any](entries map[string]T, savedAt func(T) time.Time) {
+	if len(entries) <= maxCommands {
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return savedAt(entries[b]).Compare(savedAt(entries[a]))
+	})
+	for _, name := range names[maxCommands:] {
+		delete(entries, name)
+	}
+}
+
+func loadMap[T any](path string) (map[string]T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]T
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
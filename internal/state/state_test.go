@@ -0,0 +1,180 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("XDG_STATE_HOME is not used on Windows")
+	}
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+
+	if got := Dir(); got != filepath.Join(tmpDir, "watchr") {
+		t.Errorf("expected state dir under XDG_STATE_HOME, got %q", got)
+	}
+	path := SessionPath("ls -la")
+	if filepath.Dir(path) != filepath.Join(tmpDir, "watchr", "sessions") {
+		t.Errorf("unexpected session path %q", path)
+	}
+	if path != SessionPath("ls -la") || path == SessionPath("ls") {
+		t.Errorf("expected session paths to be keyed by command, got %q", path)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "session.json")
+	want := Session{
+		Command:     "kubectl get pods",
+		Shell:       "bash",
+		Interactive: true,
+		Filter:      "Crash.*",
+		FilterKind:  "regex",
+		Notes:       map[string]string{"0123456789abcdef": "first bad request"},
+		SavedAt:     time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	// No temp files should be left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the journal file, found %d entries", len(entries))
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing journal")
+	}
+}
+
+func TestSaveAndLoadLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	if _, ok := LoadLayout(path, "preset:pods"); ok {
+		t.Error("expected no layout before anything is saved")
+	}
+
+	want := []Column{{Name: "NAME", Width: 20}, {Name: "READY", Hidden: true}, {Name: "STATUS"}}
+	if err := SaveLayout(path, "preset:pods", want); err != nil {
+		t.Fatalf("SaveLayout returned error: %v", err)
+	}
+	if err := SaveLayout(path, "ps aux", []Column{{Name: "PID"}}); err != nil {
+		t.Fatalf("SaveLayout returned error: %v", err)
+	}
+	got, ok := LoadLayout(path, "preset:pods")
+	if !ok || !slices.Equal(got, want) {
+		t.Errorf("LoadLayout = %+v, %v; want %+v", got, ok, want)
+	}
+	if got, _ := LoadLayout(path, "ps aux"); len(got) != 1 || got[0].Name != "PID" {
+		t.Errorf("expected the other profile's layout to be kept, got %+v", got)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Latest(dir); err == nil {
+		t.Error("expected an error with no saved sessions")
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, command := range []string{"ps aux", "df -h", "uptime"} {
+		s := Session{Command: command, SavedAt: start.Add(time.Duration(i%2) * time.Hour)}
+		if err := Save(filepath.Join(dir, fmt.Sprintf("%d.json", i)), s); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+	got, err := Latest(dir)
+	if err != nil {
+		t.Fatalf("Latest returned error: %v", err)
+	}
+	if got.Command != "df -h" {
+		t.Errorf("expected the most recently saved session, got %q", got.Command)
+	}
+}
+
+func TestSaveJournalCap(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	for i := range maxCommands {
+		path := filepath.Join(dir, fmt.Sprintf("%d.json", i))
+		if err := Save(path, Session{Command: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+		modified := start.Add(time.Duration(i) * time.Second)
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0.json")); err != nil {
+		t.Fatalf("expected the journals to be pruned only beyond the cap: %v", err)
+	}
+	if err := Save(filepath.Join(dir, "new.json"), Session{}); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0.json")); !os.IsNotExist(err) {
+		t.Error("expected the least recently saved journal to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.json")); err != nil {
+		t.Errorf("expected the new journal to be kept: %v", err)
+	}
+}
+
+func TestSaveAndLoadCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.json")
+	if _, ok := LoadCommand(path, "ps aux"); ok {
+		t.Error("expected no state before anything is saved")
+	}
+
+	want := Command{Filter: "nginx", FilterKind: "regex", Preview: true, Anchor: "root 1 init", SavedAt: time.Now().UTC()}
+	if err := SaveCommand(path, "ps aux", want); err != nil {
+		t.Fatalf("SaveCommand returned error: %v", err)
+	}
+	if err := SaveCommand(path, "df -h", Command{Filter: "/dev"}); err != nil {
+		t.Fatalf("SaveCommand returned error: %v", err)
+	}
+	got, ok := LoadCommand(path, "ps aux")
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCommand = %+v, %v; want %+v", got, ok, want)
+	}
+	if got, _ := LoadCommand(path, "df -h"); got.Filter != "/dev" {
+		t.Errorf("expected the other command's state to be kept, got %+v", got)
+	}
+}
+
+func TestSaveCommandCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.json")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range maxCommands + 1 {
+		c := Command{SavedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := SaveCommand(path, fmt.Sprintf("cmd %d", i), c); err != nil {
+			t.Fatalf("SaveCommand returned error: %v", err)
+		}
+	}
+	if _, ok := LoadCommand(path, "cmd 0"); ok {
+		t.Error("expected the least recently saved command to be dropped")
+	}
+	if _, ok := LoadCommand(path, fmt.Sprintf("cmd %d", maxCommands)); !ok {
+		t.Error("expected the most recently saved command to be kept")
+	}
+}
@@ -1,19 +1,23 @@
 package ui
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
 )
 
 func (m *model) actionReload() (tea.Model, tea.Cmd) {
-	m.refreshGeneration++
-	cmd := m.startStreaming()
-	return m, tea.Batch(cmd, m.spinnerTickCmd())
+	return m, m.requestRun()
 }
 
 func (m *model) actionReloadClear() (tea.Model, tea.Cmd) {
 	m.lines = nil
+	m.clearMarks()
 	m.updateFiltered()
 	return m.actionReload()
 }
@@ -31,11 +35,12 @@ func (m *model) actionDeleteLine() (tea.Model, tea.Cmd) {
 
 func (m *model) actionClearAllLines() (tea.Model, tea.Cmd) {
 	m.confirmMode = true
-	m.confirmMessage = "Clear all lines? (y/N)"
+	m.confirmMessage = i18n.T("confirm.clear_lines")
 	m.confirmAction = func(m *model) (tea.Model, tea.Cmd) {
 		m.lines = nil
+		m.clearMarks()
 		m.updateFiltered()
-		m.statusMsg = "All lines cleared"
+		m.statusMsg = i18n.T("status.lines_cleared")
 		return m, m.statusTimeoutCmd()
 	}
 	return m, nil
@@ -44,7 +49,7 @@ func (m *model) actionClearAllLines() (tea.Model, tea.Cmd) {
 func (m *model) actionStopCommand() (tea.Model, tea.Cmd) {
 	if m.streaming {
 		m.cancel()
-		m.statusMsg = "Command stopped"
+		m.statusMsg = i18n.T("status.command_stopped")
 		return m, m.statusTimeoutCmd()
 	}
 	return m, nil
@@ -52,27 +57,73 @@ func (m *model) actionStopCommand() (tea.Model, tea.Cmd) {
 
 func (m *model) actionTogglePreview() (tea.Model, tea.Cmd) {
 	m.showPreview = !m.showPreview
+	if !m.showPreview {
+		m.clearPipeOutput()
+	}
+	if !m.showPreview && m.autoPreviewOpened {
+		// Don't reopen automatically for the line the user just closed it on
+		m.autoPreviewDismissed = m.selectedIndex()
+	}
+	m.autoPreviewOpened = false
 	m.adjustOffset()
 	return m, nil
 }
 
 func (m *model) actionIncreasePreview() (tea.Model, tea.Cmd) {
-	if m.showPreview {
-		m.config.PreviewSize += previewSizeStep(m.config.PreviewSizeIsPercent)
-		m.adjustOffset()
+	if !m.showPreview {
+		return m, nil
 	}
-	return m, nil
+	size := m.config.PreviewSize + previewSizeStep(m.config.PreviewSizeIsPercent)
+	if limit := m.maxPreviewSize(); limit > 0 {
+		// Stop growing once the list is down to one row or column
+		size = max(min(size, limit), m.config.PreviewSize)
+	}
+	m.config.PreviewSize = size
+	return m.previewResized()
 }
 
 func (m *model) actionDecreasePreview() (tea.Model, tea.Cmd) {
-	if m.showPreview {
-		step := previewSizeStep(m.config.PreviewSizeIsPercent)
-		if m.config.PreviewSize > step {
-			m.config.PreviewSize -= step
-			m.adjustOffset()
-		}
+	if !m.showPreview {
+		return m, nil
 	}
-	return m, nil
+	step := previewSizeStep(m.config.PreviewSizeIsPercent)
+	if m.config.PreviewSize > step {
+		m.config.PreviewSize -= step
+	}
+	return m.previewResized()
+}
+
+// maxPreviewSize returns the largest PreviewSize, in its configured unit,
+// that still leaves room for the list, or 0 before the screen size is known.
+func (m model) maxPreviewSize() int {
+	if m.width == 0 || m.height == 0 {
+		return 0
+	}
+	cells := m.maxPreviewCells()
+	if !m.config.PreviewSizeIsPercent {
+		return cells
+	}
+	total := m.height
+	if m.config.PreviewPosition == PreviewLeft || m.config.PreviewPosition == PreviewRight {
+		total = m.width
+	}
+	return cells * 100 / total
+}
+
+// previewResized re-fits the list and preview after a resize and reports the
+// new size.
+func (m *model) previewResized() (tea.Model, tea.Cmd) {
+	m.adjustOffset()
+	m.clampPreviewOffset()
+	format := "status.preview_rows"
+	switch {
+	case m.config.PreviewSizeIsPercent:
+		format = "status.preview_percent"
+	case m.config.PreviewPosition == PreviewLeft || m.config.PreviewPosition == PreviewRight:
+		format = "status.preview_columns"
+	}
+	m.statusMsg = i18n.T(format, m.config.PreviewSize)
+	return m, m.statusTimeoutCmd()
 }
 
 func (m *model) actionGoToFirst() (tea.Model, tea.Cmd) {
@@ -93,6 +144,15 @@ func (m *model) actionGoToLast() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// actionGoToLine moves the cursor to the nth visible line, clamped to the list.
+func (m *model) actionGoToLine(n int) (tea.Model, tea.Cmd) {
+	m.userScrolled = true
+	m.previewOffset = 0
+	m.cursor = 0
+	m.moveCursor(n - 1)
+	return m, nil
+}
+
 func (m *model) actionEnterFilter() (tea.Model, tea.Cmd) {
 	m.filterMode = true
 	m.filterInput.Cursor = len(m.filterInput.Text)
@@ -101,27 +161,53 @@ func (m *model) actionEnterFilter() (tea.Model, tea.Cmd) {
 
 func (m *model) actionToggleRegexFilter() (tea.Model, tea.Cmd) {
 	m.filterMode = true
-	m.filterRegex = !m.filterRegex
-	m.filterRegexErr = nil
+	m.toggleFilterKind(filter.Regex)
 	m.filterInput.Cursor = len(m.filterInput.Text)
+	return m, nil
+}
+
+func (m *model) actionToggleGlobFilter() (tea.Model, tea.Cmd) {
+	m.filterMode = true
+	m.toggleFilterKind(filter.Glob)
+	m.filterInput.Cursor = len(m.filterInput.Text)
+	return m, nil
+}
+
+// actionCycleCase steps through the case modes for filters and search.
+func (m *model) actionCycleCase() (tea.Model, tea.Cmd) {
+	i := slices.Index(filter.Cases, m.filterCase)
+	m.filterCase = filter.Cases[(i+1)%len(filter.Cases)]
+	m.pinnedFilters = restoreFilters(m.savedFilters(), m.filterCase)
 	m.updateFiltered()
+	m.statusMsg = i18n.T("status.case", m.filterCase)
+	return m, m.statusTimeoutCmd()
+}
+
+func (m *model) actionToggleFuzzyFilter() (tea.Model, tea.Cmd) {
+	m.filterMode = true
+	m.toggleFilterKind(filter.Fuzzy)
+	m.filterInput.Cursor = len(m.filterInput.Text)
 	return m, nil
 }
 
+func (m *model) actionEditFilter() (tea.Model, tea.Cmd) {
+	return m, m.editFilterCmd()
+}
+
 func (m *model) actionCopyLine(plain bool) (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		return m.copyMarked(plain)
+	}
 	if len(m.filtered) > 0 && m.cursor >= 0 && m.cursor < len(m.filtered) {
 		idx := m.filtered[m.cursor]
 		if idx < len(m.lines) {
-			content := m.lines[idx].Content
-			if plain {
-				content = stripANSI(content)
-			}
-			if err := copyToClipboard(content); err != nil {
-				m.statusMsg = "Failed to copy"
+			content := m.formatYank(m.lines[idx:idx+1], plain, m.config.YankFormat)
+			if err := m.yank(content); err != nil {
+				m.statusMsg = i18n.T("error.copy_failed")
 			} else if plain {
-				m.statusMsg = "Copied to clipboard (plain)"
+				m.statusMsg = i18n.T("status.copied_plain") + m.registerNote()
 			} else {
-				m.statusMsg = "Copied to clipboard"
+				m.statusMsg = i18n.T("status.copied") + m.registerNote()
 			}
 			return m, m.statusTimeoutCmd()
 		}
@@ -129,12 +215,64 @@ func (m *model) actionCopyLine(plain bool) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// actionCopyRendered copies the selected line as rendered on screen rather than
+// its logical content, for pasting output exactly as it looks.
+func (m *model) actionCopyRendered() (tea.Model, tea.Cmd) {
+	if len(m.filtered) > 0 && m.cursor >= 0 && m.cursor < len(m.filtered) {
+		idx := m.filtered[m.cursor]
+		if idx < len(m.lines) {
+			content := strings.Join(m.renderedRows(m.lines[idx]), "\n")
+			if err := m.yank(content); err != nil {
+				m.statusMsg = i18n.T("error.copy_failed")
+			} else {
+				m.statusMsg = i18n.T("status.copied_rendered") + m.registerNote()
+			}
+			return m, m.statusTimeoutCmd()
+		}
+	}
+	return m, nil
+}
+
+// actionCopyFiltered copies every line matching the active filter, as plain
+// text.
+func (m *model) actionCopyFiltered() (tea.Model, tea.Cmd) {
+	lines := make([]runner.Line, 0, len(m.filtered))
+	for _, idx := range m.filtered {
+		if idx < len(m.lines) {
+			lines = append(lines, m.lines[idx])
+		}
+	}
+	return m.copyLines(lines, true, "")
+}
+
+// actionCopyAll copies the entire output as plain text, ignoring any filter.
+func (m *model) actionCopyAll() (tea.Model, tea.Cmd) {
+	return m.copyLines(m.lines, true, i18n.T("status.all_output"))
+}
+
+// copyLines copies lines joined by newlines, formatted as configured, and
+// reports how many were copied, with note appended to the status.
+func (m *model) copyLines(lines []runner.Line, plain bool, note string) (tea.Model, tea.Cmd) {
+	if len(lines) == 0 {
+		m.statusMsg = i18n.T("status.nothing_to_copy")
+		return m, m.statusTimeoutCmd()
+	}
+	if err := m.yank(m.formatYank(lines, plain, m.config.YankFormat)); err != nil {
+		m.statusMsg = i18n.T("error.copy_failed")
+	} else {
+		m.statusMsg = i18n.T("status.copied_lines", len(lines), note) + m.registerNote()
+	}
+	return m, m.statusTimeoutCmd()
+}
+
 func (m *model) actionShowHelp() (tea.Model, tea.Cmd) {
 	m.showHelp = true
 	return m, nil
 }
 
 func (m *model) actionQuit() (tea.Model, tea.Cmd) {
+	m.saveJournal()
+	m.saveCommandState()
 	m.cancel()
 	return m, tea.Quit
 }
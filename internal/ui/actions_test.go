@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/chenasraf/watchr/internal/runner"
 )
 
@@ -172,6 +175,70 @@ func TestActionCopyLinePlain(t *testing.T) {
 	}
 }
 
+func TestActionCopyFilteredAndAll(t *testing.T) {
+	m := testModelWithLines()
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+
+	// Status depends on clipboard availability, but never claims all 4 lines
+	_, cmd := m.actionCopyFiltered()
+	if cmd == nil || strings.Contains(m.statusMsg, "4 lines") {
+		t.Errorf("expected only the filtered lines to be copied, got %q", m.statusMsg)
+	}
+
+	_, cmd = m.actionCopyAll()
+	if cmd == nil || m.statusMsg == "" {
+		t.Error("expected a status message and timeout command")
+	}
+	if m.statusMsg != "Failed to copy" && m.statusMsg != "Copied 4 lines to clipboard (all output)" {
+		t.Errorf("expected the whole output to be copied, got %q", m.statusMsg)
+	}
+
+	m.lines = nil
+	m.updateFiltered()
+	m.actionCopyAll()
+	if m.statusMsg != "Nothing to copy" {
+		t.Errorf("expected nothing to copy on empty output, got %q", m.statusMsg)
+	}
+}
+
+func TestRenderedRows(t *testing.T) {
+	m := testModelWithLines()
+	m.width = 20
+	m.config.ShowLineNums = true
+	m.config.LineNumWidth = 3
+	line := runner.Line{Number: 7, Content: "a very long red line"}
+
+	rows := m.renderedRows(line)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	// list width is 20 - 2 borders - 1 = 17; gutter "  7  " takes 5
+	want := "  7  a very long…"
+	if rows[0] != want {
+		t.Errorf("expected %q, got %q", want, rows[0])
+	}
+
+	m.config.ShowLineNums = false
+	rows = m.renderedRows(line)
+	if rows[0] != "a very long red …" {
+		t.Errorf("expected truncated content without gutter, got %q", rows[0])
+	}
+}
+
+func TestActionCopyRendered(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 0
+
+	_, cmd := m.actionCopyRendered()
+	if m.statusMsg == "" {
+		t.Error("expected statusMsg to be set")
+	}
+	if cmd == nil {
+		t.Error("expected timeout command")
+	}
+}
+
 func TestActionShowHelp(t *testing.T) {
 	m := testModelWithLines()
 	m.actionShowHelp()
@@ -195,3 +262,48 @@ func TestActionEnterFilter(t *testing.T) {
 		t.Error("expected filterMode true")
 	}
 }
+
+func TestActionPreviewResizeLimit(t *testing.T) {
+	m := testModelWithLines()
+	m.showPreview = true
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 20
+	m.config.PreviewSizeIsPercent = false
+
+	for range 10 {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	}
+	if m.config.PreviewSize != m.height-7 {
+		t.Errorf("expected the preview to stop growing at %d rows, got %d", m.height-7, m.config.PreviewSize)
+	}
+	if m.visibleLines() < 1 {
+		t.Errorf("expected the list to keep a row, got %d", m.visibleLines())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if m.config.PreviewSize != m.height-9 {
+		t.Errorf("expected < to shrink the preview, got %d", m.config.PreviewSize)
+	}
+	if m.statusMsg != "Preview size: 21 rows" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+
+	m.config.PreviewSizeIsPercent = true
+	m.config.PreviewSize = 60
+	for range 10 {
+		m.actionIncreasePreview()
+	}
+	if m.config.PreviewSize != 76 {
+		t.Errorf("expected the percentage to stop at 76, got %d", m.config.PreviewSize)
+	}
+}
+
+func TestCopyRenderedKey(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 0
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	if m.statusMsg == "" {
+		t.Error("expected V to copy the line as rendered")
+	}
+}
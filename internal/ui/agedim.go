@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// ageColors are the grays lines fade through as they age, spread across
+// however many buckets DimAge has
+var ageColors = []lipgloss.Color{"250", "247", "244", "241", "238"}
+
+// ageTickMsg redraws the list so lines dim as they age
+type ageTickMsg struct{}
+
+func ageTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return ageTickMsg{}
+	})
+}
+
+// ageBucket returns how many of the DimAge buckets line is older than.
+func (m model) ageBucket(line runner.Line) int {
+	if len(m.config.DimAge) == 0 || line.Time.IsZero() {
+		return 0
+	}
+	age := time.Since(line.Time)
+	n := 0
+	for _, d := range m.config.DimAge {
+		if age >= d {
+			n++
+		}
+	}
+	return n
+}
+
+// dimByAge renders content dimmer the older line is, so the freshest output
+// stands out. Lines in the last bucket take the darkest gray.
+func (m model) dimByAge(line runner.Line, content string) string {
+	n := m.ageBucket(line)
+	if n == 0 {
+		return content
+	}
+	color := ageColors[n*(len(ageColors)-1)/len(m.config.DimAge)]
+	return lipgloss.NewStyle().Foreground(color).Render(stripANSI(content))
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDimByAge(t *testing.T) {
+	m := testModelWithLines()
+	line := m.lines[1]
+	if got := m.dimByAge(line, line.Content); got != line.Content {
+		t.Errorf("expected no dimming without DimAge, got %q", got)
+	}
+
+	m.config.DimAge = []time.Duration{10 * time.Second, time.Minute}
+	line.Time = time.Now()
+	if m.ageBucket(line) != 0 {
+		t.Error("expected a fresh line not to be dimmed")
+	}
+	line.Time = time.Now().Add(-30 * time.Second)
+	if m.ageBucket(line) != 1 {
+		t.Errorf("expected the first bucket, got %d", m.ageBucket(line))
+	}
+	line.Time = time.Now().Add(-5 * time.Minute)
+	if m.ageBucket(line) != 2 {
+		t.Errorf("expected the last bucket, got %d", m.ageBucket(line))
+	}
+	if got := m.dimByAge(line, line.Content); !strings.Contains(stripANSI(got), "foo bar") {
+		t.Errorf("expected the content to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// checkAnomaly records the line count of a finished run and flags it in the
+// header when it is far off the recent average, ringing the terminal bell
+// when AnomalyAlert is set.
+func (m *model) checkAnomaly(count int) tea.Cmd {
+	anomalous, mean := m.lineCounts.Observe(count)
+	if !anomalous {
+		m.anomaly = ""
+		return nil
+	}
+	m.anomaly = i18n.T("view.anomaly_lines", count, mean)
+	if !m.config.AnomalyAlert {
+		return nil
+	}
+	m.statusMsg = i18n.T("status.anomaly", m.anomaly)
+	return tea.Batch(m.bellCmd(), m.statusTimeoutCmd())
+}
+
+// bellCmd rings the terminal bell on the terminal the UI is drawn on, which
+// isn't stdout with PrintSelection.
+func (m model) bellCmd() tea.Cmd {
+	out := m.out
+	if out == nil {
+		out = os.Stdout
+	}
+	return func() tea.Msg {
+		_, _ = fmt.Fprint(out, "\a")
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/anomaly"
+)
+
+func TestCheckAnomaly(t *testing.T) {
+	m := testModelWithLines()
+	for range anomaly.MinSamples {
+		m.checkAnomaly(4)
+	}
+	if cmd := m.checkAnomaly(100); cmd != nil {
+		t.Error("expected no alert without AnomalyAlert")
+	}
+	if m.anomaly == "" {
+		t.Fatal("expected anomaly to be recorded")
+	}
+	if header := m.renderHeaderLine(80); !strings.Contains(header, "anomaly: 100 lines") {
+		t.Errorf("expected anomaly badge in header, got %q", header)
+	}
+
+	m.checkAnomaly(4)
+	if m.anomaly != "" {
+		t.Errorf("expected anomaly to clear on a normal run, got %q", m.anomaly)
+	}
+}
+
+func TestCheckAnomalyAlert(t *testing.T) {
+	m := testModel(Config{Command: "echo", Shell: "sh", AnomalyAlert: true})
+	for range anomaly.MinSamples {
+		m.checkAnomaly(4)
+	}
+	if cmd := m.checkAnomaly(100); cmd == nil {
+		t.Error("expected an alert command with AnomalyAlert")
+	}
+	if !strings.Contains(m.statusMsg, "anomaly") {
+		t.Errorf("expected status message about the anomaly, got %q", m.statusMsg)
+	}
+}
+
+func TestBellOutput(t *testing.T) {
+	var out strings.Builder
+	m := testModel(Config{Command: "true", Shell: "sh"})
+	m.out = &out
+	m.bellCmd()()
+	if out.String() != "\a" {
+		t.Errorf("expected the bell on the UI's output, got %q", out.String())
+	}
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// startAppending keeps the output so far when a run starts in Append mode,
+// under a separator with the run's number and start time. Only the last
+// AppendRuns runs are kept; line numbers carry on past the dropped ones, so
+// marks stay on their lines.
+func (m *model) startAppending() {
+	if !m.config.Append {
+		return
+	}
+	kept := m.lines
+	if keep := m.config.AppendRuns; keep > 0 && len(m.appendRuns) >= keep {
+		drop := len(m.appendRuns) - keep + 1
+		cut := len(kept)
+		if drop < len(m.appendRuns) {
+			first := m.appendRuns[drop]
+			cut = slices.IndexFunc(kept, func(l runner.Line) bool { return l.Number >= first })
+			if cut < 0 {
+				cut = len(kept)
+			}
+		}
+		kept = kept[cut:]
+		m.appendRuns = slices.Delete(m.appendRuns, 0, drop)
+	}
+	// Separators are numbered in order, even after lines are cleared
+	number := 1
+	if len(m.lines) > 0 {
+		number = m.lines[len(m.lines)-1].Number + 1
+	}
+	if n := len(m.appendRuns); n > 0 {
+		number = max(number, m.appendRuns[n-1]+1)
+	}
+	base := make([]runner.Line, len(kept), len(kept)+1)
+	copy(base, kept)
+	separator := i18n.T("view.run_separator", m.runCount, m.config.TimeFormat.format(m.runStartTime, time.DateTime))
+	m.appendBase = append(base, runner.Line{Number: number, Content: separator, Time: m.runStartTime})
+	m.appendRuns = append(m.appendRuns, number)
+}
+
+// withAppended returns a run's lines after the previous runs' output in
+// Append mode, numbered on from it, or just the run's lines otherwise.
+func (m model) withAppended(run []runner.Line) []runner.Line {
+	if !m.config.Append {
+		return run
+	}
+	var offset int
+	if len(m.appendBase) > 0 {
+		offset = m.appendBase[len(m.appendBase)-1].Number
+	}
+	lines := make([]runner.Line, len(m.appendBase), len(m.appendBase)+len(run))
+	copy(lines, m.appendBase)
+	for _, line := range run {
+		line.Number += offset
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// runLines returns the lines of the latest run: in Append mode, those after
+// the previous runs' output.
+func (m model) runLines() []runner.Line {
+	if !m.config.Append || len(m.appendBase) > len(m.lines) {
+		return m.lines
+	}
+	return m.lines[len(m.appendBase):]
+}
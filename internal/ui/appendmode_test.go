@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// finishRun completes a run of m with the given output, as startStreaming
+// and the stream's last tick would.
+func finishRun(m *model, output ...string) {
+	m.runCount++
+	m.runStartTime = time.Now()
+	m.startAppending()
+	lines := make([]runner.Line, len(output))
+	for i, content := range output {
+		lines[i] = runner.Line{Number: i + 1, Content: content}
+	}
+	m.streaming = true
+	m.streamResult = &runner.StreamingResult{Lines: &lines, Done: true, CurrentLineCount: len(lines)}
+	m.Update(streamTickMsg{generation: m.refreshGeneration})
+}
+
+func TestAppendMode(t *testing.T) {
+	m := testModel(Config{Command: "date", Shell: "sh", Append: true})
+	m.width, m.height = 80, 30
+
+	finishRun(m, "12:00")
+	finishRun(m, "12:05", "12:05:30")
+	if len(m.lines) != 5 {
+		t.Fatalf("expected both runs' output under separators, got %d lines: %v", len(m.lines), m.lines)
+	}
+	for i, want := range []string{"── run 1 · ", "12:00", "── run 2 · ", "12:05", "12:05:30"} {
+		if !strings.HasPrefix(m.lines[i].Content, want) {
+			t.Errorf("line %d: expected %q, got %q", i, want, m.lines[i].Content)
+		}
+		if m.lines[i].Number != i+1 {
+			t.Errorf("line %d: expected number %d, got %d", i, i+1, m.lines[i].Number)
+		}
+	}
+	if run := m.runLines(); len(run) != 2 || run[0].Content != "12:05" {
+		t.Errorf("expected the latest run's lines, got %v", run)
+	}
+}
+
+func TestReplaceMode(t *testing.T) {
+	m := testModel(Config{Command: "date", Shell: "sh"})
+	m.width, m.height = 80, 30
+
+	finishRun(m, "12:00")
+	finishRun(m, "12:05")
+	if len(m.lines) != 1 || m.lines[0].Content != "12:05" {
+		t.Errorf("expected only the latest run's output, got %v", m.lines)
+	}
+}
+
+func TestAppendRuns(t *testing.T) {
+	m := testModel(Config{Command: "date", Shell: "sh", Append: true, AppendRuns: 2})
+	m.width, m.height = 80, 30
+
+	finishRun(m, "12:00")
+	finishRun(m, "12:05", "12:05:30")
+	m.marked = map[int]bool{5: true}
+	finishRun(m, "12:10")
+	if len(m.lines) != 5 {
+		t.Fatalf("expected the last 2 runs, got %d lines: %v", len(m.lines), m.lines)
+	}
+	for i, want := range []string{"── run 2 · ", "12:05", "12:05:30", "── run 3 · ", "12:10"} {
+		if !strings.HasPrefix(m.lines[i].Content, want) {
+			t.Errorf("line %d: expected %q, got %q", i, want, m.lines[i].Content)
+		}
+		if m.lines[i].Number != i+3 {
+			t.Errorf("line %d: expected numbers to carry on at %d, got %d", i, i+3, m.lines[i].Number)
+		}
+	}
+	if !m.marked[m.lines[2].Number] {
+		t.Error("expected the mark to stay on its line")
+	}
+
+	m.lines = nil
+	finishRun(m, "12:15")
+	if len(m.lines) != 2 || m.lines[0].Number <= 6 {
+		t.Errorf("expected numbering to carry on after clearing, got %v", m.lines)
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// BindAction defines how a bound command is run
+type BindAction string
+
+const (
+	BindExecute BindAction = "execute"        // run with the terminal, suspending the UI until it exits
+	BindSilent  BindAction = "execute-silent" // run in the background, reporting only failures
+	BindPager   BindAction = "pager"          // run with its output shown in $PAGER
+	BindReload  BindAction = "reload"         // replace the watched command and re-run
+)
+
+// BindActions lists the valid binding actions
+var BindActions = []BindAction{BindExecute, BindSilent, BindPager, BindReload}
+
+// Binding is a command template bound to a key. {} in Command is replaced by
+// the selected line and {N} by its Nth field.
+type Binding struct {
+	Action  BindAction
+	Command string
+}
+
+// ParseBindings parses fzf-style bindings, mapping keys like "ctrl-o" to
+// actions like "execute(kubectl describe pod {1})". Keys are returned in the
+// form key events are matched against, e.g. "ctrl+o".
+func ParseBindings(specs map[string]string) (map[string]Binding, error) {
+	bindings := make(map[string]Binding, len(specs))
+	for key, spec := range specs {
+		b, err := parseBinding(spec)
+		if err != nil {
+			return nil, fmt.Errorf("bind %s: %w", key, err)
+		}
+		bindings[bindKey(key)] = b
+	}
+	return bindings, nil
+}
+
+// parseBinding parses an action spec of the form action(command).
+func parseBinding(spec string) (Binding, error) {
+	spec = strings.TrimSpace(spec)
+	open := strings.IndexByte(spec, '(')
+	if open < 0 || !strings.HasSuffix(spec, ")") {
+		return Binding{}, fmt.Errorf("invalid action %q (expected e.g. execute(command))", spec)
+	}
+	b := Binding{
+		Action:  BindAction(strings.TrimSpace(spec[:open])),
+		Command: strings.TrimSpace(spec[open+1 : len(spec)-1]),
+	}
+	if !slices.Contains(BindActions, b.Action) {
+		return Binding{}, fmt.Errorf("unknown action %q (expected execute, execute-silent, pager or reload)", b.Action)
+	}
+	if b.Command == "" {
+		return Binding{}, fmt.Errorf("%s has no command", b.Action)
+	}
+	return b, nil
+}
+
+// bindKey converts fzf-style key names ("ctrl-o", "alt-enter") to
+// bubbletea's ("ctrl+o", "alt+enter").
+func bindKey(key string) string {
+	key = strings.TrimSpace(key)
+	for _, mod := range []string{"ctrl-", "alt-", "shift-"} {
+		if strings.HasPrefix(key, mod) && len(key) > len(mod) {
+			return strings.TrimSuffix(mod, "-") + "+" + bindKey(key[len(mod):])
+		}
+	}
+	return key
+}
+
+// bindDoneMsg reports that a bound command has finished
+type bindDoneMsg struct {
+	err    error
+	output string // first line of output, shown when the command fails
+}
+
+// runBinding runs a bound command for the selected line.
+func (m *model) runBinding(b Binding) (tea.Model, tea.Cmd) {
+	line, _ := m.selectedPlain()
+	command := runner.PreviewCommand(m.config.Shell, b.Command, line)
+	done := func(err error) tea.Msg { return bindDoneMsg{err: err} }
+
+	switch b.Action {
+	case BindExecute:
+		return m, tea.ExecProcess(exec.Command(m.config.Shell, runner.ShellFlag(m.config.Shell), command), done)
+	case BindPager:
+		pager := os.Getenv("PAGER")
+		if pager == "" {
+			pager = "less -R"
+		}
+		return m, tea.ExecProcess(exec.Command(m.config.Shell, runner.ShellFlag(m.config.Shell), command+" | "+pager), done)
+	case BindSilent:
+		r := m.runner
+		return m, func() tea.Msg {
+			out, err := r.RunPreview(context.Background(), command)
+			first, _, _ := strings.Cut(out, "\n")
+			return bindDoneMsg{err: err, output: first}
+		}
+	case BindReload:
+		m.config.Command = command
+		m.runner.Command = command
+		m.statusMsg = i18n.T("status.watching", command)
+		return m, tea.Batch(m.requestRun(), m.statusTimeoutCmd())
+	}
+	return m, nil
+}
+
+// handleBindDone reports a bound command that failed.
+func (m *model) handleBindDone(msg bindDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		return m, nil
+	}
+	m.statusMsg = i18n.T("error.command_failed", msg.err)
+	if msg.output != "" {
+		m.statusMsg += ": " + msg.output
+	}
+	return m, m.statusTimeoutCmd()
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseBindings(t *testing.T) {
+	bindings, err := ParseBindings(map[string]string{
+		"ctrl-o":     "execute(kubectl describe pod {1})",
+		"alt-enter":  " pager( git show {1} ) ",
+		"ctrl-alt-r": "reload(kubectl get pods -n {2})",
+		"x":          "execute-silent(open {})",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]Binding{
+		"ctrl+o":     {BindExecute, "kubectl describe pod {1}"},
+		"alt+enter":  {BindPager, "git show {1}"},
+		"ctrl+alt+r": {BindReload, "kubectl get pods -n {2}"},
+		"x":          {BindSilent, "open {}"},
+	}
+	for key, b := range want {
+		if bindings[key] != b {
+			t.Errorf("%s: expected %+v, got %+v", key, b, bindings[key])
+		}
+	}
+
+	for _, spec := range []string{"execute", "run(ls)", "execute()", "execute(ls"} {
+		if _, err := ParseBindings(map[string]string{"ctrl-o": spec}); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestRunBindingKey(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Bindings = map[string]Binding{"ctrl+o": {BindSilent, "exit 3"}}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if cmd == nil {
+		t.Fatal("expected the bound command to run")
+	}
+	m.Update(cmd())
+	if !strings.HasPrefix(m.statusMsg, "Command failed: exit status 3") {
+		t.Errorf("expected a failure status, got %q", m.statusMsg)
+	}
+}
+
+func TestRunBindingReload(t *testing.T) {
+	m := testModelWithCancel()
+	m.lines = testModelWithLines().lines
+	m.updateFiltered()
+	m.cursor = 1
+
+	m.runBinding(Binding{BindReload, "grep {2} log.txt"})
+	if m.config.Command != "grep bar log.txt" || m.runner.Command != m.config.Command {
+		t.Errorf("expected the watched command to be replaced, got %q / %q", m.config.Command, m.runner.Command)
+	}
+	if m.statusMsg != "Watching: grep bar log.txt" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+}
+
+func TestHandleBindDone(t *testing.T) {
+	m := testModelWithLines()
+	m.handleBindDone(bindDoneMsg{})
+	if m.statusMsg != "" {
+		t.Errorf("expected no status for a successful command, got %q", m.statusMsg)
+	}
+	m.handleBindDone(bindDoneMsg{err: errors.New("exit status 1"), output: "not found"})
+	if m.statusMsg != "Command failed: exit status 1: not found" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Border holds the characters boxes are drawn with, including the junctions
+// where the box is split into the header, list and preview.
+type Border struct {
+	TopLeft, TopRight, BottomLeft, BottomRight string
+	Horizontal, Vertical                       string
+	LeftT, RightT, TopT, BottomT               string
+	Thumb                                      string // scrollbar thumb
+}
+
+// Borders are the border styles, by name
+var Borders = map[string]Border{
+	"rounded": {
+		TopLeft: "╭", TopRight: "╮", BottomLeft: "╰", BottomRight: "╯", Horizontal: "─", Vertical: "│",
+		LeftT: "├", RightT: "┤", TopT: "┬", BottomT: "┴", Thumb: "┃",
+	},
+	"square": {
+		TopLeft: "┌", TopRight: "┐", BottomLeft: "└", BottomRight: "┘", Horizontal: "─", Vertical: "│",
+		LeftT: "├", RightT: "┤", TopT: "┬", BottomT: "┴", Thumb: "┃",
+	},
+	"double": {
+		TopLeft: "╔", TopRight: "╗", BottomLeft: "╚", BottomRight: "╝", Horizontal: "═", Vertical: "║",
+		LeftT: "╠", RightT: "╣", TopT: "╦", BottomT: "╩", Thumb: "┃",
+	},
+	// For terminals and fonts that render box drawing characters poorly
+	"ascii": {
+		TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+", Horizontal: "-", Vertical: "|",
+		LeftT: "+", RightT: "+", TopT: "+", BottomT: "+", Thumb: "#",
+	},
+	// Blank space where the lines would be, so the layout stays the same
+	"none": {
+		TopLeft: " ", TopRight: " ", BottomLeft: " ", BottomRight: " ", Horizontal: " ", Vertical: " ",
+		LeftT: " ", RightT: " ", TopT: " ", BottomT: " ", Thumb: "┃",
+	},
+}
+
+// BorderNames returns the names of the border styles, sorted.
+func BorderNames() []string {
+	names := make([]string, 0, len(Borders))
+	for name := range Borders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseBorder returns the named border style; empty means rounded.
+func ParseBorder(name string) (Border, error) {
+	if name == "" {
+		name = "rounded"
+	}
+	b, ok := Borders[name]
+	if !ok {
+		return Border{}, fmt.Errorf("unknown border style %q (expected %s)", name, strings.Join(BorderNames(), ", "))
+	}
+	return b, nil
+}
+
+// lipgloss returns the border for lipgloss-styled overlay boxes.
+func (b Border) lipgloss() lipgloss.Border {
+	return lipgloss.Border{
+		Top: b.Horizontal, Bottom: b.Horizontal, Left: b.Vertical, Right: b.Vertical,
+		TopLeft: b.TopLeft, TopRight: b.TopRight, BottomLeft: b.BottomLeft, BottomRight: b.BottomRight,
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestParseBorder(t *testing.T) {
+	b, err := ParseBorder("")
+	if err != nil || b != Borders["rounded"] {
+		t.Errorf("expected rounded by default, got %v, %v", b, err)
+	}
+	if b, err := ParseBorder("double"); err != nil || b.TopLeft != "╔" {
+		t.Errorf("expected the double border, got %v, %v", b, err)
+	}
+	if _, err := ParseBorder("dotted"); err == nil {
+		t.Error("expected an error for an unknown border style")
+	}
+}
+
+func TestASCIIBorder(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Border = Borders["ascii"]
+	m.config.Scrollbar = true
+	m.showPreview = true
+	m.showHelp = true
+	view := stripANSI(m.View())
+	for _, r := range view {
+		if r > unicode.MaxASCII && strings.ContainsRune("╭╮╰╯─│├┤┬┴┃", r) {
+			t.Fatalf("expected only ASCII box characters, found %q", r)
+		}
+	}
+	if !strings.HasPrefix(view, "+-") {
+		t.Errorf("expected an ASCII top border, got %q", strings.SplitN(view, "\n", 2)[0])
+	}
+}
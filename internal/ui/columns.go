@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+// columnLayout matches the column layout to the current column names, in the
+// layout's order, with columns it doesn't name appended. It returns the
+// layout and each entry's column.
+func (m model) columnLayout() (layout []state.Column, columns []int) {
+	if len(m.lines) == 0 {
+		return nil, nil
+	}
+	names := m.tableCells(m.lines[m.headerLines()-1].Content)
+	used := make([]bool, len(names))
+	for _, col := range m.tableLayout {
+		for i, name := range names {
+			if !used[i] && name == col.Name {
+				used[i] = true
+				layout = append(layout, col)
+				columns = append(columns, i)
+				break
+			}
+		}
+	}
+	for i, name := range names {
+		if !used[i] {
+			layout = append(layout, state.Column{Name: name})
+			columns = append(columns, i)
+		}
+	}
+	return layout, columns
+}
+
+// applyColumnLayout works out which columns table mode shows, in which order
+// and how wide, from the column layout.
+func (m *model) applyColumnLayout() {
+	m.tableShown, m.tableSizes = nil, nil
+	layout, columns := m.columnLayout()
+	for n, col := range layout {
+		if col.Hidden {
+			continue
+		}
+		m.tableShown = append(m.tableShown, columns[n])
+		if col.Width > 0 {
+			if m.tableSizes == nil {
+				m.tableSizes = map[int]int{}
+			}
+			m.tableSizes[columns[n]] = col.Width
+		}
+	}
+	m.tableCol = max(min(m.tableCol, len(m.tableShown)-1), 0)
+}
+
+func (m *model) actionColumns() (tea.Model, tea.Cmd) {
+	if !m.tableMode {
+		m.statusMsg = i18n.T("status.columns_need_table")
+		return m, m.statusTimeoutCmd()
+	}
+	m.tableLayout, _ = m.columnLayout()
+	if len(m.tableLayout) == 0 {
+		return m, nil
+	}
+	m.columnsMode = true
+	m.columnsCursor = 0
+	return m, nil
+}
+
+// handleColumnsMode handles keys in the column chooser. Changes show in the
+// table behind it as they're made, and the layout is saved when it closes.
+func (m *model) handleColumnsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cur := m.columnsCursor
+	switch msg.String() {
+	case "j", "down":
+		m.columnsCursor = min(cur+1, len(m.tableLayout)-1)
+		return m, nil
+	case "k", "up":
+		m.columnsCursor = max(cur-1, 0)
+		return m, nil
+	case " ":
+		if !m.tableLayout[cur].Hidden && len(m.tableShown) == 1 {
+			return m, nil
+		}
+		m.tableLayout[cur].Hidden = !m.tableLayout[cur].Hidden
+	case "J", "shift+down":
+		if cur == len(m.tableLayout)-1 {
+			return m, nil
+		}
+		m.tableLayout[cur], m.tableLayout[cur+1] = m.tableLayout[cur+1], m.tableLayout[cur]
+		m.columnsCursor++
+	case "K", "shift+up":
+		if cur == 0 {
+			return m, nil
+		}
+		m.tableLayout[cur], m.tableLayout[cur-1] = m.tableLayout[cur-1], m.tableLayout[cur]
+		m.columnsCursor--
+	case "+", "=", ">", "l", "right":
+		m.tableLayout[cur].Width = m.layoutWidth(cur) + 1
+	case "-", "<", "h", "left":
+		m.tableLayout[cur].Width = max(m.layoutWidth(cur)-1, 1)
+	case "0":
+		m.tableLayout[cur].Width = 0
+	case "esc", "enter", "q", "O":
+		m.columnsMode = false
+		return m, m.saveColumnLayout()
+	default:
+		return m, nil
+	}
+	m.updateTableWidths()
+	return m, nil
+}
+
+// layoutWidth returns the width the column chooser's column n is shown at,
+// or its name's width if it's the last column and so has none.
+func (m model) layoutWidth(n int) int {
+	if width := m.tableLayout[n].Width; width > 0 {
+		return width
+	}
+	_, columns := m.columnLayout()
+	return max(m.columnWidth(columns[n]), lipgloss.Width(m.tableLayout[n].Name))
+}
+
+// saveColumnLayout saves the column layout for the profile, reporting a
+// failure in the status bar.
+func (m *model) saveColumnLayout() tea.Cmd {
+	if m.config.LayoutPath == "" {
+		return nil
+	}
+	if err := state.SaveLayout(m.config.LayoutPath, m.config.Profile, m.tableLayout); err != nil {
+		m.statusMsg = i18n.T("error.columns_save_failed", err)
+		return m.statusTimeoutCmd()
+	}
+	return nil
+}
+
+// renderColumnsOverlay lists table mode's columns with whether they're shown
+// and their widths, the one being changed selected.
+func (m model) renderColumnsOverlay() (box string, boxWidth, boxHeight int) {
+	titleStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Header).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Filter)
+	dimStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	selectedStyle := lipgloss.NewStyle().Reverse(true)
+	const nameWidth = 30
+
+	nameCol := 0
+	for _, col := range m.tableLayout {
+		nameCol = max(nameCol, lipgloss.Width(col.Name))
+	}
+	nameCol = min(nameCol, nameWidth)
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(i18n.T("columns.title")) + "\n\n")
+	for n, col := range m.tableLayout {
+		check := "[x]"
+		if col.Hidden {
+			check = "[ ]"
+		}
+		name := truncateToWidth(col.Name, nameCol)
+		name += strings.Repeat(" ", nameCol-lipgloss.Width(name))
+		width := i18n.T("columns.fit")
+		if col.Width > 0 {
+			width = fmt.Sprint(col.Width)
+		}
+		line := check + " " + name + "  " + width
+		if n == m.columnsCursor {
+			line = selectedStyle.Render(line)
+		} else if col.Hidden {
+			line = dimStyle.Render(line)
+		}
+		content.WriteString("  " + line + "\n")
+	}
+
+	hints := [][2]string{
+		{"space", "columns.toggle"},
+		{"J/K", "columns.move"},
+		{"+/-", "columns.resize"},
+		{"0", "columns.reset"},
+	}
+	content.WriteString("\n")
+	for _, hint := range hints {
+		content.WriteString(keyStyle.Render(fmt.Sprintf("%-6s", hint[0])) + dimStyle.Render(i18n.T(hint[1])) + "\n")
+	}
+	content.WriteString("\n" + dimStyle.Render(i18n.T("columns.close")))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(m.config.Border.lipgloss()).
+		BorderForeground(m.config.Theme.Header).
+		Padding(1, 2)
+	box = boxStyle.Render(content.String())
+	return box, lipgloss.Width(box), lipgloss.Height(box)
+}
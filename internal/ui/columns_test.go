@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+func TestColumnsNeedTable(t *testing.T) {
+	m := testModelWithLines()
+	pressKeys(m, "O")
+	if m.columnsMode || m.statusMsg != "The column chooser needs table mode (t)" {
+		t.Errorf("expected the column chooser to need table mode, got %v %q", m.columnsMode, m.statusMsg)
+	}
+}
+
+func TestColumnChooser(t *testing.T) {
+	m := tableModel()
+	m.config.LayoutPath = filepath.Join(t.TempDir(), "layouts.json")
+	m.config.Profile = "ps aux"
+
+	pressKeys(m, "O")
+	if !m.columnsMode || len(m.tableLayout) != 3 {
+		t.Fatalf("expected the column chooser open with 3 columns, got %v %v", m.columnsMode, m.tableLayout)
+	}
+
+	if view := stripANSI(m.View()); !strings.Contains(view, "[x] COMMAND") {
+		t.Errorf("expected the column chooser to list the columns, got %q", view)
+	}
+
+	// Move COMMAND before PID
+	pressKeys(m, "jjK")
+	if got := m.tableRow(m.lines[1].Content); got != "root      /sbin/init splash        1" {
+		t.Errorf("expected COMMAND moved before PID, got %q", got)
+	}
+
+	// Hide COMMAND and narrow USER
+	pressKeys(m, " k-")
+	if got := m.tableRow(m.lines[2].Content); got != "postgr…  4242" {
+		t.Errorf("expected COMMAND hidden and USER narrowed, got %q", got)
+	}
+
+	// The last column shown can't be hidden
+	pressKeys(m, " jj ")
+	if len(m.tableShown) != 1 || !m.tableLayout[0].Hidden || m.tableLayout[2].Hidden {
+		t.Errorf("expected PID to stay shown, got %v", m.tableLayout)
+	}
+	pressKeys(m, "kk ")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.columnsMode {
+		t.Error("expected enter to close the column chooser")
+	}
+	want := []state.Column{{Name: "USER", Width: 7}, {Name: "COMMAND", Hidden: true}, {Name: "PID"}}
+	got, ok := state.LoadLayout(m.config.LayoutPath, "ps aux")
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadLayout = %v, %v; want %v", got, ok, want)
+	}
+
+	// A saved layout is applied by column name when table mode starts
+	m = testModelWithLines()
+	m.tableLayout = want
+	m.lines = []runner.Line{
+		{Number: 1, Content: "PID USER COMMAND"},
+		{Number: 2, Content: "1 root /sbin/init splash"},
+	}
+	pressKeys(m, "t")
+	if got := m.tableRow(m.lines[1].Content); got != "root     1" {
+		t.Errorf("expected the saved layout applied, got %q", got)
+	}
+}
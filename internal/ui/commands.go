@@ -2,16 +2,19 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
 )
 
 // command represents a command palette entry
 type command struct {
-	name     string // display name
+	name     string // display name, in the interface language
 	shortcut string // keybinding hint
 	action   func(m *model) (tea.Model, tea.Cmd)
 }
@@ -19,22 +22,54 @@ type command struct {
 // commands returns the list of available command palette entries.
 func commands() []command {
 	return []command{
-		{"Reload command", "r / Ctrl+r", (*model).actionReload},
-		{"Reload & clear lines", "R", (*model).actionReloadClear},
-		{"Delete selected line", "d / Del", (*model).actionDeleteLine},
-		{"Clear all lines", "D", (*model).actionClearAllLines},
-		{"Stop running command", "c", (*model).actionStopCommand},
-		{"Toggle preview pane", "p", (*model).actionTogglePreview},
-		{"Increase preview size", "+", (*model).actionIncreasePreview},
-		{"Decrease preview size", "-", (*model).actionDecreasePreview},
-		{"Go to first line", "g", (*model).actionGoToFirst},
-		{"Go to last line", "G", (*model).actionGoToLast},
-		{"Enter filter mode", "/", (*model).actionEnterFilter},
-		{"Toggle regex filter", "//", (*model).actionToggleRegexFilter},
-		{"Copy line to clipboard", "y", func(m *model) (tea.Model, tea.Cmd) { return m.actionCopyLine(false) }},
-		{"Copy line (plain text)", "Y", func(m *model) (tea.Model, tea.Cmd) { return m.actionCopyLine(true) }},
-		{"Show help", "?", (*model).actionShowHelp},
-		{"Quit", "q", (*model).actionQuit},
+		{i18n.T("palette.reload_command"), "r / Ctrl+r", (*model).actionReload},
+		{i18n.T("palette.reload_clear_lines"), "R", (*model).actionReloadClear},
+		{i18n.T("palette.delete_selected_line"), "d / Del", (*model).actionDeleteLine},
+		{i18n.T("palette.clear_all_lines"), "D", (*model).actionClearAllLines},
+		{i18n.T("palette.stop_running_command"), "c", (*model).actionStopCommand},
+		{i18n.T("palette.toggle_preview_pane"), "p", (*model).actionTogglePreview},
+		{i18n.T("palette.toggle_context_preview"), "x", (*model).actionToggleContextPreview},
+		{i18n.T("palette.toggle_soft_wrap"), "w", (*model).actionToggleWrap},
+		{i18n.T("palette.toggle_line_numbers"), "#", (*model).actionToggleLineNumbers},
+		{i18n.T("palette.toggle_relative_line_numbers"), "", (*model).actionToggleRelativeNumbers},
+		{i18n.T("palette.toggle_preview_wrap"), "W", (*model).actionTogglePreviewWrap},
+		{i18n.T("palette.search_preview"), "", (*model).actionEnterPreviewSearch},
+		{i18n.T("palette.cycle_duplicate_collapsing"), "u", (*model).actionCycleDedup},
+		{i18n.T("palette.toggle_table_mode"), "t", (*model).actionToggleTable},
+		{i18n.T("palette.choose_columns"), "O", (*model).actionColumns},
+		{i18n.T("palette.pin_header_lines"), "T", (*model).actionToggleHeader},
+		{i18n.T("palette.toggle_title_line"), "Ctrl+t", (*model).actionToggleTitle},
+		{i18n.T("palette.scroll_columns_left"), "H / Left", func(m *model) (tea.Model, tea.Cmd) { return m.actionScrollColumns(-1) }},
+		{i18n.T("palette.scroll_columns_right"), "L / Right", func(m *model) (tea.Model, tea.Cmd) { return m.actionScrollColumns(1) }},
+		{i18n.T("palette.increase_preview_size"), "+ / >", (*model).actionIncreasePreview},
+		{i18n.T("palette.decrease_preview_size"), "- / <", (*model).actionDecreasePreview},
+		{i18n.T("palette.go_to_first_line"), "g", (*model).actionGoToFirst},
+		{i18n.T("palette.go_to_last_line"), "G", (*model).actionGoToLast},
+		{i18n.T("palette.enter_filter_mode"), "/", (*model).actionEnterFilter},
+		{i18n.T("palette.toggle_regex_filter"), "//", (*model).actionToggleRegexFilter},
+		{i18n.T("palette.toggle_glob_filter"), "Tab", (*model).actionToggleGlobFilter},
+		{i18n.T("palette.toggle_fuzzy_filter"), "Tab", (*model).actionToggleFuzzyFilter},
+		{i18n.T("palette.edit_filter_in_editor"), "Ctrl+x", (*model).actionEditFilter},
+		{i18n.T("palette.open_file_in_editor"), "e / Enter", (*model).actionOpenInEditor},
+		{i18n.T("palette.pipe_selection_to_command"), "|", (*model).actionEnterPipe},
+		{i18n.T("palette.pop_pinned_filter"), "P", (*model).actionPopFilter},
+		{i18n.T("palette.search"), "?", (*model).actionEnterSearch},
+		{i18n.T("palette.cycle_case_sensitivity"), "C", (*model).actionCycleCase},
+		{i18n.T("palette.next_search_match"), "n", func(m *model) (tea.Model, tea.Cmd) { return m.actionSearchNext(1) }},
+		{i18n.T("palette.previous_search_match"), "N", func(m *model) (tea.Model, tea.Cmd) { return m.actionSearchNext(-1) }},
+		{i18n.T("palette.copy_line_to_clipboard"), "y", func(m *model) (tea.Model, tea.Cmd) { return m.actionCopyLine(false) }},
+		{i18n.T("palette.copy_line_plain_text"), "Y", func(m *model) (tea.Model, tea.Cmd) { return m.actionCopyLine(true) }},
+		{i18n.T("palette.copy_line_as_rendered"), "V", (*model).actionCopyRendered},
+		{i18n.T("palette.copy_as_markdown_block"), "M", (*model).actionCopyMarkdown},
+		{i18n.T("palette.copy_selected_column"), "f", (*model).actionYankField},
+		{i18n.T("palette.copy_filtered_output"), "Ctrl+y", (*model).actionCopyFiltered},
+		{i18n.T("palette.show_registers"), `""`, (*model).actionShowRegisters},
+		{i18n.T("palette.copy_all_output"), "Alt+y", (*model).actionCopyAll},
+		{i18n.T("palette.write_output_to_file"), ":w / Ctrl+s", (*model).actionEnterWrite},
+		{i18n.T("palette.annotate_selected_line"), "a", (*model).actionEditNote},
+		{i18n.T("palette.next_annotated_line"), "A", (*model).actionNextNote},
+		{i18n.T("palette.show_help"), "h", (*model).actionShowHelp},
+		{i18n.T("palette.quit"), "q", (*model).actionQuit},
 	}
 }
 
@@ -54,26 +89,62 @@ func (m *model) filteredCommands() []command {
 	return result
 }
 
-// copyToClipboard copies text to the system clipboard using OS-specific commands
+// copyToClipboard copies text to the system clipboard and, inside tmux, to a
+// tmux paste buffer too. Loading the tmux buffer is enough to succeed, so
+// yanks can be pasted in tmux even without a clipboard utility.
 func copyToClipboard(text string) error {
-	var cmd *exec.Cmd
+	err := copyToSystemClipboard(text)
+	if os.Getenv("TMUX") != "" {
+		if tmuxErr := copyToTmux(text); tmuxErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// copyToTmux loads text into a new tmux paste buffer.
+func copyToTmux(text string) error {
+	cmd := exec.Command("tmux", "load-buffer", "-")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// copyToSystemClipboard copies text to the system clipboard using OS-specific
+// commands
+func copyToSystemClipboard(text string) error {
+	args := ClipboardCommand()
+	if args == nil {
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
 
+// ClipboardCommand returns the command yanks are copied with on this
+// platform, or nil if it has none.
+func ClipboardCommand() []string {
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("pbcopy")
+		return []string{"pbcopy"}
 	case "linux":
-		// Try xclip first, fall back to xsel
-		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.Command("xclip", "-selection", "clipboard")
-		} else {
-			cmd = exec.Command("xsel", "--clipboard", "--input")
-		}
+		return linuxClipboardArgs(exec.LookPath)
 	case "windows":
-		cmd = exec.Command("clip")
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return []string{"clip"}
 	}
+	return nil
+}
 
-	cmd.Stdin = strings.NewReader(text)
-	return cmd.Run()
+// linuxClipboardArgs picks the clipboard command on Linux: wl-copy under
+// Wayland, then xclip, falling back to xsel.
+func linuxClipboardArgs(lookPath func(string) (string, error)) []string {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := lookPath("wl-copy"); err == nil {
+			return []string{"wl-copy"}
+		}
+	}
+	if _, err := lookPath("xclip"); err == nil {
+		return []string{"xclip", "-selection", "clipboard"}
+	}
+	return []string{"xsel", "--clipboard", "--input"}
 }
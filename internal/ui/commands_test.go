@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
 )
 
 func TestCommandsCount(t *testing.T) {
 	cmds := commands()
-	if len(cmds) != 16 {
-		t.Errorf("expected 16 commands, got %d", len(cmds))
+	if len(cmds) != 48 {
+		t.Errorf("expected 48 commands, got %d", len(cmds))
 	}
 }
 
@@ -57,3 +62,61 @@ func TestCommandPaletteTogglePreview(t *testing.T) {
 		t.Error("expected showPreview true after toggle command")
 	}
 }
+
+func TestLinuxClipboardArgs(t *testing.T) {
+	only := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(names, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name    string
+		wayland string
+		found   []string
+		want    string
+	}{
+		{"wayland", "wayland-0", []string{"wl-copy", "xclip"}, "wl-copy"},
+		{"wayland without wl-copy", "wayland-0", []string{"xclip"}, "xclip"},
+		{"x11 ignores wl-copy", "", []string{"wl-copy", "xclip"}, "xclip"},
+		{"xsel fallback", "", nil, "xsel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WAYLAND_DISPLAY", tt.wayland)
+			if got := linuxClipboardArgs(only(tt.found...)); got[0] != tt.want {
+				t.Errorf("expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCopyToTmux(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake tmux")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "buffer")
+	script := "#!/bin/sh\nPATH=/usr/bin:/bin\n[ \"$1 $2\" = \"load-buffer -\" ] && cat > " + out + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	t.Setenv("TMUX", "")
+	if err := copyToClipboard("outside"); err == nil {
+		t.Error("expected copying to fail without a clipboard utility outside tmux")
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if err := copyToClipboard("pod/api"); err != nil {
+		t.Errorf("expected the tmux buffer to be enough, got %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "pod/api" {
+		t.Errorf("expected the text in the tmux buffer, got %q", got)
+	}
+}
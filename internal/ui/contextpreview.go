@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// defaultPreviewContext is how many lines context previews show around the
+// selected line when PreviewContext isn't set
+const defaultPreviewContext = 5
+
+// contextLines returns how many lines of output the context preview shows
+// above and below the selected line.
+func (m model) contextLines() int {
+	if m.config.PreviewContext > 0 {
+		return m.config.PreviewContext
+	}
+	return defaultPreviewContext
+}
+
+// contextPreview renders the lines of the unfiltered output around lines[idx],
+// with line numbers. The selected line is highlighted and lines the filter
+// hides are dimmed, so matches can be read in their surroundings.
+func (m model) contextPreview(idx int) string {
+	n := m.contextLines()
+	lo, hi := max(idx-n, 0), min(idx+n, len(m.lines)-1)
+
+	visible := make(map[int]bool, hi-lo+1)
+	for _, i := range m.filtered {
+		if i >= lo && i <= hi {
+			visible[i] = true
+		}
+	}
+
+	numStyle := lipgloss.NewStyle().Foreground(m.config.Theme.LineNumber)
+	hiddenStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	selectedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Prompt).Bold(true)
+
+	rows := make([]string, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		line := m.lines[i]
+		num := fmt.Sprintf("%*d ", m.config.LineNumWidth, line.Number)
+		switch {
+		case i == idx:
+			rows = append(rows, selectedStyle.Render(num+stripANSI(line.Content)))
+		case !visible[i]:
+			rows = append(rows, numStyle.Render(num)+hiddenStyle.Render(stripANSI(line.Content)))
+		default:
+			rows = append(rows, numStyle.Render(num)+line.Content)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// actionToggleContextPreview switches the preview between the selected line
+// and the output around it, opening the preview if it's closed.
+func (m *model) actionToggleContextPreview() (tea.Model, tea.Cmd) {
+	m.previewContext = !m.previewContext
+	m.previewOffset = 0
+	if !m.showPreview {
+		m.showPreview = true
+		m.autoPreviewOpened = false
+		m.adjustOffset()
+	}
+	m.statusMsg = i18n.T("status.preview_line")
+	if m.previewContext {
+		m.statusMsg = i18n.T("status.preview_context", m.contextLines())
+	}
+	return m, m.statusTimeoutCmd()
+}
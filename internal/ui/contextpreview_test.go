@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestContextPreview(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", LineNumWidth: 3, PreviewContext: 2})
+	for i := range 10 {
+		m.lines = append(m.lines, runner.Line{Number: i + 1, Content: fmt.Sprintf("line %d", i+1)})
+	}
+	m.width, m.height = 80, 30
+	m.filterInput.Text = "line 5"
+	m.updateFiltered()
+
+	m.actionToggleContextPreview()
+	if !m.showPreview || !m.previewContext {
+		t.Fatal("expected the context preview to open")
+	}
+
+	rows := strings.Split(stripANSI(m.previewContent()), "\n")
+	want := []string{"  3 line 3", "  4 line 4", "  5 line 5", "  6 line 6", "  7 line 7"}
+	if strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Errorf("expected unfiltered lines around the match, got %q", rows)
+	}
+
+	m.actionToggleContextPreview()
+	if m.previewContext || stripANSI(m.previewContent()) != "line 5" {
+		t.Errorf("expected the line preview back, got %q", m.previewContent())
+	}
+}
+
+func TestContextPreviewEdges(t *testing.T) {
+	m := testModelWithLines()
+	m.config.LineNumWidth = 1
+	m.previewContext = true
+
+	rows := strings.Split(stripANSI(m.previewContent()), "\n")
+	if len(rows) != 4 || rows[0] != "1 hello world" {
+		t.Errorf("expected context clamped to the output, got %q", rows)
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// dedupMode controls how duplicate lines are collapsed in the list
+type dedupMode int
+
+const (
+	dedupOff         dedupMode = iota // show every line
+	dedupConsecutive                  // collapse runs of identical lines
+	dedupAll                          // keep only the first of identical lines
+)
+
+func (d dedupMode) String() string {
+	switch d {
+	case dedupConsecutive:
+		return "consecutive"
+	case dedupAll:
+		return "all"
+	}
+	return "off"
+}
+
+// actionCycleDedup steps through off, consecutive and all.
+func (m *model) actionCycleDedup() (tea.Model, tea.Cmd) {
+	m.dedup = (m.dedup + 1) % (dedupAll + 1)
+	m.updateFiltered()
+	m.statusMsg = i18n.T("status.dedup", m.dedup)
+	return m, m.statusTimeoutCmd()
+}
+
+// dedupFiltered collapses duplicate lines in the filtered list, recording
+// how many lines each kept line stands for.
+func (m *model) dedupFiltered() {
+	m.dupCounts = nil
+	if m.dedup == dedupOff || len(m.filtered) == 0 {
+		return
+	}
+	m.dupCounts = make(map[int]int)
+	kept := m.filtered[:0]
+	seen := make(map[string]int) // content -> line number it was folded into
+	prev := ""
+	for _, idx := range m.filtered {
+		if idx >= len(m.lines) {
+			continue
+		}
+		line := m.lines[idx]
+		content := stripANSI(line.Content)
+		var number int
+		var dup bool
+		if m.dedup == dedupAll {
+			number, dup = seen[content]
+		} else if len(kept) > 0 && content == prev {
+			number, dup = m.lines[kept[len(kept)-1]].Number, true
+		}
+		prev = content
+		if dup {
+			m.dupCounts[number]++
+			continue
+		}
+		seen[content] = line.Number
+		m.dupCounts[line.Number] = 1
+		kept = append(kept, idx)
+	}
+	m.filtered = kept
+}
+
+// dupGutter returns the "×N" count shown before a line that stands for
+// several collapsed duplicates.
+func (m model) dupGutter(line runner.Line) string {
+	if n := m.dupCounts[line.Number]; n > 1 {
+		return fmt.Sprintf("×%d ", n)
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func dedupModel() *model {
+	m := testModelWithLines()
+	m.lines = []runner.Line{
+		{Number: 1, Content: "retrying"},
+		{Number: 2, Content: "retrying"},
+		{Number: 3, Content: "\x1b[31mretrying\x1b[0m"},
+		{Number: 4, Content: "connected"},
+		{Number: 5, Content: "retrying"},
+	}
+	m.updateFiltered()
+	return m
+}
+
+func TestDedupCycle(t *testing.T) {
+	m := dedupModel()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if want := []int{0, 3, 4}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected consecutive duplicates to collapse to %v, got %v", want, m.filtered)
+	}
+	if m.dupCounts[1] != 3 || m.dupCounts[5] != 1 {
+		t.Errorf("expected counts of 3 and 1, got %v", m.dupCounts)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if want := []int{0, 3}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected all duplicates to collapse to %v, got %v", want, m.filtered)
+	}
+	if m.dupCounts[1] != 4 {
+		t.Errorf("expected the first line to stand for 4, got %v", m.dupCounts)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if len(m.filtered) != 5 || m.dupCounts != nil {
+		t.Errorf("expected dedup off to show every line, got %v", m.filtered)
+	}
+	if m.statusMsg != "Dedup: off" {
+		t.Errorf("expected the mode in the status, got %q", m.statusMsg)
+	}
+}
+
+func TestDedupCountGutter(t *testing.T) {
+	m := dedupModel()
+	m.dedup = dedupConsecutive
+	m.updateFiltered()
+
+	lines := m.renderListLines(3, 40)
+	if got := stripANSI(lines[0]); !strings.HasPrefix(got, "×3 retrying") {
+		t.Errorf("expected the count before the collapsed line, got %q", got)
+	}
+	if got := stripANSI(lines[2]); strings.Contains(got, "×") {
+		t.Errorf("expected no count on a single line, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// maxLCSCells caps the size of the LCS table. Larger changed regions fall back
+// to a content-membership comparison, which is cheaper but less precise.
+const maxLCSCells = 1 << 22
+
+// diffNewLines reports, for each line in curr, whether it is new compared to
+// prev. Lines are anchored by content using a longest common subsequence, so
+// an inserted line doesn't mark every line below it as changed (as a
+// positional comparison would when output scrolls).
+func diffNewLines(prev, curr []string) []bool {
+	changed := make([]bool, len(curr))
+
+	// Skip the common prefix and suffix; they're unchanged by definition
+	start := 0
+	for start < len(prev) && start < len(curr) && prev[start] == curr[start] {
+		start++
+	}
+	endPrev, endCurr := len(prev), len(curr)
+	for endPrev > start && endCurr > start && prev[endPrev-1] == curr[endCurr-1] {
+		endPrev--
+		endCurr--
+	}
+
+	a := prev[start:endPrev]
+	b := curr[start:endCurr]
+	if len(b) == 0 {
+		return changed
+	}
+	if len(a) == 0 {
+		for i := range b {
+			changed[start+i] = true
+		}
+		return changed
+	}
+
+	if len(a)*len(b) > maxLCSCells {
+		// Too large for a full LCS: treat a line as new if its content
+		// doesn't appear (often enough) in the previous region
+		counts := make(map[string]int, len(a))
+		for _, line := range a {
+			counts[line]++
+		}
+		for i, line := range b {
+			if counts[line] > 0 {
+				counts[line]--
+			} else {
+				changed[start+i] = true
+			}
+		}
+		return changed
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:], stored row-major
+	cols := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*cols)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*cols+j] = lcs[(i+1)*cols+j+1] + 1
+			} else {
+				lcs[i*cols+j] = max(lcs[(i+1)*cols+j], lcs[i*cols+j+1])
+			}
+		}
+	}
+
+	// Walk the table: lines of b that aren't part of the LCS are new
+	i, j := 0, 0
+	for j < len(b) {
+		switch {
+		case i < len(a) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && lcs[(i+1)*cols+j] >= lcs[i*cols+j+1]:
+			i++
+		default:
+			changed[start+j] = true
+			j++
+		}
+	}
+	return changed
+}
+
+// changeStyle highlights lines that changed since the previous run
+var changeStyle = lipgloss.NewStyle().Reverse(true)
+
+// markChanges compares the finished run with the previous one and records
+// the lines that changed, for Differences and HighlightNew. The first run
+// has nothing to compare against, so nothing is marked.
+func (m *model) markChanges() {
+	if !m.config.Differences && m.config.HighlightNew <= 0 {
+		return
+	}
+	run := m.runLines()
+	curr := make([]string, len(run))
+	for i, line := range run {
+		curr[i] = stripANSI(line.Content)
+	}
+	prev, primed := m.prevRunLines, m.prevRunPrimed
+	m.prevRunLines, m.prevRunPrimed = curr, true
+	m.changedLines = nil
+	if !primed {
+		return
+	}
+	for i, changed := range diffNewLines(prev, curr) {
+		if changed {
+			if m.changedLines == nil {
+				m.changedLines = make(map[int]bool)
+			}
+			m.changedLines[run[i].Number] = true
+		}
+	}
+}
+
+// highlightChange renders content highlighted when line changed since the
+// previous run, as watch -d does.
+func (m model) highlightChange(line runner.Line, content string) string {
+	if !m.config.Differences || !m.changedLines[line.Number] {
+		return content
+	}
+	return changeStyle.Render(stripANSI(content))
+}
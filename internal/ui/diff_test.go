@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestDiffNewLines(t *testing.T) {
+	tests := []struct {
+		name string
+		prev []string
+		curr []string
+		want []bool
+	}{
+		{"identical", []string{"a", "b"}, []string{"a", "b"}, []bool{false, false}},
+		{"empty previous", nil, []string{"a", "b"}, []bool{true, true}},
+		{"empty current", []string{"a"}, nil, []bool{}},
+		{"inserted at top", []string{"a", "b", "c"}, []string{"x", "a", "b", "c"}, []bool{true, false, false, false}},
+		{"inserted in middle", []string{"a", "b", "c"}, []string{"a", "x", "b", "c"}, []bool{false, true, false, false}},
+		{"scrolled region", []string{"1", "2", "3", "4"}, []string{"2", "3", "4", "5"}, []bool{false, false, false, true}},
+		{"modified line", []string{"a", "b", "c"}, []string{"a", "B", "c"}, []bool{false, true, false}},
+		{"deleted line", []string{"a", "b", "c"}, []string{"a", "c"}, []bool{false, false}},
+		{"duplicate content", []string{"x", "x"}, []string{"x", "x", "x"}, []bool{false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffNewLines(tt.prev, tt.curr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffNewLines(%q, %q) = %v, want %v", tt.prev, tt.curr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffNewLinesLargeFallback(t *testing.T) {
+	// Both regions are large enough to skip the LCS table
+	n := 3000
+	prev := make([]string, n)
+	curr := make([]string, n)
+	for i := range n {
+		prev[i] = fmt.Sprintf("old %d", i)
+		curr[i] = fmt.Sprintf("old %d", i+1)
+	}
+	curr[0] = "new first"
+
+	got := diffNewLines(prev, curr)
+	if !got[0] {
+		t.Error("expected first line to be new")
+	}
+	if !got[n-1] {
+		t.Errorf("expected last line %q to be new", curr[n-1])
+	}
+	if got[1] {
+		t.Error("expected line present in previous output not to be new")
+	}
+}
+
+func TestMarkChanges(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Differences = true
+	m.markChanges()
+	if len(m.changedLines) != 0 {
+		t.Fatal("expected nothing marked on the first run")
+	}
+
+	m.lines = []runner.Line{
+		{Number: 1, Content: "new line"},
+		{Number: 2, Content: "hello world"},
+		{Number: 3, Content: "foo bar"},
+		{Number: 4, Content: "hello foo"},
+		{Number: 5, Content: "baz qux"},
+	}
+	m.markChanges()
+	if !m.changedLines[1] || len(m.changedLines) != 1 {
+		t.Errorf("expected only the inserted line to be changed, got %v", m.changedLines)
+	}
+	if got := m.highlightChange(m.lines[0], "new line"); got != changeStyle.Render("new line") {
+		t.Errorf("expected the changed line highlighted, got %q", got)
+	}
+	if got := m.highlightChange(m.lines[1], "hello world"); got != "hello world" {
+		t.Errorf("expected an unchanged line as is, got %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// maxDocumentBytes caps how much output is parsed as a JSON or YAML document
+const maxDocumentBytes = 1 << 20
+
+// linePreview returns what the preview shows for lines[idx] when no preview
+// mode overrides it: the file it names, the line pretty-printed when it's
+// JSON or flow-style YAML, the output document around it when the whole
+// output is one, or the line itself.
+func (m model) linePreview(idx int) string {
+	line := m.lines[idx].Content
+	if content, ok := m.filePreview(line); ok {
+		return content
+	}
+	if content := highlightJSON(line); content != line {
+		return content
+	}
+	if content, ok := highlightYAML(line); ok {
+		return content
+	}
+	if len(m.outputDoc) > 0 && len(m.outputDoc) == len(m.lines) {
+		return m.documentPreview(idx)
+	}
+	return line
+}
+
+// highlightYAML pretty-prints a flow-style YAML mapping or sequence (e.g.
+// {level: info, tags: [a, b]}) in block style and highlights it. Reports
+// false when the line isn't one.
+func highlightYAML(s string) (string, bool) {
+	clean := strings.TrimSpace(stripANSI(s))
+	if !strings.HasPrefix(clean, "{") && !strings.HasPrefix(clean, "[") {
+		return "", false
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(clean), &doc); err != nil || len(doc.Content) == 0 {
+		return "", false
+	}
+	blockStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", false
+	}
+	return highlightCode(strings.TrimSuffix(buf.String(), "\n"), "yaml"), true
+}
+
+// blockStyle switches n and its children from flow to block style.
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// documentRows highlights the output as a whole when it is a single JSON or
+// YAML document spread over several lines, returning one row per line, or
+// nil when it isn't one.
+func documentRows(lines []runner.Line) []string {
+	if len(lines) < 2 {
+		return nil
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(stripANSI(line.Content))
+		if b.Len() > maxDocumentBytes {
+			return nil
+		}
+	}
+	text := b.String()
+
+	lang := "json"
+	if !json.Valid([]byte(text)) {
+		// Plain text parses as a YAML string, so only mappings and
+		// sequences count as documents, and only when nothing follows
+		dec := yaml.NewDecoder(strings.NewReader(text))
+		var doc, rest any
+		if err := dec.Decode(&doc); err != nil || dec.Decode(&rest) != io.EOF {
+			return nil
+		}
+		switch doc.(type) {
+		case map[string]any, map[any]any, []any:
+		default:
+			return nil
+		}
+		lang = "yaml"
+	}
+
+	rows := strings.Split(highlightCode(text, lang), "\n")
+	if len(rows) < len(lines) {
+		return nil
+	}
+	return rows[:len(lines)]
+}
+
+// documentPreview renders the highlighted output document around lines[idx],
+// with line numbers and the selected line's number highlighted.
+func (m model) documentPreview(idx int) string {
+	n := max(m.contextLines(), m.previewHeight()/2)
+	lo, hi := max(idx-n, 0), min(idx+n, len(m.outputDoc)-1)
+
+	numStyle := lipgloss.NewStyle().Foreground(m.config.Theme.LineNumber)
+	selectedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Prompt).Bold(true)
+
+	rows := make([]string, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		num := fmt.Sprintf("%*d ", m.config.LineNumWidth, m.lines[i].Number)
+		if i == idx {
+			num = selectedStyle.Render(num)
+		} else {
+			num = numStyle.Render(num)
+		}
+		rows = append(rows, num+m.outputDoc[i])
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func linesOf(text string) []runner.Line {
+	var lines []runner.Line
+	for i, content := range strings.Split(text, "\n") {
+		lines = append(lines, runner.Line{Number: i + 1, Content: content})
+	}
+	return lines
+}
+
+func TestHighlightYAML(t *testing.T) {
+	out, ok := highlightYAML("{level: info, tags: [a, b]}")
+	if !ok {
+		t.Fatal("expected a flow mapping to be pretty-printed")
+	}
+	want := "level: info\ntags:\n  - a\n  - b"
+	if got := stripANSI(out); got != want {
+		t.Errorf("expected block style:\n%s\ngot:\n%s", want, got)
+	}
+
+	for _, line := range []string{"level: info", "plain text", "{unclosed"} {
+		if _, ok := highlightYAML(line); ok {
+			t.Errorf("expected %q not to be pretty-printed", line)
+		}
+	}
+}
+
+func TestDocumentRows(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		ok   bool
+	}{
+		{"json", "{\n  \"a\": 1,\n  \"b\": [true]\n}", true},
+		{"yaml", "name: watchr\nitems:\n  - one\n  - two", true},
+		{"json lines", "{\"a\": 1}\n{\"a\": 2}", false},
+		{"plain text", "hello\nworld", false},
+		{"single line", "{\"a\": 1}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := linesOf(tt.text)
+			rows := documentRows(lines)
+			if (rows != nil) != tt.ok {
+				t.Fatalf("expected document %v, got %q", tt.ok, rows)
+			}
+			for i, row := range rows {
+				if stripANSI(row) != lines[i].Content {
+					t.Errorf("row %d: expected %q, got %q", i, lines[i].Content, stripANSI(row))
+				}
+			}
+		})
+	}
+}
+
+func TestLinePreviewDocument(t *testing.T) {
+	m := testModelWithLines()
+	m.lines = linesOf("name: watchr\nitems:\n  - one\n  - two")
+	m.outputDoc = documentRows(m.lines)
+	m.config.PreviewContext = 1
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 2
+	m.updateFiltered()
+
+	got := stripANSI(m.linePreview(2))
+	if want := "2 items:\n3   - one\n4   - two"; got != want {
+		t.Errorf("expected the document around the line:\n%s\ngot:\n%s", want, got)
+	}
+
+	// Once lines are deleted the document no longer lines up
+	m.lines = m.lines[1:]
+	if got := m.linePreview(0); got != m.lines[0].Content {
+		t.Errorf("expected the plain line, got %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// editorFilterMsg carries the filter expression edited in $EDITOR
+type editorFilterMsg struct {
+	text string
+	err  error
+}
+
+// editorFilterHeader is written above the filter in the temp file, in the
+// style of `git rebase -i`.
+const editorFilterHeader = `# Edit the filter expression below, then save and quit to apply it.
+# Lines starting with '#' and blank lines are ignored; the remaining lines
+# are joined without separators, so long regexes can be split across lines.
+# Leave it empty to clear the filter.
+`
+
+// editorDoneMsg reports that the editor opened on a file from the output
+// has exited
+type editorDoneMsg struct{ err error }
+
+// editorCommand returns the command that opens path in the user's editor.
+func editorCommand(path string) *exec.Cmd {
+	parts := editorArgs()
+	return exec.Command(parts[0], append(parts[1:], path)...)
+}
+
+// editorArgs returns the user's editor and its arguments, using $VISUAL,
+// then $EDITOR, then a platform default.
+func editorArgs() []string {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		} else {
+			editor = "vi"
+		}
+	}
+	// Allow editors with arguments, e.g. "code --wait"
+	return strings.Fields(editor)
+}
+
+// EditorCommand returns the editor files and filters are opened in, with
+// its arguments.
+func EditorCommand() []string {
+	return editorArgs()
+}
+
+// editorAtCommand returns the command that opens the user's editor at the
+// line and column ref points to. Editors that take path:line:col get that;
+// the rest get vi's +line argument.
+func editorAtCommand(ref fileRef) *exec.Cmd {
+	parts := editorArgs()
+	args := parts[1:]
+	switch name := strings.TrimSuffix(filepath.Base(parts[0]), ".exe"); {
+	case ref.line == 0:
+		args = append(args, ref.path)
+	case name == "code" || name == "code-insiders" || name == "codium" || name == "cursor":
+		args = append(args, "--goto", fileRefArg(ref))
+	case name == "subl" || name == "zed" || name == "hx" || name == "helix":
+		args = append(args, fileRefArg(ref))
+	default:
+		args = append(args, fmt.Sprintf("+%d", ref.line), ref.path)
+	}
+	return exec.Command(parts[0], args...)
+}
+
+// fileRefArg formats ref as path:line[:col].
+func fileRefArg(ref fileRef) string {
+	if ref.col > 0 {
+		return fmt.Sprintf("%s:%d:%d", ref.path, ref.line, ref.col)
+	}
+	return fmt.Sprintf("%s:%d", ref.path, ref.line)
+}
+
+// actionOpenInEditor opens the file the selected line refers to (path or
+// path:line[:col], as in compiler errors, grep -n output and stack traces)
+// in the user's editor, suspending the UI until it exits.
+func (m *model) actionOpenInEditor() (tea.Model, tea.Cmd) {
+	line, ok := m.selectedPlain()
+	if !ok {
+		return m, nil
+	}
+	ref, ok := parseFileRef(line)
+	if !ok || !m.localPaths() {
+		m.statusMsg = i18n.T("status.no_file")
+		return m, m.statusTimeoutCmd()
+	}
+	return m, tea.ExecProcess(editorAtCommand(ref), func(err error) tea.Msg {
+		return editorDoneMsg{err: err}
+	})
+}
+
+// parseEditedFilter extracts the filter expression from the edited file.
+func parseEditedFilter(content string) string {
+	var b strings.Builder
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// editFilterCmd writes the current filter to a temp file, opens it in the
+// editor, and reports the edited expression once the editor exits.
+func (m model) editFilterCmd() tea.Cmd {
+	f, err := os.CreateTemp("", "watchr-filter-*.txt")
+	if err != nil {
+		return func() tea.Msg { return editorFilterMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = fmt.Fprintf(f, "%s\n%s\n", editorFilterHeader, m.filterInput.Text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return func() tea.Msg { return editorFilterMsg{err: err} }
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer func() { _ = os.Remove(path) }()
+		if err != nil {
+			return editorFilterMsg{err: err}
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return editorFilterMsg{err: err}
+		}
+		return editorFilterMsg{text: parseEditedFilter(string(content))}
+	})
+}
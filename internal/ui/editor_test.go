@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseEditedFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"header only", editorFilterHeader, ""},
+		{"single line", editorFilterHeader + "\nerror|warn\n", "error|warn"},
+		{"split regex", "# comment\n^(foo\n|bar)\n\n$\n", "^(foo|bar)$"},
+		{"crlf", "abc\r\ndef\r\n", "abcdef"},
+		{"keeps inner spaces", "foo bar \n", "foo bar "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEditedFilter(tt.input); got != tt.expected {
+				t.Errorf("parseEditedFilter(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	cmd := editorCommand("/tmp/f.txt")
+	if len(cmd.Args) != 3 || cmd.Args[0] != "code" || cmd.Args[1] != "--wait" || cmd.Args[2] != "/tmp/f.txt" {
+		t.Errorf("unexpected editor args: %q", cmd.Args)
+	}
+
+	t.Setenv("VISUAL", "nvim")
+	cmd = editorCommand("/tmp/f.txt")
+	if cmd.Args[0] != "nvim" {
+		t.Errorf("expected $VISUAL to take precedence, got %q", cmd.Args[0])
+	}
+}
+
+func TestUpdateEditorFilterMsg(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+
+	m.Update(editorFilterMsg{text: "hello"})
+	if m.filterMode {
+		t.Error("expected filter mode to be closed after editing")
+	}
+	if m.filterInput.Text != "hello" {
+		t.Errorf("expected filter 'hello', got %q", m.filterInput.Text)
+	}
+	if len(m.filtered) != 2 {
+		t.Errorf("expected 2 filtered lines, got %d", len(m.filtered))
+	}
+
+	_, cmd := m.Update(editorFilterMsg{err: errors.New("boom")})
+	if m.filterInput.Text != "hello" {
+		t.Error("expected filter unchanged when the editor fails")
+	}
+	if cmd == nil || m.statusMsg == "" {
+		t.Error("expected an error status message")
+	}
+}
+
+func TestEditorAtCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	ref := fileRef{path: "main.go", line: 12, col: 5}
+
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"nvim", []string{"nvim", "+12", "main.go"}},
+		{"code --wait", []string{"code", "--wait", "--goto", "main.go:12:5"}},
+		{"/usr/local/bin/subl", []string{"/usr/local/bin/subl", "main.go:12:5"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("EDITOR", tt.editor)
+		if got := editorAtCommand(ref).Args; !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.editor, tt.want, got)
+		}
+	}
+
+	t.Setenv("EDITOR", "nvim")
+	if got := editorAtCommand(fileRef{path: "main.go"}).Args; !slices.Equal(got, []string{"nvim", "main.go"}) {
+		t.Errorf("expected no line argument without a line, got %q", got)
+	}
+}
+
+func TestActionOpenInEditor(t *testing.T) {
+	m := testModelWithLines()
+	if _, cmd := m.actionOpenInEditor(); cmd == nil || m.statusMsg != "No file on this line" {
+		t.Errorf("expected a status message for a line without a file, got %q", m.statusMsg)
+	}
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m.lines[0].Content = path + ":1:1: expected 'package'"
+	m.statusMsg = ""
+	if _, cmd := m.actionOpenInEditor(); cmd == nil || m.statusMsg != "" {
+		t.Errorf("expected the editor to be opened, got status %q", m.statusMsg)
+	}
+
+	m.Update(editorDoneMsg{err: errors.New("boom")})
+	if m.statusMsg != "Editor failed: boom" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+}
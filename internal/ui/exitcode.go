@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// hookFailedMsg reports a notification hook or webhook that failed
+type hookFailedMsg struct{ err error }
+
+// recordExitCode tracks the exit code of a finished run for quitExitCode, and
+// notifies when the command goes from passing to failing or back. A run that
+// was stopped or cancelled isn't recorded: its -1 says nothing about the
+// command.
+func (m *model) recordExitCode(code int) tea.Cmd {
+	if m.ctx != nil && m.ctx.Err() != nil {
+		return nil
+	}
+	prev, hadRun := m.lastExitCode, m.finishedRuns > 0
+	m.finishedRuns++
+	m.lastExitCode = code
+	if code != 0 && m.failedExitCode == 0 {
+		m.failedExitCode = code
+	}
+	if !hadRun || (prev == 0) == (code == 0) {
+		return nil
+	}
+	return m.notifyTransition(prev, code)
+}
+
+// notifyTransition shows a status message for an exit code transition, and
+// rings the bell and runs the OnTransition hook when configured.
+func (m *model) notifyTransition(prev, code int) tea.Cmd {
+	m.statusMsg = i18n.T("status.started_failing", code)
+	if code == 0 {
+		m.statusMsg = i18n.T("status.passing_again")
+	}
+
+	cmds := []tea.Cmd{m.statusTimeoutCmd()}
+	if m.config.TransitionBell {
+		cmds = append(cmds, m.bellCmd())
+	}
+	if hook := m.config.OnTransition; hook != "" {
+		r := m.runner
+		cmds = append(cmds, func() tea.Msg {
+			if err := r.RunTransitionHook(context.Background(), hook, prev, code); err != nil {
+				return hookFailedMsg{err}
+			}
+			return nil
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// quitExitCode returns the exit status watchr should exit with. Runs killed
+// by a signal report -1, which is mapped to a plain failure.
+func (m model) quitExitCode() int {
+	var code int
+	switch m.config.ExitCode {
+	case ExitCodeLast:
+		code = m.lastExitCode
+	case ExitCodeAny:
+		code = m.failedExitCode
+	}
+	if code < 0 {
+		return 1
+	}
+	return code
+}
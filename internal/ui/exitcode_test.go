@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ExitCodePolicy
+		runs   []int
+		want   int
+	}{
+		{"disabled", ExitCodeNone, []int{2, 3}, 0},
+		{"last run", ExitCodeLast, []int{2, 0, 3}, 3},
+		{"last run passed", ExitCodeLast, []int{2, 0}, 0},
+		{"any failed", ExitCodeAny, []int{0, 2, 0, 3}, 2},
+		{"none failed", ExitCodeAny, []int{0, 0}, 0},
+		{"never ran", ExitCodeLast, nil, 0},
+		{"killed by signal", ExitCodeLast, []int{-1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(Config{Command: "true", Shell: "sh", ExitCode: tt.policy})
+			for _, code := range tt.runs {
+				m.recordExitCode(code)
+			}
+			if got := m.quitExitCode(); got != tt.want {
+				t.Errorf("quitExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodeCancelledRun(t *testing.T) {
+	m := testModel(Config{Command: "true", Shell: "sh", ExitCode: ExitCodeAny})
+	m.recordExitCode(0)
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.cancel()
+
+	if cmd := m.recordExitCode(-1); cmd != nil {
+		t.Error("expected no transition for a stopped run")
+	}
+	if m.finishedRuns != 1 || m.lastExitCode != 0 || m.quitExitCode() != 0 {
+		t.Errorf("expected a stopped run not to be recorded, got %d runs, last exit %d", m.finishedRuns, m.lastExitCode)
+	}
+}
+
+func TestExitCodeTransitions(t *testing.T) {
+	m := testModel(Config{Command: "true", Shell: "sh"})
+
+	if cmd := m.recordExitCode(1); cmd != nil {
+		t.Error("expected no notification for the first run")
+	}
+	if cmd := m.recordExitCode(2); cmd != nil {
+		t.Error("expected no notification while still failing")
+	}
+	if cmd := m.recordExitCode(0); cmd == nil || m.statusMsg != "Command is passing again" {
+		t.Errorf("expected pass notification, got %q", m.statusMsg)
+	}
+	if cmd := m.recordExitCode(0); cmd != nil {
+		t.Error("expected no notification while still passing")
+	}
+	if cmd := m.recordExitCode(3); cmd == nil || m.statusMsg != "Command started failing (exit 3)" {
+		t.Errorf("expected fail notification, got %q", m.statusMsg)
+	}
+}
+
+func TestTransitionHook(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "transition")
+	m := testModel(Config{
+		Command:      "true",
+		Shell:        "sh",
+		OnTransition: `echo "$WATCHR_TRANSITION $WATCHR_PREV_EXIT_CODE $WATCHR_EXIT_CODE" > ` + out,
+	})
+	m.recordExitCode(0)
+	cmd := m.recordExitCode(5)
+
+	// The hook is the last command in the batch
+	batch := cmd().(tea.BatchMsg)
+	if msg := batch[len(batch)-1](); msg != nil {
+		t.Fatalf("expected hook to succeed, got %v", msg)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected hook to run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "fail 0 5" {
+		t.Errorf("expected 'fail 0 5', got %q", got)
+	}
+}
+
+func TestTransitionHookFailure(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(hookFailedMsg{errors.New("on-transition hook failed: exit status 1")})
+	if !strings.Contains(m.statusMsg, "on-transition hook failed") {
+		t.Errorf("expected hook failure in status, got %q", m.statusMsg)
+	}
+}
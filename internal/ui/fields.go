@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FieldRange selects fields of a line, from From to To inclusive. Fields are
+// numbered from 1; negative numbers count from the last field (-1) and 0
+// leaves that end of the range open.
+type FieldRange struct {
+	From, To int
+}
+
+// ParseNth parses a comma-separated list of fzf-style field expressions:
+// N, -N, N.., ..N and N..M (e.g. "1,3..", "-1").
+func ParseNth(spec string) ([]FieldRange, error) {
+	var ranges []FieldRange
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		var r FieldRange
+		var err error
+		if from, to, isRange := strings.Cut(part, ".."); isRange {
+			if r.From, err = parseFieldIndex(from, true); err == nil {
+				r.To, err = parseFieldIndex(to, true)
+			}
+		} else {
+			r.From, err = parseFieldIndex(part, false)
+			r.To = r.From
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid field expression %q (expected e.g. 1, -1, 2.., ..3 or 1..3)", part)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseFieldIndex parses one end of a field range, which may be empty when
+// open is set.
+func parseFieldIndex(s string, open bool) (int, error) {
+	if s == "" && open {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err == nil && n == 0 {
+		err = fmt.Errorf("fields are numbered from 1")
+	}
+	return n, err
+}
+
+// field is one field of a line and the delimiter that follows it
+type field struct {
+	text, delim string
+}
+
+// awkFields matches a whitespace-separated field and the whitespace after it
+var awkFields = regexp.MustCompile(`\S+\s*`)
+
+// splitFields splits s on delim, or on runs of whitespace (ignoring leading
+// whitespace, as awk does) when delim is nil.
+func splitFields(s string, delim *regexp.Regexp) []field {
+	var fields []field
+	if delim == nil {
+		for _, f := range awkFields.FindAllString(s, -1) {
+			text := strings.TrimRight(f, " \t")
+			fields = append(fields, field{text: text, delim: f[len(text):]})
+		}
+		return fields
+	}
+	start := 0
+	for _, loc := range delim.FindAllStringIndex(s, -1) {
+		if loc[1] == loc[0] {
+			continue
+		}
+		fields = append(fields, field{text: s[start:loc[0]], delim: s[loc[0]:loc[1]]})
+		start = loc[1]
+	}
+	return append(fields, field{text: s[start:]})
+}
+
+// selectFields returns the fields of s that ranges select, in order and
+// joined by their original delimiters.
+func selectFields(s string, delim *regexp.Regexp, ranges []FieldRange) string {
+	fields := splitFields(s, delim)
+	n := len(fields)
+	index := func(i, open int) int {
+		switch {
+		case i == 0:
+			return open
+		case i < 0:
+			return n + i + 1
+		}
+		return i
+	}
+
+	var selected []field
+	for _, r := range ranges {
+		from, to := max(index(r.From, 1), 1), min(index(r.To, n), n)
+		for i := from; i <= to; i++ {
+			selected = append(selected, fields[i-1])
+		}
+	}
+
+	var b strings.Builder
+	for i, f := range selected {
+		b.WriteString(f.text)
+		if i < len(selected)-1 {
+			delim := f.delim
+			if delim == "" {
+				// The last field has no delimiter of its own
+				delim = " "
+				if n > 1 {
+					delim = fields[0].delim
+				}
+			}
+			b.WriteString(delim)
+		}
+	}
+	return b.String()
+}
+
+// shown returns the part of a line's content displayed and matched in the
+// list: the fields selected by WithNth, or the whole content.
+func (m model) shown(content string) string {
+	if len(m.config.WithNth) == 0 {
+		return content
+	}
+	return selectFields(stripANSI(content), m.config.Delimiter, m.config.WithNth)
+}
+
+// yanked returns what yanking or accepting a line emits: the displayed
+// fields with YankDisplayed, or the whole line.
+func (m model) yanked(content string) string {
+	if m.config.YankDisplayed {
+		return m.shown(content)
+	}
+	return content
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestParseNth(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []FieldRange
+	}{
+		{"1", []FieldRange{{1, 1}}},
+		{"-1", []FieldRange{{-1, -1}}},
+		{"2..", []FieldRange{{2, 0}}},
+		{"..3", []FieldRange{{0, 3}}},
+		{"1, 3..4", []FieldRange{{1, 1}, {3, 4}}},
+		{"..", []FieldRange{{0, 0}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseNth(tt.spec)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseNth(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "0", "a", "1..b", "1,,2"} {
+		if _, err := ParseNth(spec); err == nil {
+			t.Errorf("ParseNth(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	const ps = "  root   42  0.0 /usr/bin/sleep 100"
+	comma := regexp.MustCompile(`,`)
+	tests := []struct {
+		line   string
+		delim  *regexp.Regexp
+		ranges []FieldRange
+		want   string
+	}{
+		{ps, nil, []FieldRange{{2, 2}}, "42"},
+		{ps, nil, []FieldRange{{1, 1}, {4, 0}}, "root   /usr/bin/sleep 100"},
+		{ps, nil, []FieldRange{{-2, -1}}, "/usr/bin/sleep 100"},
+		{ps, nil, []FieldRange{{0, 2}}, "root   42"},
+		{ps, nil, []FieldRange{{9, 9}}, ""},
+		{"a,b,,d", comma, []FieldRange{{2, 0}}, "b,,d"},
+		{"a,b,,d", comma, []FieldRange{{-1, -1}, {1, 1}}, "d,a"},
+	}
+	for _, tt := range tests {
+		if got := selectFields(tt.line, tt.delim, tt.ranges); got != tt.want {
+			t.Errorf("selectFields(%q, %v) = %q, want %q", tt.line, tt.ranges, got, tt.want)
+		}
+	}
+}
+
+func TestWithNthDisplayAndMatch(t *testing.T) {
+	m := testModelWithLines()
+	m.config.WithNth = []FieldRange{{2, 2}}
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	if len(m.filtered) != 0 {
+		t.Errorf("expected the filter to match only the displayed field, got %d lines", len(m.filtered))
+	}
+
+	m.filterInput.Text = "bar"
+	m.updateFiltered()
+	if len(m.filtered) != 1 {
+		t.Fatalf("expected one line matching the displayed field, got %d", len(m.filtered))
+	}
+	if rows := m.renderedRows(m.lines[m.filtered[0]]); rows[0] != "bar" {
+		t.Errorf("expected only the displayed field, got %q", rows[0])
+	}
+
+	if got := m.yanked("foo bar"); got != "foo bar" {
+		t.Errorf("expected the whole line to be yanked, got %q", got)
+	}
+	m.config.YankDisplayed = true
+	if got := m.yanked("foo bar"); got != "bar" {
+		t.Errorf("expected the displayed field to be yanked, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// maxFilePreviewLines caps how many lines of a file are previewed when the
+// line doesn't reference a line number
+const maxFilePreviewLines = 200
+
+// fileRefPatterns match a path, a line number and an optional column, in
+// the order they're tried
+var fileRefPatterns = []*regexp.Regexp{
+	// grep -n and most compilers and linters: path:line[:col] at the start
+	regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?(?:[:\s]|$)`),
+	// Python tracebacks: File "path", line N
+	regexp.MustCompile(`File "([^"]+)", line (\d+)()`),
+	// Stack traces and rustc: path:line[:col] anywhere, e.g. "at f (x.js:3:7)"
+	regexp.MustCompile(`([^\s():"'<>]+):(\d+)(?::(\d+))?`),
+}
+
+// fileRef is a local file named by a line of output, with the line and
+// column it references (0 if none)
+type fileRef struct {
+	path string
+	line int
+	col  int
+}
+
+// parseFileRef returns the local file a line of output names. A
+// path:line[:col] reference, the whole line, and the last word (as in git
+// status output) are tried in turn.
+func parseFileRef(line string) (fileRef, bool) {
+	plain := strings.TrimSpace(stripANSI(line))
+	if plain == "" {
+		return fileRef{}, false
+	}
+	for _, pattern := range fileRefPatterns {
+		for _, m := range pattern.FindAllStringSubmatch(plain, -1) {
+			if isRegularFile(m[1]) {
+				n, _ := strconv.Atoi(m[2])
+				col, _ := strconv.Atoi(m[3])
+				return fileRef{path: m[1], line: n, col: col}, true
+			}
+		}
+	}
+	if isRegularFile(plain) {
+		return fileRef{path: plain}, true
+	}
+	if fields := strings.Fields(plain); len(fields) > 1 && isRegularFile(fields[len(fields)-1]) {
+		return fileRef{path: fields[len(fields)-1]}, true
+	}
+	return fileRef{}, false
+}
+
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// localPaths reports whether paths in the output refer to this machine's
+// files, which isn't the case when the command runs remotely.
+func (m model) localPaths() bool {
+	return m.config.SSH == "" && m.config.Docker == "" && m.config.KubectlPod == ""
+}
+
+// filePreview returns the contents of the file line names, around the line
+// it references or from the top, with line numbers. Reports false when the
+// line doesn't name a local file or file previews are off.
+func (m model) filePreview(line string) (string, bool) {
+	if !m.config.FilePreview || !m.localPaths() {
+		return "", false
+	}
+	fr, ok := parseFileRef(line)
+	if !ok {
+		return "", false
+	}
+	f, err := os.Open(fr.path)
+	if err != nil {
+		return "", false
+	}
+	defer func() { _ = f.Close() }()
+
+	first, last := 1, maxFilePreviewLines
+	if fr.line > 0 {
+		n := max(m.contextLines(), m.previewHeight()/2)
+		first, last = max(fr.line-n, 1), fr.line+n
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	numStyle := lipgloss.NewStyle().Foreground(m.config.Theme.LineNumber)
+	selectedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Prompt).Bold(true)
+
+	header := fr.path
+	if fr.line > 0 {
+		header = fmt.Sprintf("%s:%d", fr.path, fr.line)
+	}
+	rows := []string{headerStyle.Render("── " + header)}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for n := 1; n <= last && scanner.Scan(); n++ {
+		text := scanner.Bytes()
+		if bytes.IndexByte(text, 0) >= 0 {
+			return headerStyle.Render("── " + header + i18n.T("preview.binary_file")), true
+		}
+		if n < first {
+			continue
+		}
+		num := fmt.Sprintf("%*d ", m.config.LineNumWidth, n)
+		if n == fr.line {
+			rows = append(rows, selectedStyle.Render(num+stripANSI(string(text))))
+		} else {
+			rows = append(rows, numStyle.Render(num)+string(text))
+		}
+	}
+	return strings.Join(rows, "\n"), true
+}
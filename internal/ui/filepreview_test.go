@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFileRef(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line     string
+		ref, col int
+		ok       bool
+	}{
+		{path, 0, 0, true},
+		{path + ":12:func main() {", 12, 0, true},
+		{path + ":3:7: undefined: x", 3, 7, true},
+		{" M " + path, 0, 0, true},
+		{"\tmodified:   " + path, 0, 0, true},
+		{"    at run (" + path + ":8:15)", 8, 15, true},
+		{"  --> " + path + ":4:1", 4, 1, true},
+		{"\t" + path + ":42 +0x1d", 42, 0, true},
+		{`  File "` + path + `", line 9, in main`, 9, 0, true},
+		{filepath.Dir(path), 0, 0, false},
+		{path + ".missing:4:x", 0, 0, false},
+		{"hello world", 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseFileRef(tt.line)
+		want := fileRef{path: path, line: tt.ref, col: tt.col}
+		if ok != tt.ok || (ok && got != want) {
+			t.Errorf("parseFileRef(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, want, tt.ok)
+		}
+	}
+}
+
+func TestFilePreview(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	var lines []string
+	for i := range 30 {
+		lines = append(lines, strings.Repeat("x", i+1))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := testModelWithLines()
+	m.config.PreviewContext = 2
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 4
+
+	if _, ok := m.filePreview(path); ok {
+		t.Error("expected no file preview while FilePreview is off")
+	}
+
+	m.config.FilePreview = true
+	content, ok := m.filePreview(path + ":10:xxxxxxxxxx")
+	if !ok {
+		t.Fatal("expected a file preview")
+	}
+	rows := strings.Split(stripANSI(content), "\n")
+	if len(rows) != 6 || rows[0] != "── "+path+":10" {
+		t.Fatalf("expected a header and lines 8-12, got %q", rows)
+	}
+	if rows[3] != "10 "+lines[9] {
+		t.Errorf("expected line 10 in the middle, got %q", rows[3])
+	}
+
+	content, _ = m.filePreview(path)
+	if rows := strings.Split(content, "\n"); len(rows) != 31 {
+		t.Errorf("expected the whole file from the top, got %d rows", len(rows))
+	}
+
+	m.config.SSH = "host"
+	if _, ok := m.filePreview(path); ok {
+		t.Error("expected no file preview for a remote command")
+	}
+}
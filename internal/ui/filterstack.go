@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+// pinnedFilter is a filter pinned to the filter stack. Lines must match every
+// pinned filter as well as the one being typed.
+type pinnedFilter struct {
+	text    string
+	kind    filter.Kind
+	matcher filter.Matcher
+}
+
+// label returns how the filter is shown in the prompt
+func (p pinnedFilter) label() string {
+	if p.kind == filter.Substring {
+		return p.text
+	}
+	return string(p.kind) + "/" + p.text
+}
+
+// pinFilter pushes the typed filter onto the stack and clears the input for
+// the next one. Empty and invalid filters aren't pinned.
+func (m *model) pinFilter() {
+	if m.filterInput.Text == "" {
+		return
+	}
+	kind := m.filterKind()
+	matcher, err := m.compileFilter(kind, m.filterInput.Text)
+	if err != nil {
+		return
+	}
+	m.pinnedFilters = append(m.pinnedFilters, pinnedFilter{text: m.filterInput.Text, kind: kind, matcher: matcher})
+	m.resetFilter()
+}
+
+// popFilter removes the last pinned filter, moving it back into the input
+// when edit is set. Reports whether there was one to remove.
+func (m *model) popFilter(edit bool) bool {
+	n := len(m.pinnedFilters)
+	if n == 0 {
+		return false
+	}
+	last := m.pinnedFilters[n-1]
+	m.pinnedFilters = m.pinnedFilters[:n-1]
+	if edit {
+		m.filterInput.Text = last.text
+		m.filterInput.Cursor = len(last.text)
+		m.setFilterKind(last.kind)
+	} else {
+		m.updateFiltered()
+	}
+	return true
+}
+
+func (m *model) actionPopFilter() (tea.Model, tea.Cmd) {
+	n := len(m.pinnedFilters)
+	if n == 0 {
+		return m, nil
+	}
+	label := m.pinnedFilters[n-1].label()
+	m.popFilter(false)
+	m.statusMsg = i18n.T("status.filter_removed", label)
+	return m, m.statusTimeoutCmd()
+}
+
+// matchesPinned reports whether a line matches every pinned filter.
+func (m model) matchesPinned(line string) bool {
+	for _, p := range m.pinnedFilters {
+		if !p.matcher.Match(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// pinnedLabels returns the pinned filters as shown in the prompt
+func (m model) pinnedLabels() string {
+	labels := make([]string, len(m.pinnedFilters))
+	for i, p := range m.pinnedFilters {
+		labels[i] = p.label()
+	}
+	return strings.Join(labels, " + ")
+}
+
+// savedFilters returns the pinned filters for the journal
+func (m model) savedFilters() []state.Filter {
+	var saved []state.Filter
+	for _, p := range m.pinnedFilters {
+		saved = append(saved, state.Filter{Text: p.text, Kind: string(p.kind)})
+	}
+	return saved
+}
+
+// restoreFilters compiles saved pinned filters with case mode c, skipping any
+// that no longer compile.
+func restoreFilters(saved []state.Filter, c filter.Case) []pinnedFilter {
+	var pinned []pinnedFilter
+	for _, f := range saved {
+		kind := filter.Kind(f.Kind)
+		matcher, err := filter.NewCase(kind, f.Text, c)
+		if err != nil {
+			continue
+		}
+		pinned = append(pinned, pinnedFilter{text: f.Text, kind: kind, matcher: matcher})
+	}
+	return pinned
+}
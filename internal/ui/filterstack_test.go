@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+func TestPinFilterStacks(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+	typeText(m, "hello")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+
+	if len(m.pinnedFilters) != 1 || m.filterInput.Text != "" {
+		t.Fatalf("expected the filter to be pinned and the input cleared, got %d pinned, input %q", len(m.pinnedFilters), m.filterInput.Text)
+	}
+
+	typeText(m, "foo")
+	// "hello world", "foo bar", "hello foo", "baz qux": only "hello foo" has both
+	if want := []int{2}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected pinned and typed filters to AND together, got %v", m.filtered)
+	}
+	if prompt := stripANSI(m.renderPromptLine()); !strings.Contains(prompt, "[hello] /foo") {
+		t.Errorf("expected the stack in the prompt, got %q", prompt)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if prompt := stripANSI(m.renderPromptLine()); !strings.Contains(prompt, "(filters: hello + foo)") {
+		t.Errorf("expected the stack in the prompt after confirming, got %q", prompt)
+	}
+}
+
+func TestPinFilterKeepsKind(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+	m.setFilterKind(filter.Regex)
+	typeText(m, "^hello")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+
+	if got := m.pinnedFilters[0].label(); got != "regex/^hello" {
+		t.Errorf("expected regex label, got %q", got)
+	}
+	if m.filterKind() != filter.Substring {
+		t.Errorf("expected the next filter to start in the default mode, got %q", m.filterKind())
+	}
+
+	// Invalid patterns aren't pinned
+	m.setFilterKind(filter.Regex)
+	typeText(m, "[bad")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if len(m.pinnedFilters) != 1 {
+		t.Errorf("expected an invalid pattern not to be pinned, got %d pinned", len(m.pinnedFilters))
+	}
+}
+
+func TestBackspacePopsPinnedFilter(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+	m.setFilterKind(filter.Glob)
+	typeText(m, "hello*")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if len(m.pinnedFilters) != 0 || m.filterInput.Text != "hello*" || m.filterKind() != filter.Glob {
+		t.Errorf("expected the pinned filter back in the input, got input %q kind %q", m.filterInput.Text, m.filterKind())
+	}
+}
+
+func TestPopFilterKey(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+	typeText(m, "hello")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typeText(m, "world")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if len(m.pinnedFilters) != 1 || m.pinnedFilters[0].text != "hello" {
+		t.Fatalf("expected P to pop the last filter, got %v", m.pinnedFilters)
+	}
+	if len(m.filtered) != 2 {
+		t.Errorf("expected 2 lines matching hello, got %d", len(m.filtered))
+	}
+
+	// Esc clears the rest of the stack before quitting
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil || len(m.pinnedFilters) != 0 || len(m.filtered) != 4 {
+		t.Errorf("expected Esc to clear the stack, got %d pinned", len(m.pinnedFilters))
+	}
+}
+
+func TestPinnedFiltersJournal(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+	m.setFilterKind(filter.Regex)
+	typeText(m, "o{2}")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+
+	saved := m.session().Pinned
+	if want := []state.Filter{{Text: "o{2}", Kind: "regex"}}; !slices.Equal(saved, want) {
+		t.Fatalf("expected pinned filters in the session, got %v", saved)
+	}
+
+	restored := testModel(Config{Command: "echo", Shell: "sh", PinnedFilters: append(saved, state.Filter{Text: "[bad", Kind: "regex"})})
+	if len(restored.pinnedFilters) != 1 || !restored.pinnedFilters[0].matcher.Match("foo") {
+		t.Errorf("expected the valid pinned filter to be restored, got %v", restored.pinnedFilters)
+	}
+}
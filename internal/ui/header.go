@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// actionToggleHeader pins or unpins the header lines: HeaderLines of them,
+// or the first line when none are configured.
+func (m *model) actionToggleHeader() (tea.Model, tea.Cmd) {
+	m.pinHeader = !m.pinHeader
+	m.updateFiltered()
+	m.adjustOffset()
+	if m.pinHeader {
+		m.statusMsg = i18n.T("status.header_pinned")
+	} else {
+		m.statusMsg = i18n.T("status.header_unpinned")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+// headerLines returns how many leading output lines are pinned above the
+// list instead of scrolling in it. They're left out of filtering. Table mode
+// always pins at least the line with its column names.
+func (m model) headerLines() int {
+	n := 0
+	if m.pinHeader {
+		n = max(m.config.HeaderLines, 1)
+	}
+	if m.tableMode {
+		n = max(n, 1)
+	}
+	return min(n, len(m.lines))
+}
+
+// renderHeaderRows renders the pinned header lines, aligned with the list's
+// content. In table mode the last of them holds the column names.
+func (m model) renderHeaderRows(listWidth int) []string {
+	style := lipgloss.NewStyle().Foreground(m.config.Theme.Header).Bold(true)
+	n := m.headerLines()
+	rows := make([]string, 0, n)
+	for i, line := range m.lines[:n] {
+		indent := lipgloss.Width(m.listGutter(line))
+		if m.tableMode && i == n-1 {
+			rows = append(rows, strings.Repeat(" ", indent)+m.renderColumnNames(line.Content, listWidth-indent, style))
+			continue
+		}
+		content := stripANSI(m.shown(line.Content))
+		rows = append(rows, strings.Repeat(" ", indent)+style.Render(truncateToWidth(content, listWidth-indent)))
+	}
+	return rows
+}
+
+// renderColumnNames renders table mode's column names with the selected
+// column's underlined.
+func (m model) renderColumnNames(content string, width int, style lipgloss.Style) string {
+	row := truncateToWidth(m.tableRow(content), width)
+	col := m.selectedColumn()
+	cells := m.tableCells(content)
+	if col < 0 || col >= len(cells) || !strings.HasPrefix(row, m.tableCell(cells, col)) {
+		return style.Render(row)
+	}
+	name := m.tableCell(cells, col)
+	return style.Underline(true).Render(name) + style.Render(row[len(name):])
+}
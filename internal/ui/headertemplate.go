@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expandHeaderTemplate fills in the HeaderTemplate placeholders: {command},
+// {cwd}, {exit_code}, {duration}, {last_run} and {next_run}. Placeholders
+// for a run that hasn't happened yet are left empty. The header is one row,
+// so newlines become spaces.
+func (m model) expandHeaderTemplate(command string) string {
+	var exitCode, duration, lastRun, nextRun string
+	if m.finishedRuns > 0 {
+		exitCode = strconv.Itoa(m.lastExitCode)
+		duration = m.lastRunDuration.Round(time.Millisecond).String()
+		lastRun = m.config.TimeFormat.since(m.lastRunEnd, time.TimeOnly)
+	}
+	if m.config.RefreshInterval > 0 && !m.refreshStopped && !m.streaming && !m.refreshStartTime.IsZero() {
+		nextRun = m.config.TimeFormat.format(m.refreshStartTime.Add(m.config.RefreshInterval), time.TimeOnly)
+	}
+	cwd, _ := os.Getwd()
+	return strings.NewReplacer(
+		"{command}", command,
+		"{cwd}", cwd,
+		"{exit_code}", exitCode,
+		"{duration}", duration,
+		"{last_run}", lastRun,
+		"{next_run}", nextRun,
+	).Replace(strings.ReplaceAll(m.config.HeaderTemplate, "\n", " "))
+}
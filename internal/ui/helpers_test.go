@@ -3,6 +3,8 @@ package ui
 import (
 	"context"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/chenasraf/watchr/internal/runner"
 )
 
@@ -34,3 +36,9 @@ func testModelWithCancel() *model {
 	m.cancel = cancel
 	return &m
 }
+
+func pressKeys(m *model, keys string) {
+	for _, r := range keys {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
 	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/alecthomas/chroma/v2/styles"
+
+	"github.com/chenasraf/watchr/internal/filter"
 )
 
 // ansiEscPattern matches ANSI escape sequences (CSI and simple ESC sequences).
@@ -55,12 +59,26 @@ func highlightJSON(s string) string {
 	if err := json.Indent(&buf, []byte(jsonStr), "", "  "); err != nil {
 		return s
 	}
-	pretty := buf.String()
+	// Highlight only the JSON portion
+	result := highlightCode(buf.String(), "json")
+
+	// Re-attach any non-JSON prefix (stripped of ANSI)
+	if prefix != "" {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" {
+			result = prefix + "\n" + result
+		}
+	}
+
+	return result
+}
 
-	// Highlight with chroma (only the JSON portion)
-	lexer := lexers.Get("json")
+// highlightCode applies terminal syntax highlighting for the given chroma
+// lexer to code, returning it unchanged if highlighting fails.
+func highlightCode(code, lang string) string {
+	lexer := lexers.Get(lang)
 	if lexer == nil {
-		return pretty
+		return code
 	}
 	lexer = chroma.Coalesce(lexer)
 
@@ -74,24 +92,116 @@ func highlightJSON(s string) string {
 		formatter = formatters.Fallback
 	}
 
-	iterator, err := lexer.Tokenise(nil, pretty)
+	iterator, err := lexer.Tokenise(nil, code)
 	if err != nil {
-		return pretty
+		return code
 	}
 
 	var out bytes.Buffer
 	if err := formatter.Format(&out, style, iterator); err != nil {
-		return pretty
+		return code
 	}
+	return out.String()
+}
 
-	// Re-attach any non-JSON prefix (stripped of ANSI)
-	result := out.String()
-	if prefix != "" {
-		prefix = strings.TrimSpace(prefix)
-		if prefix != "" {
-			result = prefix + "\n" + result
+// Match highlighting toggles reverse video, which leaves the line's own colors
+// intact
+const (
+	matchOn  = "\x1b[7m"
+	matchOff = "\x1b[27m"
+)
+
+// matchLocators returns locators for the active filters and search, whose
+// matches are highlighted in the list.
+func (m model) matchLocators() []filter.Locator {
+	var locators []filter.Locator
+	add := func(matcher filter.Matcher) {
+		if l, ok := matcher.(filter.Locator); ok {
+			locators = append(locators, l)
 		}
 	}
+	for _, p := range m.pinnedFilters {
+		add(p.matcher)
+	}
+	if m.filterInput.Text != "" {
+		if matcher, err := m.compileFilter(m.filterKind(), m.filterInput.Text); err == nil {
+			add(matcher)
+		}
+	}
+	if m.searchQuery != "" {
+		add(m.searchMatcher())
+	}
+	return locators
+}
 
-	return result
+// locateMatches returns the sorted, merged byte ranges of plain matched by
+// any of locators.
+func locateMatches(plain string, locators []filter.Locator) [][]int {
+	var ranges [][]int
+	for _, l := range locators {
+		ranges = append(ranges, l.Locate(plain)...)
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	slices.SortFunc(ranges, func(a, b []int) int { return a[0] - b[0] })
+	merged := [][]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// highlightRanges marks the parts of s covered by ranges with matchOn and
+// matchOff. Ranges are byte offsets into the ANSI-stripped line, of which s
+// (possibly truncated or one wrapped row) starts at offset base. Escape
+// sequences inside a match are followed by matchOn again, so a reset in the
+// line can't end the highlight early.
+func highlightRanges(s string, ranges [][]int, base int) string {
+	if len(ranges) == 0 {
+		return s
+	}
+	escapes := ansiEscPattern.FindAllStringIndex(s, -1)
+	var b strings.Builder
+	pos, on := base, false
+	ri, ei := 0, 0
+	for i := 0; i < len(s); {
+		for ri < len(ranges) && ranges[ri][1] <= pos {
+			ri++
+		}
+		want := ri < len(ranges) && ranges[ri][0] <= pos
+		escape := ei < len(escapes) && escapes[ei][0] == i
+		// At an escape, only end a match; starting one waits for visible text
+		if want != on && (!want || !escape) {
+			if want {
+				b.WriteString(matchOn)
+			} else {
+				b.WriteString(matchOff)
+			}
+			on = want
+		}
+
+		if escape {
+			b.WriteString(s[i:escapes[ei][1]])
+			i = escapes[ei][1]
+			ei++
+			if on {
+				b.WriteString(matchOn)
+			}
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		i += size
+		pos += size
+	}
+	if on {
+		b.WriteString(matchOff)
+	}
+	return b.String()
 }
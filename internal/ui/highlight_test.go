@@ -3,6 +3,8 @@ package ui
 import (
 	"strings"
 	"testing"
+
+	"github.com/chenasraf/watchr/internal/filter"
 )
 
 func TestWrapTextANSI(t *testing.T) {
@@ -162,3 +164,61 @@ func TestHighlightJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestHighlightRanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		ranges [][]int
+		base   int
+		want   string
+	}{
+		{"plain", "foo bar foo", [][]int{{0, 3}, {8, 11}}, 0,
+			matchOn + "foo" + matchOff + " bar " + matchOn + "foo" + matchOff},
+		{"no ranges", "foo", nil, 0, "foo"},
+		{"colored line", "\x1b[31merror\x1b[0m: disk", [][]int{{0, 5}}, 0,
+			"\x1b[31m" + matchOn + "error" + matchOff + "\x1b[0m: disk"},
+		{"reset inside a match", "ab\x1b[0mcd", [][]int{{1, 3}}, 0,
+			"a" + matchOn + "b\x1b[0m" + matchOn + "c" + matchOff + "d"},
+		{"truncated mid-match", "hello w…", [][]int{{6, 11}}, 0,
+			"hello " + matchOn + "w…" + matchOff},
+		{"offset row", "world", [][]int{{6, 8}}, 5, "w" + matchOn + "or" + matchOff + "ld"},
+	}
+	for _, tt := range tests {
+		if got := highlightRanges(tt.s, tt.ranges, tt.base); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLocateMatchesMerges(t *testing.T) {
+	a, _ := filter.New(filter.Substring, "foo")
+	b, _ := filter.New(filter.Substring, "oba")
+	got := locateMatches("foobar", []filter.Locator{a.(filter.Locator), b.(filter.Locator)})
+	if len(got) != 1 || got[0][0] != 0 || got[0][1] != 5 {
+		t.Errorf("expected overlapping matches to merge into [0 5], got %v", got)
+	}
+}
+
+func TestListHighlightsMatches(t *testing.T) {
+	m := testModelWithLines()
+	m.config.ShowLineNums = true
+	m.config.LineNumWidth = 3
+	m.filterInput.Text = "foo"
+	m.updateFiltered()
+	m.cursor = 1 // "hello foo"; the unselected "foo bar" is checked below
+
+	view := m.View()
+	if !strings.Contains(view, matchOn+"foo"+matchOff+" bar") {
+		t.Errorf("expected the filter match to be highlighted, got %q", view)
+	}
+
+	// Search matches are highlighted too, without a filter
+	m.filterInput.Text = ""
+	m.updateFiltered()
+	m.searchQuery = "qux"
+	m.cursor = 0
+	if view := m.View(); !strings.Contains(view, "baz "+matchOn+"qux"+matchOff) {
+		t.Errorf("expected the search match to be highlighted, got %q", view)
+	}
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+// journalInterval is how often session state is persisted to the journal
+const journalInterval = 5 * time.Second
+
+type journalTickMsg struct{}
+
+func (m model) journalTickCmd() tea.Cmd {
+	return tea.Tick(journalInterval, func(t time.Time) tea.Msg {
+		return journalTickMsg{}
+	})
+}
+
+// session returns the current investigation context for the journal.
+func (m model) session() state.Session {
+	return state.Session{
+		Command:     m.config.Command,
+		Shell:       m.config.Shell,
+		Interactive: m.config.Interactive,
+		Filter:      m.filterInput.Text,
+		FilterKind:  string(m.filterKind()),
+		Pinned:      m.savedFilters(),
+		Notes:       maps.Clone(m.notes),
+		Marks:       m.markKeys(),
+		Baseline:    m.baseline,
+	}
+}
+
+// outputHash identifies a run's output by a hash of its plain content.
+func outputHash(lines []runner.Line) string {
+	h := fnv.New64a()
+	for _, line := range lines {
+		_, _ = h.Write([]byte(stripANSI(line.Content)))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// markKeys returns the content hashes of the marked lines for the journal.
+// Until the first run of a resumed session re-marks them, the resumed ones
+// are kept as they are.
+func (m model) markKeys() []string {
+	if m.pendingMarks != nil {
+		return m.pendingMarks
+	}
+	var keys []string
+	for _, line := range m.lines {
+		if m.marked[line.Number] {
+			keys = append(keys, noteKey(line.Content))
+		}
+	}
+	return keys
+}
+
+// restoreSession keeps the finished run's baseline for the journal. After
+// the first run of a resumed session, it re-marks the lines that were marked
+// and reports whether the output changed while it was interrupted.
+func (m *model) restoreSession() {
+	m.baseline = outputHash(m.runLines())
+	if m.pendingMarks != nil {
+		for _, line := range m.lines {
+			if slices.Contains(m.pendingMarks, noteKey(line.Content)) {
+				if m.marked == nil {
+					m.marked = make(map[int]bool)
+				}
+				m.marked[line.Number] = true
+			}
+		}
+		m.pendingMarks = nil
+	}
+	if m.resumeBaseline != "" {
+		if m.baseline == m.resumeBaseline {
+			m.statusMsg = i18n.T("status.output_unchanged")
+		} else {
+			m.statusMsg = i18n.T("status.output_changed")
+		}
+		m.resumeBaseline = ""
+	}
+}
+
+// saveJournal persists the session state if it changed since the last save.
+// Errors are ignored: the journal is a best-effort safety net.
+func (m *model) saveJournal() {
+	if m.config.JournalPath == "" {
+		return
+	}
+	s := m.session()
+	if m.journaled != nil && reflect.DeepEqual(*m.journaled, s) {
+		return
+	}
+	saved := s
+	s.SavedAt = time.Now()
+	if err := state.Save(m.config.JournalPath, s); err == nil {
+		m.journaled = &saved
+	}
+}
+
+// commandState returns the view state to restore the next time the command
+// is watched.
+func (m model) commandState() state.Command {
+	c := state.Command{
+		Filter:     m.filterInput.Text,
+		FilterKind: string(m.filterKind()),
+		Preview:    m.showPreview,
+	}
+	if c.Filter == "" {
+		c.FilterKind = ""
+	}
+	if idx := m.selectedIndex(); idx >= 0 && idx < len(m.lines) {
+		c.Anchor = stripANSI(m.lines[idx].Content)
+	}
+	return c
+}
+
+// saveCommandState remembers the view state for the command. Errors are
+// ignored, as for the journal.
+func (m *model) saveCommandState() {
+	if m.config.StatePath == "" || m.replaying() {
+		return
+	}
+	c := m.commandState()
+	c.SavedAt = time.Now()
+	_ = state.SaveCommand(m.config.StatePath, m.config.Command, c)
+}
+
+// restoreAnchor selects the line that was selected when the command was
+// last watched, once its first run is in.
+func (m *model) restoreAnchor() {
+	if m.pendingAnchor == "" {
+		return
+	}
+	m.anchor, m.pendingAnchor = m.pendingAnchor, ""
+	m.reanchorCursor()
+}
@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+func TestSaveJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	m := testModel(Config{Command: "ls -la", Shell: "bash", JournalPath: path})
+	m.filterInput.Text = "*.go"
+	m.setFilterKind(filter.Glob)
+
+	m.saveJournal()
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if s.Command != "ls -la" || s.Shell != "bash" {
+		t.Errorf("expected command and shell to be saved, got %+v", s)
+	}
+	if s.Filter != "*.go" || s.FilterKind != string(filter.Glob) {
+		t.Errorf("expected glob filter to be saved, got %+v", s)
+	}
+}
+
+func TestSaveJournalSkipsUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	m := testModel(Config{Command: "ls", Shell: "sh", JournalPath: path})
+
+	m.saveJournal()
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	m.saveJournal()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Error("expected unchanged session not to be rewritten")
+	}
+
+	m.filterInput.Text = "err"
+	m.saveJournal()
+	s, _ := state.Load(path)
+	if s.Filter != "err" {
+		t.Errorf("expected changed filter to be saved, got %q", s.Filter)
+	}
+}
+
+func TestSaveJournalDisabled(t *testing.T) {
+	m := testModel(Config{Command: "ls", Shell: "sh"})
+	m.saveJournal()
+	if m.journaled != nil {
+		t.Error("expected no journal without a JournalPath")
+	}
+}
+
+func TestJournalBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	finish := func(m *model, output ...string) {
+		lines := make([]runner.Line, len(output))
+		for i, content := range output {
+			lines[i] = runner.Line{Number: i + 1, Content: content}
+		}
+		m.streaming = true
+		m.streamResult = &runner.StreamingResult{Lines: &lines, Done: true, CurrentLineCount: len(lines)}
+		m.Update(streamTickMsg{generation: m.refreshGeneration})
+	}
+
+	m := testModel(Config{Command: "ls", Shell: "sh", JournalPath: path})
+	finish(m, "a", "b", "c")
+	m.saveJournal()
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Baseline == "" {
+		t.Fatal("expected the run's baseline to be journaled")
+	}
+
+	// Resuming compares the first run with the baseline
+	resumed := testModel(Config{Command: "ls", Shell: "sh", Baseline: s.Baseline})
+	finish(resumed, "c", "b", "a")
+	if resumed.statusMsg != i18n.T("status.output_changed") {
+		t.Errorf("expected the changed output to be reported, got %q", resumed.statusMsg)
+	}
+
+	resumed = testModel(Config{Command: "ls", Shell: "sh", Baseline: s.Baseline})
+	finish(resumed, "a", "b", "c")
+	if resumed.statusMsg != i18n.T("status.output_unchanged") {
+		t.Errorf("expected the unchanged output to be reported, got %q", resumed.statusMsg)
+	}
+}
+
+func TestInitialFilterFromConfig(t *testing.T) {
+	m := testModel(Config{Command: "ls", Shell: "sh", Filter: "^foo", FilterKind: filter.Regex})
+	if m.filterInput.Text != "^foo" {
+		t.Errorf("expected filter text '^foo', got %q", m.filterInput.Text)
+	}
+	if m.filterKind() != filter.Regex {
+		t.Errorf("expected regex filter kind, got %q", m.filterKind())
+	}
+}
+
+func TestSaveJournalNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	m := testModel(Config{Command: "ls", Shell: "sh", JournalPath: path})
+	m.saveJournal()
+
+	// Notes are mutated in place, so the journaled copy must not alias them
+	m.notes = map[string]string{}
+	m.notes[noteKey("boom")] = "first failure"
+	m.saveJournal()
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Notes[noteKey("boom")] != "first failure" {
+		t.Errorf("expected note to be journaled, got %v", s.Notes)
+	}
+
+	m.notes[noteKey("boom")] = "edited"
+	m.saveJournal()
+	s, _ = state.Load(path)
+	if s.Notes[noteKey("boom")] != "edited" {
+		t.Errorf("expected edited note to be journaled, got %v", s.Notes)
+	}
+}
+
+func TestJournalMarks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	m := testModelWithLines()
+	m.config.JournalPath = path
+	m.marked = map[int]bool{m.lines[1].Number: true}
+	m.saveJournal()
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Marks) != 1 || s.Marks[0] != noteKey(m.lines[1].Content) {
+		t.Fatalf("expected the marked line to be journaled, got %v", s.Marks)
+	}
+
+	// Resumed marks follow the line's content once the first run finishes
+	resumed := testModel(Config{Command: "ls", Shell: "sh", Marks: s.Marks})
+	resumed.lines = []runner.Line{
+		{Number: 1, Content: "new first line"},
+		{Number: 2, Content: m.lines[0].Content},
+		{Number: 3, Content: m.lines[1].Content},
+	}
+	if got := resumed.session().Marks; len(got) != 1 {
+		t.Errorf("expected pending marks to be kept until the first run, got %v", got)
+	}
+	resumed.restoreSession()
+	if !resumed.isMarked(3) || resumed.isMarked(2) || len(resumed.marked) != 1 {
+		t.Errorf("expected only line 3 to be re-marked, got %v", resumed.marked)
+	}
+}
+
+func TestCommandState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.json")
+	m := testModel(Config{Command: "ls", Shell: "sh", StatePath: path})
+	m.width, m.height = 80, 30
+	finishRun(m, "a", "b", "c")
+	m.cursor = 1
+	m.showPreview = true
+	m.filterInput.Text = "^[ab]"
+	m.setFilterKind(filter.Regex)
+	m.updateFiltered()
+	m.saveCommandState()
+
+	c, ok := state.LoadCommand(path, "ls")
+	if !ok {
+		t.Fatal("expected the command's state to be saved")
+	}
+	if c.Filter != "^[ab]" || c.FilterKind != string(filter.Regex) || !c.Preview || c.Anchor != "b" {
+		t.Errorf("unexpected saved state %+v", c)
+	}
+
+	m = testModel(Config{Command: "ls", Shell: "sh", Anchor: c.Anchor})
+	m.width, m.height = 80, 30
+	finishRun(m, "a", "b", "c")
+	if line, _ := m.selectedPlain(); line != "b" {
+		t.Errorf("expected the remembered line to be selected, got %q", line)
+	}
+}
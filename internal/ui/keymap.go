@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Keymap translates keys to the default keys they act as, so a keymap only
+// lists what it changes. A key translated to "" does nothing.
+type Keymap map[string]string
+
+// Keymaps are the keymap profiles, by name. vim is the default, and the
+// others are layered over it; --bind keys override all of them.
+var Keymaps = map[string]Keymap{
+	"vim": {},
+	"emacs": {
+		"ctrl+v": "pgdown",
+		"alt+v":  "pgup",
+		"alt+<":  "home",
+		"alt+>":  "end",
+		"ctrl+f": "right",
+		"ctrl+b": "left",
+		"ctrl+s": "?", // search; :w still writes output to a file
+		"ctrl+g": "esc",
+	},
+	// Only arrows and the other navigation keys move, so stray letters
+	// don't jump around
+	"arrows": {
+		"j": "", "k": "", "g": "", "G": "", "H": "", "L": "",
+		"ctrl+n": "", "ctrl+p": "", "ctrl+d": "", "ctrl+u": "", "ctrl+f": "", "ctrl+b": "",
+		"J": "", "K": "",
+		"shift+down": "J",
+		"shift+up":   "K",
+	},
+}
+
+// KeymapNames returns the names of the keymaps, sorted.
+func KeymapNames() []string {
+	names := make([]string, 0, len(Keymaps))
+	for name := range Keymaps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseKeymap returns the named keymap; empty means vim.
+func ParseKeymap(name string) (Keymap, error) {
+	if name == "" {
+		name = "vim"
+	}
+	k, ok := Keymaps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown keymap %q (expected %s)", name, strings.Join(KeymapNames(), ", "))
+	}
+	return k, nil
+}
+
+// translate returns the default key that key acts as, and false if the
+// keymap unbinds it.
+func (k Keymap) translate(key string) (string, bool) {
+	to, ok := k[key]
+	if !ok {
+		return key, true
+	}
+	return to, to != ""
+}
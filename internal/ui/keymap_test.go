@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseKeymap(t *testing.T) {
+	if k, err := ParseKeymap(""); err != nil || len(k) != 0 {
+		t.Errorf("expected vim's empty keymap by default, got %v, %v", k, err)
+	}
+	if _, err := ParseKeymap("emacs"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := ParseKeymap("nano"); err == nil {
+		t.Error("expected an error for an unknown keymap")
+	}
+}
+
+func TestEmacsKeymap(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Keymap = Keymaps["emacs"]
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlV})
+	if m.cursor != len(m.filtered)-1 {
+		t.Errorf("expected C-v to page down, cursor at %d", m.cursor)
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}, Alt: true})
+	if m.cursor != 0 {
+		t.Errorf("expected M-< to go to the first line, cursor at %d", m.cursor)
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.searchMode {
+		t.Error("expected C-s to start a search")
+	}
+}
+
+func TestArrowsKeymap(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Keymap = Keymaps["arrows"]
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if m.cursor != 0 {
+		t.Errorf("expected j to do nothing, cursor at %d", m.cursor)
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if m.cursor != 1 {
+		t.Errorf("expected down to move, cursor at %d", m.cursor)
+	}
+
+	// Keys bound with --bind override the keymap
+	m.config.Bindings = map[string]Binding{"j": {Action: BindSilent, Command: "true"}}
+	if _, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}); cmd == nil {
+		t.Error("expected the bound command to run")
+	}
+}
@@ -2,12 +2,19 @@ package ui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/filter"
 )
 
 func (m *model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.showHelp {
 		return m.handleHelpMode(msg)
 	}
+	if m.showRegisters {
+		return m.handleRegistersMode(msg)
+	}
+	if m.columnsMode {
+		return m.handleColumnsMode(msg)
+	}
 	if m.confirmMode {
 		return m.handleConfirmMode(msg)
 	}
@@ -17,12 +24,24 @@ func (m *model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.filterMode {
 		return m.handleFilterMode(msg)
 	}
+	if m.noteMode {
+		return m.handleNoteMode(msg)
+	}
+	if m.pipeMode {
+		return m.handlePipeMode(msg)
+	}
+	if m.searchMode {
+		return m.handleSearchMode(msg)
+	}
+	if m.previewSearchMode {
+		return m.handlePreviewSearchMode(msg)
+	}
 	return m.handleNormalMode(msg)
 }
 
 func (m *model) handleHelpMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "?", "esc", "q", "enter":
+	case "h", "f1", "esc", "q", "enter":
 		m.showHelp = false
 	}
 	return m, nil
@@ -49,6 +68,12 @@ func (m *model) handleCmdPaletteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cmdPaletteSelected = 0
 		return m, nil
 	case tea.KeyEnter:
+		if wc, ok := parseWriteCommand(m.cmdPaletteInput.Text); ok {
+			m.cmdPaletteMode = false
+			m.cmdPaletteInput.clear()
+			m.cmdPaletteSelected = 0
+			return m.writeOutput(wc)
+		}
 		filtered := m.filteredCommands()
 		if len(filtered) > 0 && m.cmdPaletteSelected < len(filtered) {
 			m.cmdPaletteMode = false
@@ -81,20 +106,30 @@ func (m *model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.filterMode = false
-		m.filterInput.clear()
-		m.filterRegex = false
-		m.filterRegexErr = nil
-		m.updateFiltered()
+		m.resetFilter()
 		return m, nil
 	case tea.KeyEnter:
 		m.filterMode = false
 		return m, nil
+	case tea.KeyTab:
+		m.cycleFilterKind()
+		return m, nil
+	case tea.KeyCtrlX:
+		return m.actionEditFilter()
+	case tea.KeyCtrlP:
+		m.pinFilter()
+		return m, nil
 	default:
+		if msg.String() == "alt+c" {
+			return m.actionCycleCase()
+		}
+		// Backspace on an empty filter pops the last pinned one for editing
+		if msg.Type == tea.KeyBackspace && m.filterInput.Text == "" && m.popFilter(true) {
+			return m, nil
+		}
 		// Special case: "/" on empty filter toggles regex mode
 		if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 && string(msg.Runes) == "/" && m.filterInput.Text == "" {
-			m.filterRegex = !m.filterRegex
-			m.filterRegexErr = nil
-			m.updateFiltered()
+			m.toggleFilterKind(filter.Regex)
 			return m, nil
 		}
 		m.filterInput.handleKey(msg)
@@ -103,56 +138,123 @@ func (m *model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// maxPendingCount caps count prefixes so a held digit key can't overflow
+const maxPendingCount = 99999
+
 func (m *model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	if m.previewFocused && m.handlePreviewFocus(msg) {
+		return m, nil
+	}
+
+	// Digits build up a count for the next motion, as in vim (0 only
+	// continues a count, it never starts one)
+	key := msg.String()
+	if len(key) == 1 && key >= "0" && key <= "9" && (key != "0" || m.pendingCount > 0) {
+		m.pendingCount = min(m.pendingCount*10+int(key[0]-'0'), maxPendingCount)
+		return m, nil
+	}
+	count := max(m.pendingCount, 1)
+	hasCount := m.pendingCount > 0
+	m.pendingCount = 0
+	if hasCount && key == "esc" {
+		return m, nil
+	}
+	m.yankedInto = ""
+	if m.registerPending {
+		return m.handleRegisterKey(key)
+	}
+	if m.register != "" {
+		// A register only applies to the key right after it
+		defer func() { m.register = "" }()
+		if key == "p" {
+			return m.actionPutRegister(m.register)
+		}
+	}
+	if b, ok := m.config.Bindings[key]; ok {
+		return m.runBinding(b)
+	}
+	key, ok := m.config.Keymap.translate(key)
+	if !ok {
+		return m, nil
+	}
+
+	switch key {
 	case "q", "ctrl+c":
 		return m.actionQuit()
 	case "esc":
-		if m.filterInput.Text != "" || m.filterRegex {
-			m.filterInput.clear()
-			m.filterRegex = false
-			m.filterRegexErr = nil
+		if m.clearPipeOutput() {
+			return m, nil
+		}
+		if m.filterInput.Text != "" || m.filterKind() != m.defaultFilterKind() {
+			m.resetFilter()
+			return m, nil
+		}
+		if len(m.pinnedFilters) > 0 {
+			m.pinnedFilters = nil
 			m.updateFiltered()
 			return m, nil
 		}
+		if m.searchQuery != "" {
+			m.searchQuery = ""
+			return m, nil
+		}
+		if len(m.marked) > 0 {
+			m.clearMarks()
+			return m, nil
+		}
 		return m.actionQuit()
 
 	case "j", "down", "ctrl+n":
 		m.userScrolled = true
-		m.moveCursor(1)
+		m.moveCursor(count)
 	case "k", "up", "ctrl+p":
 		m.userScrolled = true
-		m.moveCursor(-1)
+		m.moveCursor(-count)
 	case "g", "home":
+		if hasCount {
+			return m.actionGoToLine(count)
+		}
 		return m.actionGoToFirst()
 	case "G", "end":
+		if hasCount {
+			return m.actionGoToLine(count)
+		}
 		return m.actionGoToLast()
 	case "ctrl+d":
 		m.userScrolled = true
-		m.moveCursor(m.visibleLines() / 2)
+		m.moveCursor(count * m.visibleLines() / 2)
 	case "ctrl+u":
 		m.userScrolled = true
-		m.moveCursor(-m.visibleLines() / 2)
+		m.moveCursor(-count * m.visibleLines() / 2)
 	case "J":
-		if m.showPreview {
-			m.previewOffset++
-			m.clampPreviewOffset()
-		}
+		m.scrollPreview(count)
 	case "K":
-		if m.showPreview && m.previewOffset > 0 {
-			m.previewOffset--
-		}
+		m.scrollPreview(-count)
 	case "pgdown", "ctrl+f":
 		m.userScrolled = true
-		m.moveCursor(m.visibleLines())
+		m.moveCursor(count * m.visibleLines())
 	case "pgup", "ctrl+b":
 		m.userScrolled = true
-		m.moveCursor(-m.visibleLines())
+		m.moveCursor(-count * m.visibleLines())
 	case "p":
 		return m.actionTogglePreview()
-	case "+", "=":
+	case "x":
+		return m.actionToggleContextPreview()
+	case "w":
+		return m.actionToggleWrap()
+	case "#":
+		return m.actionToggleLineNumbers()
+	case "W":
+		return m.actionTogglePreviewWrap()
+	case "u":
+		return m.actionCycleDedup()
+	case "P":
+		return m.actionPopFilter()
+	case "C":
+		return m.actionCycleCase()
+	case "+", "=", ">":
 		return m.actionIncreasePreview()
-	case "-":
+	case "-", "<":
 		return m.actionDecreasePreview()
 	case "r", "ctrl+r":
 		return m.actionReload()
@@ -166,14 +268,67 @@ func (m *model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.actionStopCommand()
 	case "/":
 		return m.actionEnterFilter()
+	case "ctrl+x":
+		return m.actionEditFilter()
+	case "|":
+		return m.actionEnterPipe()
+	case "e", "enter":
+		if key == "enter" && m.config.PrintSelection {
+			return m.actionAcceptSelection()
+		}
+		return m.actionOpenInEditor()
 	case ":":
 		return m.actionOpenPalette()
-	case "?":
+	case "ctrl+s":
+		return m.actionEnterWrite()
+	case "h", "f1":
 		return m.actionShowHelp()
+	case "?":
+		return m.actionEnterSearch()
+	case "n":
+		return m.actionSearchNext(1)
+	case "N":
+		return m.actionSearchNext(-1)
+	case "tab":
+		return m.actionToggleMark(1)
+	case "shift+tab":
+		return m.actionToggleMark(-1)
+	case `"`:
+		m.registerPending = true
+	case "f":
+		return m.actionYankField()
+	case "M":
+		return m.actionCopyMarkdown()
+	case "V":
+		return m.actionCopyRendered()
+	case "t":
+		return m.actionToggleTable()
+	case "T":
+		return m.actionToggleHeader()
+	case "O":
+		return m.actionColumns()
+	case "ctrl+t":
+		return m.actionToggleTitle()
+	case "H", "left":
+		return m.actionScrollColumns(-count)
+	case "L", "right":
+		return m.actionScrollColumns(count)
 	case "y":
 		return m.actionCopyLine(false)
 	case "Y":
 		return m.actionCopyLine(true)
+	case "ctrl+y":
+		return m.actionCopyFiltered()
+	case "alt+y":
+		return m.actionCopyAll()
+	case "a":
+		return m.actionEditNote()
+	case "A":
+		return m.actionNextNote()
+	case "[":
+		return m.actionReplayStep(-1)
+	case "]":
+		return m.actionReplayStep(1)
 	}
 
 	return m, nil
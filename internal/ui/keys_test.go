@@ -2,9 +2,12 @@ package ui
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/filter"
 	"github.com/chenasraf/watchr/internal/runner"
 )
 
@@ -677,19 +680,19 @@ func TestKeyCmdPaletteOpenAndNav(t *testing.T) {
 func TestKeyHelpToggle(t *testing.T) {
 	m := testModelWithLines()
 
-	// '?' opens help
-	keyMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+	// 'h' opens help
+	keyMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}
 	result, _ := m.handleKeyPress(keyMsg)
 	newModel := result.(*model)
 	if !newModel.showHelp {
-		t.Error("expected showHelp true after '?'")
+		t.Error("expected showHelp true after 'h'")
 	}
 
-	// '?' closes help
+	// 'h' closes help
 	result, _ = newModel.handleKeyPress(keyMsg)
 	newModel = result.(*model)
 	if newModel.showHelp {
-		t.Error("expected showHelp false after second '?'")
+		t.Error("expected showHelp false after second 'h'")
 	}
 }
 
@@ -791,3 +794,193 @@ func TestKeyYankPlain(t *testing.T) {
 		t.Error("expected a command for status timeout")
 	}
 }
+
+func TestFilterTabCyclesMode(t *testing.T) {
+	m := testModelWithLines()
+	m.filterMode = true
+
+	keyMsg := tea.KeyMsg{Type: tea.KeyTab}
+	want := []filter.Kind{filter.Regex, filter.Glob, filter.Fuzzy, filter.Substring}
+	for _, kind := range want {
+		result, _ := m.handleKeyPress(keyMsg)
+		m = result.(*model)
+		if got := m.filterKind(); got != kind {
+			t.Errorf("expected filter kind %q after Tab, got %q", kind, got)
+		}
+	}
+}
+
+func TestFuzzyFilterRanksMatches(t *testing.T) {
+	m := testModelWithLines()
+	m.lines = []runner.Line{
+		{Number: 1, Content: "pending on disk"},
+		{Number: 2, Content: "kubelet pod ready"},
+		{Number: 3, Content: "no match here"},
+		{Number: 4, Content: "xpod"},
+	}
+	m.setFilterKind(filter.Fuzzy)
+	m.filterInput.Text = "pod"
+	m.updateFiltered()
+
+	// Word start, then mid-word, then spread out
+	if want := []int{1, 3, 0}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected ranked matches %v, got %v", want, m.filtered)
+	}
+}
+
+func TestFilterModeDefault(t *testing.T) {
+	m := testModel(Config{Command: "echo", Shell: "sh", FilterMode: filter.Fuzzy})
+	m.height, m.width = 30, 80
+	if got := m.filterKind(); got != filter.Fuzzy {
+		t.Fatalf("expected the configured filter mode, got %q", got)
+	}
+
+	// Toggling regex off returns to the configured mode
+	m.actionToggleRegexFilter()
+	m.actionToggleRegexFilter()
+	if got := m.filterKind(); got != filter.Fuzzy {
+		t.Errorf("expected toggling regex off to return to fuzzy, got %q", got)
+	}
+
+	// Esc in normal mode with no filter quits rather than resetting the mode
+	m.filterMode = false
+	_, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Error("expected Esc to quit when the filter is already at its default")
+	}
+}
+
+func TestFilterSmartCase(t *testing.T) {
+	m := testModel(Config{Command: "echo", Shell: "sh", FilterCase: filter.SmartCase})
+	m.height, m.width = 30, 80
+	m.lines = []runner.Line{
+		{Number: 1, Content: "Error: disk full"},
+		{Number: 2, Content: "error: retrying"},
+	}
+
+	m.filterInput.Text = "error"
+	m.updateFiltered()
+	if want := []int{0, 1}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected a lowercase filter to ignore case, got %v", m.filtered)
+	}
+
+	m.filterInput.Text = "Error"
+	m.updateFiltered()
+	if want := []int{0}; !slices.Equal(m.filtered, want) {
+		t.Errorf("expected an uppercase letter to make the filter case-sensitive, got %v", m.filtered)
+	}
+}
+
+func TestCycleCaseKey(t *testing.T) {
+	m := testModelWithLines()
+	m.filterInput.Text = "Hello"
+	m.updateFiltered()
+	if len(m.filtered) != 2 {
+		t.Fatalf("expected the default to ignore case, got %d matches", len(m.filtered))
+	}
+
+	want := []filter.Case{filter.SmartCase, filter.SensitiveCase, filter.IgnoreCase}
+	for _, c := range want {
+		result, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+		m = result.(*model)
+		if m.filterCase != c {
+			t.Errorf("expected case mode %q after C, got %q", c, m.filterCase)
+		}
+		if c != filter.IgnoreCase && len(m.filtered) != 0 {
+			t.Errorf("expected no matches for %q in %q mode, got %d", "Hello", c, len(m.filtered))
+		}
+	}
+	if m.statusMsg != "Case: ignore" {
+		t.Errorf("expected the status to show the case mode, got %q", m.statusMsg)
+	}
+
+	// Alt+c cycles while typing a filter
+	m.filterMode = true
+	result, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c"), Alt: true})
+	m = result.(*model)
+	if m.filterCase != filter.SmartCase {
+		t.Errorf("expected Alt+c to cycle the case mode, got %q", m.filterCase)
+	}
+	if m.filterInput.Text != "Hello" {
+		t.Errorf("expected Alt+c not to edit the filter, got %q", m.filterInput.Text)
+	}
+}
+
+func TestNormalModeEscClearsGlob(t *testing.T) {
+	m := testModelWithLines()
+	m.filterGlob = true
+
+	result, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(*model)
+	if m.filterGlob {
+		t.Error("expected filterGlob to be false after Esc")
+	}
+}
+
+func TestCountPrefix(t *testing.T) {
+	m := testModelWithLines()
+	for i := range 20 {
+		m.lines = append(m.lines, runner.Line{Number: 5 + i, Content: "more"})
+	}
+	m.updateFiltered()
+	keys := func(s string) {
+		for _, r := range s {
+			m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	keys("15j")
+	if m.cursor != 15 {
+		t.Errorf("expected 15j to move 15 lines, got cursor %d", m.cursor)
+	}
+	if m.pendingCount != 0 {
+		t.Errorf("expected the count to reset after the motion, got %d", m.pendingCount)
+	}
+	keys("10k")
+	if m.cursor != 5 {
+		t.Errorf("expected 10k to move up 10 lines, got cursor %d", m.cursor)
+	}
+	keys("3G")
+	if m.cursor != 2 {
+		t.Errorf("expected 3G to go to the third line, got cursor %d", m.cursor)
+	}
+	keys("0")
+	if m.cursor != 2 || m.pendingCount != 0 {
+		t.Error("expected 0 not to start a count")
+	}
+
+	keys("12")
+	if got := stripANSI(m.renderPromptLine()); !strings.Contains(got, "12  3/24") {
+		t.Errorf("expected the pending count in the prompt, got %q", got)
+	}
+	result, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(*model)
+	if m.pendingCount != 0 || cmd != nil {
+		t.Error("expected Esc to cancel the count without quitting")
+	}
+}
+
+func TestPrintSelectionEnter(t *testing.T) {
+	m := testModelWithLines()
+	m.config.PrintSelection = true
+	m.cursor = 1
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !slices.Equal(m.selection, []string{"foo bar"}) {
+		t.Errorf("expected the selected line, got %q", m.selection)
+	}
+	if cmd == nil {
+		t.Fatal("expected Enter to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected a quit message")
+	}
+
+	m = testModelWithLines()
+	m.config.PrintSelection = true
+	m.marked = map[int]bool{3: true, 1: true}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !slices.Equal(m.selection, []string{"hello world", "hello foo"}) {
+		t.Errorf("expected the marked lines in order, got %q", m.selection)
+	}
+}
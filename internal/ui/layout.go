@@ -1,8 +1,12 @@
 package ui
 
 import (
-	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
 )
 
 func (m *model) moveCursor(delta int) {
@@ -20,23 +24,142 @@ func (m *model) moveCursor(delta int) {
 	m.adjustOffset()
 }
 
+// rememberAnchor records the selected line's content so the cursor can follow
+// it when a refresh shifts the output.
+func (m *model) rememberAnchor() {
+	m.anchor = ""
+	if idx := m.selectedIndex(); idx >= 0 && idx < len(m.lines) {
+		m.anchor = stripANSI(m.lines[idx].Content)
+	}
+}
+
+// reanchorCursor moves the cursor to the line matching the anchor nearest to
+// its current position. The cursor stays put when no line matches.
+func (m *model) reanchorCursor() {
+	if m.anchor == "" {
+		return
+	}
+	for d := 0; d < len(m.filtered); d++ {
+		for _, i := range []int{m.cursor - d, m.cursor + d} {
+			if i < 0 || i >= len(m.filtered) || m.filtered[i] >= len(m.lines) {
+				continue
+			}
+			if stripANSI(m.lines[m.filtered[i]].Content) == m.anchor {
+				if i != m.cursor {
+					m.cursor = i
+					m.adjustOffset()
+				}
+				return
+			}
+		}
+	}
+}
+
 func (m *model) adjustOffset() {
 	visible := m.visibleLines()
 	if visible <= 0 {
 		return
 	}
+	if m.config.ScrollOff >= 0 {
+		m.scrollWithMargin(visible, min(m.config.ScrollOff, (visible-1)/2))
+		return
+	}
 
-	// Try to center the cursor
-	idealOffset := m.cursor - visible/2
+	// Try to center the cursor, counting rows so wrapped lines above it
+	// don't push it off screen
+	idealOffset, above := m.cursor, 0
+	for idealOffset > 0 {
+		h := m.lineHeight(idealOffset - 1)
+		if above+h > visible/2 {
+			break
+		}
+		above += h
+		idealOffset--
+	}
 
 	// Clamp to valid range
 	idealOffset = max(idealOffset, 0)
-	maxOffset := max(len(m.filtered)-visible, 0)
-	idealOffset = min(idealOffset, maxOffset)
+	idealOffset = min(idealOffset, m.maxOffset())
 
 	m.offset = idealOffset
 }
 
+// scrollWithMargin scrolls only as far as needed to keep margin rows visible
+// above and below the cursor, rather than re-centering it.
+func (m *model) scrollWithMargin(visible, margin int) {
+	offset := m.offset
+	if offset > m.cursor || m.rowsBetween(offset, m.cursor) < margin {
+		above := 0
+		offset = m.cursor
+		for offset > 0 && above+m.lineHeight(offset-1) <= margin {
+			above += m.lineHeight(offset - 1)
+			offset--
+		}
+	}
+	for offset < m.cursor && m.rowsBetween(offset, m.cursor+1)+margin > visible {
+		offset++
+	}
+	m.offset = max(min(offset, m.maxOffset()), 0)
+}
+
+// rowsBetween counts the list rows taken by filtered lines from up to to.
+func (m model) rowsBetween(from, to int) int {
+	if !m.wrapLines {
+		return max(to-from, 0)
+	}
+	rows := 0
+	for i := from; i < to; i++ {
+		rows += m.lineHeight(i)
+	}
+	return rows
+}
+
+// selectedTruncated reports whether the selected line is wider than the list
+// can show without a preview pane.
+func (m model) selectedTruncated() bool {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) || m.width == 0 {
+		return false
+	}
+	idx := m.filtered[m.cursor]
+	if idx >= len(m.lines) {
+		return false
+	}
+	line := m.lines[idx]
+	width := m.innerWidth() - 1 // the list's right margin
+	if m.config.ShowLineNums {
+		width -= lipgloss.Width(m.lineGutter(line))
+	}
+	return lipgloss.Width(m.displayed(line.Content)) > width
+}
+
+// syncAutoPreview opens the preview when the selected line is truncated and
+// closes it again once it isn't, if AutoPreview is enabled. A preview the user
+// opened manually is left alone, and one they closed stays closed for that line.
+func (m *model) syncAutoPreview() {
+	if !m.config.AutoPreview {
+		return
+	}
+	truncated := m.selectedTruncated()
+	switch {
+	case truncated && !m.showPreview && m.autoPreviewDismissed != m.selectedIndex():
+		m.showPreview = true
+		m.autoPreviewOpened = true
+		m.adjustOffset()
+	case !truncated && m.showPreview && m.autoPreviewOpened:
+		m.showPreview = false
+		m.autoPreviewOpened = false
+		m.adjustOffset()
+	}
+}
+
+// selectedIndex returns the index into lines of the selected line, or -1.
+func (m model) selectedIndex() int {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return -1
+	}
+	return m.filtered[m.cursor]
+}
+
 func previewSizeStep(isPercent bool) int {
 	if isPercent {
 		return 5
@@ -47,42 +170,39 @@ func previewSizeStep(isPercent bool) int {
 // clampPreviewOffset computes the actual preview content size and clamps
 // previewOffset so it can't exceed the scrollable range.
 func (m *model) clampPreviewOffset() {
-	if !m.showPreview || m.cursor < 0 || m.cursor >= len(m.filtered) {
+	content := m.previewContent()
+	if !m.showPreview || content == "" {
 		m.previewOffset = 0
 		return
 	}
-	idx := m.filtered[m.cursor]
-	if idx >= len(m.lines) {
-		m.previewOffset = 0
-		return
+	previewLines := m.previewRows(content, m.previewWidth())
+	maxOffset := max(len(previewLines)-m.previewHeight(), 0)
+	if m.previewOffset > maxOffset {
+		m.previewOffset = maxOffset
 	}
+}
 
-	content := highlightJSON(m.lines[idx].Content)
-	innerWidth := m.width - 2
-
-	var previewW, visibleH int
-	switch m.config.PreviewPosition {
-	case PreviewTop, PreviewBottom:
-		previewW = innerWidth
-		visibleH = m.previewSize()
-	case PreviewLeft:
-		previewW = m.previewSize()
-		visibleH = m.visibleLines()
-	case PreviewRight:
-		previewW = m.previewSize()
-		visibleH = m.visibleLines()
+// previewHeight returns how many rows of content the preview pane shows.
+func (m model) previewHeight() int {
+	if m.config.PreviewPosition == PreviewTop || m.config.PreviewPosition == PreviewBottom {
+		return m.previewSize()
 	}
+	return m.visibleLines()
+}
 
-	previewLines := wrapPreviewContent(content, previewW)
-	maxOffset := max(len(previewLines)-visibleH, 0)
-	if m.previewOffset > maxOffset {
-		m.previewOffset = maxOffset
+// scrollPreview scrolls the preview by delta lines, if it's open.
+func (m *model) scrollPreview(delta int) {
+	if !m.showPreview {
+		return
 	}
+	m.previewOffset = max(m.previewOffset+delta, 0)
+	m.clampPreviewOffset()
 }
 
 // applyPreviewOffset slices previewLines based on the current preview scroll
-// offset, clamping the offset so it doesn't scroll past the content.
-func (m *model) applyPreviewOffset(previewLines []string, visibleH int) []string {
+// offset, clamping the offset so it doesn't scroll past the content. When
+// more content follows, the last visible row says how much, cut to width.
+func (m *model) applyPreviewOffset(previewLines []string, visibleH, width int) []string {
 	maxOffset := max(len(previewLines)-visibleH, 0)
 	if m.previewOffset > maxOffset {
 		m.previewOffset = maxOffset
@@ -90,9 +210,43 @@ func (m *model) applyPreviewOffset(previewLines []string, visibleH int) []string
 	if m.previewOffset > 0 {
 		previewLines = previewLines[m.previewOffset:]
 	}
+	if visibleH > 1 && len(previewLines) > visibleH {
+		more := len(previewLines) - visibleH + 1
+		moreStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+		indicator := truncateToWidth(i18n.T("preview.more", more), width)
+		previewLines = append(previewLines[:visibleH-1:visibleH-1], moreStyle.Render(indicator))
+	}
 	return previewLines
 }
 
+// previewContent returns what the preview shows for the selected line: its
+// linePreview, the PreviewCommand's output for it, or the output around it in
+// context mode, under its note if it has one. Piped command output replaces
+// it until dismissed.
+func (m model) previewContent() string {
+	if m.pipeCommand != "" {
+		return m.pipePreview()
+	}
+	idx := m.selectedIndex()
+	if idx < 0 || idx >= len(m.lines) {
+		return ""
+	}
+	var content string
+	switch {
+	case m.previewContext:
+		content = m.contextPreview(idx)
+	case m.config.PreviewCommand != "":
+		content = m.commandPreview(stripANSI(m.lines[idx].Content))
+	default:
+		content = m.linePreview(idx)
+	}
+	if note := m.noteFor(m.lines[idx].Content); note != "" {
+		noteStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Filter)
+		content = noteStyle.Render(noteMarker+" "+note) + "\n" + content
+	}
+	return content
+}
+
 func (m model) previewSize() int {
 	if m.config.PreviewSizeIsPercent {
 		if m.config.PreviewPosition == PreviewLeft || m.config.PreviewPosition == PreviewRight {
@@ -103,9 +257,50 @@ func (m model) previewSize() int {
 	return m.config.PreviewSize
 }
 
+// frameSide is the width of the box's left and right borders, which compact
+// mode leaves out.
+func (m model) frameSide() int {
+	if m.config.Compact {
+		return 0
+	}
+	return 1
+}
+
+// innerWidth returns the width inside the box.
+func (m model) innerWidth() int {
+	return m.width - 2*m.frameSide()
+}
+
+// contentTop returns the first screen row of the content area: below the top
+// border, the title and the title separator, or just the title when compact.
+// A hidden title takes its separator with it.
+func (m model) contentTop() int {
+	top := 0
+	if !m.config.Compact {
+		top++
+	}
+	if !m.hideTitle {
+		top++
+		if !m.config.Compact {
+			top++
+		}
+	}
+	return top
+}
+
+// bottomRows returns how many rows follow the content area: the bottom
+// border and the prompt, or just the prompt when compact.
+func (m model) bottomRows() int {
+	if m.config.Compact {
+		return 1
+	}
+	return 2
+}
+
 func (m model) visibleLines() int {
-	// Fixed lines: top border (1) + header (1) + separator (1) + bottom border (1) + prompt (1) = 5
-	fixedLines := 5
+	// Fixed lines: top border (1) + title (1) + separator (1) + bottom border (1) + prompt (1) = 5,
+	// less the box when compact and the title when hidden, plus the pinned header lines
+	fixedLines := m.contentTop() + m.bottomRows() + m.headerLines()
 	if m.showPreview && (m.config.PreviewPosition == PreviewTop || m.config.PreviewPosition == PreviewBottom) {
 		// Add preview height + separator between content and preview
 		return m.height - fixedLines - m.previewSize() - 1
@@ -113,33 +308,142 @@ func (m model) visibleLines() int {
 	return m.height - fixedLines
 }
 
+// minInnerWidth is the narrowest the box can get and still fit the title,
+// the prompt and a useful part of each line
+const minInnerWidth = 20
+
+// minSize returns the smallest window the layout fits in: the box at its
+// narrowest, and everything around the list with one row of output.
+func (m model) minSize() (width, height int) {
+	return minInnerWidth + 2*m.frameSide(), m.height - m.visibleLines() + 1
+}
+
+// tooSmall reports whether the window is smaller than minSize.
+func (m model) tooSmall() bool {
+	width, height := m.minSize()
+	return m.width < width || m.height < height
+}
+
+// renderTooSmall replaces the view while the window is too small for it, until
+// it is resized.
+func (m model) renderTooSmall() string {
+	width, height := m.minSize()
+	msg := i18n.T("view.too_small", width, height, m.width, m.height)
+	style := lipgloss.NewStyle().Foreground(m.config.Theme.Warning).Width(m.width).Align(lipgloss.Center)
+	rows := strings.Split(style.Render(msg), "\n")
+	if len(rows) > m.height {
+		rows = rows[:max(m.height, 0)]
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, strings.Join(rows, "\n"))
+}
+
+// filterKind returns the matching algorithm for the current filter mode.
+func (m model) filterKind() filter.Kind {
+	switch {
+	case m.filterRegex:
+		return filter.Regex
+	case m.filterGlob:
+		return filter.Glob
+	case m.filterFuzzy:
+		return filter.Fuzzy
+	default:
+		return filter.Substring
+	}
+}
+
+// compileFilter compiles a filter or search pattern with the current case
+// mode.
+func (m model) compileFilter(kind filter.Kind, pattern string) (filter.Matcher, error) {
+	return filter.NewCase(kind, pattern, m.filterCase)
+}
+
+// defaultFilterKind returns the matching algorithm the filter resets to.
+func (m model) defaultFilterKind() filter.Kind {
+	if m.config.FilterMode == "" {
+		return filter.Substring
+	}
+	return m.config.FilterMode
+}
+
+// filterKindCycle is the order Tab steps through filter modes
+var filterKindCycle = []filter.Kind{filter.Substring, filter.Regex, filter.Glob, filter.Fuzzy}
+
+// setFilterKind switches the filter to the given matching algorithm.
+func (m *model) setFilterKind(kind filter.Kind) {
+	m.filterRegex = kind == filter.Regex
+	m.filterGlob = kind == filter.Glob
+	m.filterFuzzy = kind == filter.Fuzzy
+	m.filterRegexErr = nil
+	m.updateFiltered()
+}
+
+// toggleFilterKind switches to kind, or back to the default mode if the
+// filter is already using it.
+func (m *model) toggleFilterKind(kind filter.Kind) {
+	if m.filterKind() == kind {
+		kind = m.defaultFilterKind()
+	}
+	m.setFilterKind(kind)
+}
+
+// resetFilter clears the filter text and returns to the default mode.
+func (m *model) resetFilter() {
+	m.filterInput.clear()
+	m.setFilterKind(m.defaultFilterKind())
+}
+
+// cycleFilterKind switches to the next filter mode in filterKindCycle.
+func (m *model) cycleFilterKind() {
+	current := m.filterKind()
+	for i, kind := range filterKindCycle {
+		if kind == current {
+			m.setFilterKind(filterKindCycle[(i+1)%len(filterKindCycle)])
+			return
+		}
+	}
+	m.setFilterKind(filter.Substring)
+}
+
 func (m *model) updateFiltered() {
 	m.filtered = []int{}
 	m.filterRegexErr = nil
+	m.updateTableWidths()
 
-	if m.filterRegex && m.filterInput.Text != "" {
-		re, err := regexp.Compile("(?i)" + m.filterInput.Text)
-		if err != nil {
-			m.filterRegexErr = err
-			// Show all lines when regex is invalid
-			for i := range m.lines {
+	matcher, err := m.compileFilter(m.filterKind(), m.filterInput.Text)
+	if err != nil {
+		m.filterRegexErr = err
+		// Show all lines when the pattern is invalid
+		for i, line := range m.lines {
+			if m.matchesPinned(m.shown(line.Content)) {
 				m.filtered = append(m.filtered, i)
 			}
-		} else {
-			for i, line := range m.lines {
-				if re.MatchString(line.Content) {
-					m.filtered = append(m.filtered, i)
-				}
+		}
+	} else if scorer, ok := matcher.(filter.Scorer); ok {
+		// Rank the best matches first, keeping output order among equals
+		scores := make(map[int]int)
+		for i, line := range m.lines {
+			content := m.shown(line.Content)
+			if score, ok := scorer.Score(content); ok && m.matchesPinned(content) {
+				m.filtered = append(m.filtered, i)
+				scores[i] = score
 			}
 		}
+		slices.SortStableFunc(m.filtered, func(a, b int) int {
+			return scores[b] - scores[a]
+		})
 	} else {
-		filter := strings.ToLower(m.filterInput.Text)
 		for i, line := range m.lines {
-			if m.filterInput.Text == "" || strings.Contains(strings.ToLower(line.Content), filter) {
+			content := m.shown(line.Content)
+			if matcher.Match(content) && m.matchesPinned(content) {
 				m.filtered = append(m.filtered, i)
 			}
 		}
 	}
+	if header := m.headerLines(); header > 0 {
+		m.filtered = slices.DeleteFunc(m.filtered, func(i int) bool { return i < header })
+	}
+
+	m.dedupFiltered()
 
 	// Reset cursor if out of bounds
 	if m.cursor >= len(m.filtered) {
@@ -151,11 +455,7 @@ func (m *model) updateFiltered() {
 
 	// Clamp offset to valid bounds instead of resetting to 0
 	// This preserves scroll position during streaming updates
-	visible := m.visibleLines()
-	if visible > 0 {
-		maxOffset := max(len(m.filtered)-visible, 0)
-		if m.offset > maxOffset {
-			m.offset = maxOffset
-		}
+	if m.visibleLines() > 0 {
+		m.offset = min(m.offset, m.maxOffset())
 	}
 }
@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/filter"
 	"github.com/chenasraf/watchr/internal/runner"
 )
 
@@ -316,30 +319,60 @@ func TestApplyPreviewOffset(t *testing.T) {
 	m := testModelWithLines()
 	lines := []string{"a", "b", "c", "d", "e"}
 
-	// No offset
+	// No offset: the last row says how much more there is
 	m.previewOffset = 0
-	result := m.applyPreviewOffset(lines, 3)
-	if len(result) != 5 {
-		t.Errorf("expected 5 lines with no offset, got %d", len(result))
+	result := m.applyPreviewOffset(lines, 3, 80)
+	if len(result) != 3 || result[1] != "b" {
+		t.Fatalf("expected 3 rows starting at 'a', got %v", result)
+	}
+	if got := stripANSI(result[2]); !strings.HasPrefix(got, "↓ 3 more") {
+		t.Errorf("expected an indicator for the 3 hidden lines, got %q", got)
+	}
+	if lines[2] != "c" {
+		t.Error("expected the indicator not to overwrite the caller's lines")
 	}
 
 	// With offset
 	m.previewOffset = 2
-	result = m.applyPreviewOffset(lines, 3)
+	result = m.applyPreviewOffset(lines, 3, 80)
 	if len(result) != 3 || result[0] != "c" {
 		t.Errorf("expected lines starting at 'c', got %v", result)
 	}
 
 	// Offset clamped if too high
 	m.previewOffset = 10
-	_ = m.applyPreviewOffset(lines, 3)
+	_ = m.applyPreviewOffset(lines, 3, 80)
 	if m.previewOffset != 2 {
 		t.Errorf("expected previewOffset clamped to 2, got %d", m.previewOffset)
 	}
 }
 
+func TestNarrowPreviewMoreIndicator(t *testing.T) {
+	m := testModelWithLines()
+	m.lines = []runner.Line{{Number: 1, Content: strings.Repeat("x", 2000)}}
+	m.width = 100
+	m.height = 20
+	m.showPreview = true
+	m.config.PreviewPosition = PreviewRight
+	m.config.PreviewSize = 20
+	m.config.PreviewSizeIsPercent = true
+	m.updateFiltered()
+
+	// The indicator is wider than the pane and must not overflow it
+	lines := strings.Split(m.View(), "\n")
+	for i, line := range lines {
+		if w := textWidth(stripANSI(line)); w != m.width {
+			t.Fatalf("expected row %d to be %d wide, got %d: %q", i, m.width, w, stripANSI(line))
+		}
+	}
+	if !strings.Contains(m.View(), "↓") {
+		t.Error("expected the preview to say more content follows")
+	}
+}
+
 func TestAdjustOffset(t *testing.T) {
 	m := testModelWithLines()
+	m.config.ScrollOff = -1
 	m.height = 15 // visibleLines = 15 - 5 = 10
 
 	// Cursor near start - offset should be 0
@@ -361,3 +394,146 @@ func TestAdjustOffset(t *testing.T) {
 		t.Errorf("expected offset %d for centered cursor, got %d", expected, m.offset)
 	}
 }
+
+func TestAdjustOffsetScrollOff(t *testing.T) {
+	m := testModelWithLines()
+	m.height = 15 // visibleLines = 10
+	m.filtered = nil
+	for i := range 50 {
+		m.filtered = append(m.filtered, i)
+	}
+
+	// With no margin the list only scrolls once the cursor leaves it
+	m.moveCursor(9)
+	if m.offset != 0 {
+		t.Errorf("expected no scrolling while the cursor is on screen, got offset %d", m.offset)
+	}
+	m.moveCursor(1)
+	if m.offset != 1 {
+		t.Errorf("expected to scroll by one at the bottom edge, got offset %d", m.offset)
+	}
+
+	// A margin keeps lines of context below and above the cursor
+	m.config.ScrollOff = 3
+	m.cursor, m.offset = 0, 0
+	m.moveCursor(6)
+	if m.offset != 0 {
+		t.Errorf("expected no scrolling within the margin, got offset %d", m.offset)
+	}
+	m.moveCursor(1)
+	if m.offset != 1 {
+		t.Errorf("expected 3 lines kept below the cursor, got offset %d", m.offset)
+	}
+	m.moveCursor(-4)
+	if m.offset != 0 {
+		t.Errorf("expected 3 lines kept above the cursor, got offset %d", m.offset)
+	}
+}
+
+func TestFilterGlob(t *testing.T) {
+	cfg := Config{Command: "echo test", Shell: "sh"}
+	m := initialModel(cfg)
+	m.lines = []runner.Line{
+		{Number: 1, Content: "./main.go"},
+		{Number: 2, Content: "./internal/ui/view.go"},
+		{Number: 3, Content: "./internal/ui/view_test.go"},
+		{Number: 4, Content: "./go.mod"},
+	}
+
+	m.setFilterKind(filter.Glob)
+	m.filterInput.Text = "*.go"
+	m.updateFiltered()
+	if len(m.filtered) != 3 {
+		t.Errorf("expected 3 matches for glob '*.go', got %d", len(m.filtered))
+	}
+
+	m.filterInput.Text = "internal/**/*_test.go"
+	m.updateFiltered()
+	if len(m.filtered) != 1 || m.filtered[0] != 2 {
+		t.Errorf("expected only index 2 for 'internal/**/*_test.go', got %v", m.filtered)
+	}
+}
+
+func TestCompactLayout(t *testing.T) {
+	m := testModelWithLines()
+	full := m.visibleLines()
+	m.config.Compact = true
+	if got := m.visibleLines(); got != full+3 {
+		t.Errorf("expected compact mode to free 3 rows, got %d (was %d)", got, full)
+	}
+
+	rows := strings.Split(stripANSI(m.View()), "\n")
+	if len(rows) != m.height {
+		t.Errorf("expected the view to fill %d rows, got %d", m.height, len(rows))
+	}
+	if !strings.Contains(rows[0], "echo test") || !strings.HasPrefix(rows[1], "hello world") {
+		t.Errorf("expected the header then the output without a box, got %q, %q", rows[0], rows[1])
+	}
+	if region, row := m.regionAt(0, 2); region != regionList || row != 1 {
+		t.Errorf("expected the list's second row at (0, 2), got %v row %d", region, row)
+	}
+}
+
+func TestHiddenTitle(t *testing.T) {
+	for _, compact := range []bool{false, true} {
+		m := testModelWithLines()
+		m.config.Compact = compact
+		shown := m.visibleLines()
+		m.actionToggleTitle()
+		freed := 2
+		if compact {
+			freed = 1
+		}
+		if got := m.visibleLines(); got != shown+freed {
+			t.Errorf("compact %v: expected hiding the title to free %d rows, got %d (was %d)", compact, freed, got, shown)
+		}
+
+		rows := strings.Split(stripANSI(m.View()), "\n")
+		if strings.Contains(m.View(), "echo test") {
+			t.Errorf("compact %v: expected the command to be hidden", compact)
+		}
+		if len(rows) != m.height {
+			t.Errorf("compact %v: expected the view to fill %d rows, got %d", compact, m.height, len(rows))
+		}
+		if region, row := m.regionAt(m.frameSide(), m.contentTop()); region != regionList || row != 0 {
+			t.Errorf("compact %v: expected the list's first row at the content top, got %v row %d", compact, region, row)
+		}
+		if !strings.Contains(rows[m.contentTop()], "hello world") {
+			t.Errorf("compact %v: expected the first line at the content top, got %q", compact, rows[m.contentTop()])
+		}
+	}
+}
+
+func TestTooSmall(t *testing.T) {
+	m := testModelWithLines()
+	if m.tooSmall() {
+		t.Fatal("expected 80x30 to fit")
+	}
+	if w, h := m.minSize(); w != minInnerWidth+2 || h != 6 {
+		t.Errorf("expected a minimum of %dx6, got %dx%d", minInnerWidth+2, w, h)
+	}
+
+	for _, size := range [][2]int{{1, 30}, {80, 3}, {10, 2}} {
+		m.width, m.height = size[0], size[1]
+		if !m.tooSmall() {
+			t.Errorf("expected %dx%d to be too small", m.width, m.height)
+		}
+		view := m.View()
+		if !strings.Contains(stripANSI(view), "T") {
+			t.Errorf("expected the too-small screen at %dx%d, got %q", m.width, m.height, view)
+		}
+		// The message wraps and is cut to fit rather than overflowing
+		if w, h := lipgloss.Width(view), lipgloss.Height(view); w > m.width || h > m.height {
+			t.Errorf("expected the too-small screen to fit %dx%d, got %dx%d", m.width, m.height, w, h)
+		}
+	}
+	m.width, m.height = 80, 3
+	if view := m.View(); !strings.Contains(view, "Terminal too small") {
+		t.Errorf("expected the too-small message, got %q", view)
+	}
+
+	m.width, m.height = 40, 10
+	if view := m.View(); strings.Contains(view, "Terminal too small") {
+		t.Error("expected the view to recover once the window grows")
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// actionToggleLineNumbers shows or hides the line number gutter. (N is
+// taken by the previous search match, so this is # as in vim's :set nu.)
+func (m *model) actionToggleLineNumbers() (tea.Model, tea.Cmd) {
+	m.config.ShowLineNums = !m.config.ShowLineNums
+	m.adjustOffset()
+	if m.config.ShowLineNums {
+		m.statusMsg = i18n.T("status.line_numbers_on")
+	} else {
+		m.statusMsg = i18n.T("status.line_numbers_off")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+func (m *model) actionToggleRelativeNumbers() (tea.Model, tea.Cmd) {
+	m.relativeNums = !m.relativeNums
+	if m.relativeNums {
+		m.config.ShowLineNums = true
+		m.statusMsg = i18n.T("status.relative_numbers_on")
+	} else {
+		m.statusMsg = i18n.T("status.relative_numbers_off")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+// lineNumber returns the number shown in line's gutter: its line number in
+// the output or, with relative numbers, its distance from the selected line,
+// which keeps its own number as in vim's relativenumber with number set.
+func (m model) lineNumber(line runner.Line) int {
+	if !m.relativeNums {
+		return line.Number
+	}
+	if p, ok := m.filteredPosition(line); ok && p != m.cursor {
+		return max(p-m.cursor, m.cursor-p)
+	}
+	return line.Number
+}
+
+// filteredPosition returns line's position among the filtered lines,
+// searching outward from the cursor since drawn lines are near it.
+func (m model) filteredPosition(line runner.Line) (int, bool) {
+	at := func(p int) bool {
+		return p >= 0 && p < len(m.filtered) && m.filtered[p] < len(m.lines) && m.lines[m.filtered[p]].Number == line.Number
+	}
+	for d := range len(m.filtered) {
+		switch {
+		case at(m.cursor + d):
+			return m.cursor + d, true
+		case at(m.cursor - d):
+			return m.cursor - d, true
+		}
+	}
+	return 0, false
+}
+
+// lineNumbers returns the number column of line's gutter. With
+// DualLineNums and a filter active, the line's position among the matching
+// lines follows its number (e.g. "42│3").
+func (m model) lineNumbers(line runner.Line) string {
+	number := fmt.Sprintf("%*d", m.config.LineNumWidth, m.lineNumber(line))
+	if !m.config.DualLineNums || (m.filterInput.Text == "" && len(m.pinnedFilters) == 0) {
+		return number
+	}
+	pos, ok := m.filteredPosition(line)
+	if !ok {
+		return number
+	}
+	width := len(strconv.Itoa(len(m.filtered)))
+	return number + m.config.Border.Vertical + fmt.Sprintf("%-*d", width, pos+1)
+}
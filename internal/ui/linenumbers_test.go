@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToggleLineNumbers(t *testing.T) {
+	m := testModelWithLines()
+	m.config.LineNumWidth = 3
+	pressKeys(m, "#")
+	if !m.config.ShowLineNums {
+		t.Fatal("expected line numbers to be shown")
+	}
+	if got := m.renderedRows(m.lines[1])[0]; !strings.HasPrefix(got, "  2") {
+		t.Errorf("expected the line number in the gutter, got %q", got)
+	}
+
+	pressKeys(m, "#")
+	if got := m.renderedRows(m.lines[1])[0]; got != "foo bar" {
+		t.Errorf("expected no gutter, got %q", got)
+	}
+}
+
+func TestRelativeLineNumbers(t *testing.T) {
+	m := testModelWithLines()
+	m.config.LineNumWidth = 3
+	m.actionToggleRelativeNumbers()
+	m.cursor = 1
+
+	want := []string{"  1", "  2", "  1", "  2"}
+	for i, line := range m.lines {
+		if got := m.renderedRows(line)[0][:3]; got != want[i] {
+			t.Errorf("line %d: expected gutter %q, got %q", line.Number, want[i], got)
+		}
+	}
+
+	// Distances count the lines the filter kept
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	m.cursor = 0
+	if got := m.lineNumber(m.lines[2]); got != 1 {
+		t.Errorf("expected the next matching line to be 1 away, got %d", got)
+	}
+}
+
+func TestDualLineNumbers(t *testing.T) {
+	m := testModelWithLines()
+	m.config.ShowLineNums = true
+	m.config.LineNumWidth = 2
+	m.config.DualLineNums = true
+	if got := m.lineNumbers(m.lines[2]); got != " 3" {
+		t.Errorf("expected only the line number without a filter, got %q", got)
+	}
+
+	m.filterInput.Text = "foo"
+	m.updateFiltered()
+	if got := m.lineNumbers(m.lines[2]); got != " 3│2" {
+		t.Errorf("expected the line number and the position among matches, got %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// logLevel is the severity a line logs at, from most to least severe
+type logLevel int
+
+const (
+	levelNone logLevel = iota
+	levelError
+	levelWarn
+	levelInfo
+	levelDebug
+)
+
+// logLevelPattern finds a line's level: a syslog <PRI> prefix, an uppercase
+// level word, or a lowercase one in brackets or after level= (logfmt).
+var logLevelPattern = regexp.MustCompile(
+	`^<(\d{1,3})>` +
+		`|\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERR|ERROR|CRIT|CRITICAL|ALERT|EMERG|FATAL|PANIC)\b` +
+		`|(?:\[|level=)(?i:(trace|debug|info|notice|warn|warning|err|error|crit|critical|alert|emerg|fatal|panic))\b`)
+
+// logLevels maps the level words to their level
+var logLevels = map[string]logLevel{
+	"trace": levelDebug, "debug": levelDebug,
+	"info": levelInfo, "notice": levelInfo,
+	"warn": levelWarn, "warning": levelWarn,
+	"err": levelError, "error": levelError, "crit": levelError, "critical": levelError,
+	"alert": levelError, "emerg": levelError, "fatal": levelError, "panic": levelError,
+}
+
+// detectLogLevel returns the level of the first level token in s.
+func detectLogLevel(s string) logLevel {
+	match := logLevelPattern.FindStringSubmatch(s)
+	switch {
+	case match == nil:
+		return levelNone
+	case match[1] != "":
+		// The severity is the low 3 bits of a syslog priority
+		pri, _ := strconv.Atoi(match[1])
+		switch severity := pri % 8; {
+		case severity <= 3:
+			return levelError
+		case severity == 4:
+			return levelWarn
+		case severity <= 6:
+			return levelInfo
+		}
+		return levelDebug
+	case match[2] != "":
+		return logLevels[strings.ToLower(match[2])]
+	}
+	return logLevels[strings.ToLower(match[3])]
+}
+
+// levelColored colors content by its line's log level, unless NoLogLevels is
+// set or the command colored the line itself.
+func (m model) levelColored(line runner.Line, content string) string {
+	if m.config.NoLogLevels || ansiEscPattern.MatchString(line.Content) {
+		return content
+	}
+	var color lipgloss.Color
+	switch detectLogLevel(line.Content) {
+	case levelError:
+		color = m.config.Theme.Error
+	case levelWarn:
+		color = m.config.Theme.Warning
+	case levelInfo:
+		color = m.config.Theme.Accent
+	case levelDebug:
+		color = m.config.Theme.Muted
+	default:
+		return content
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(content)
+}
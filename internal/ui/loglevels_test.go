@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want logLevel
+	}{
+		{"2024-01-02 12:00:00 ERROR connection refused", levelError},
+		{"FATAL: out of memory", levelError},
+		{"W0102 WARN disk almost full", levelWarn},
+		{"[warning] deprecated option", levelWarn},
+		{"time=12:00 level=info msg=started", levelInfo},
+		{"INFO server listening", levelInfo},
+		{"DEBUG cache miss", levelDebug},
+		{"<11>su: authentication failure", levelError},
+		{"<12>kernel: low memory", levelWarn},
+		{"<14>cron: job started", levelInfo},
+		{"<15>app: tick", levelDebug},
+		{"no errors found", levelNone},
+		{"INFORMATION", levelNone},
+	}
+	for _, tt := range tests {
+		if got := detectLogLevel(tt.line); got != tt.want {
+			t.Errorf("detectLogLevel(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLevelColored(t *testing.T) {
+	m := testModelWithLines()
+	line := runner.Line{Number: 1, Content: "ERROR boom"}
+	if got := m.levelColored(line, line.Content); stripANSI(got) != line.Content {
+		t.Errorf("expected the error line's text to be kept, got %q", got)
+	}
+
+	colored := runner.Line{Number: 2, Content: "\x1b[33mERROR\x1b[0m boom"}
+	if got := m.levelColored(colored, colored.Content); got != colored.Content {
+		t.Errorf("expected the command's own colors to be kept, got %q", got)
+	}
+
+	m.config.NoLogLevels = true
+	if got := m.levelColored(line, line.Content); got != line.Content {
+		t.Errorf("expected no coloring with NoLogLevels, got %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// markMarker flags lines marked for multi-yank in the gutter
+const markMarker = "●"
+
+// isMarked reports whether the line with the given number is marked.
+func (m model) isMarked(number int) bool {
+	return m.marked[number]
+}
+
+// actionToggleMark marks or unmarks the selected line, then moves the cursor
+// by dir so marking a run of lines is a matter of repeating the key.
+func (m *model) actionToggleMark(dir int) (tea.Model, tea.Cmd) {
+	idx := m.selectedIndex()
+	if idx < 0 || idx >= len(m.lines) {
+		return m, nil
+	}
+	number := m.lines[idx].Number
+	if m.marked[number] {
+		delete(m.marked, number)
+	} else {
+		if m.marked == nil {
+			m.marked = make(map[int]bool)
+		}
+		m.marked[number] = true
+	}
+	m.userScrolled = true
+	m.moveCursor(dir)
+	return m, nil
+}
+
+// clearMarks unmarks every line.
+func (m *model) clearMarks() {
+	m.marked = nil
+}
+
+// markedLines returns the content of all marked lines in output order.
+func (m model) markedLines(plain bool) []string {
+	var lines []string
+	for _, line := range m.markedOutput() {
+		content := m.yanked(line.Content)
+		if plain {
+			content = stripANSI(content)
+		}
+		lines = append(lines, content)
+	}
+	return lines
+}
+
+// markedOutput returns all marked lines in output order.
+func (m model) markedOutput() []runner.Line {
+	var lines []runner.Line
+	for _, line := range m.lines {
+		if m.marked[line.Number] {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// copyMarked copies all marked lines to the clipboard.
+func (m *model) copyMarked(plain bool) (tea.Model, tea.Cmd) {
+	lines := m.markedOutput()
+	if len(lines) == 0 {
+		return m, nil
+	}
+	note := ""
+	if plain {
+		note = i18n.T("status.plain")
+	}
+	return m.copyLines(lines, plain, note)
+}
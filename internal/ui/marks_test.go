@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTabMarksLines(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.cursor != 2 {
+		t.Errorf("expected Tab to move the cursor down, got %d", m.cursor)
+	}
+	if !m.isMarked(1) || !m.isMarked(2) || m.isMarked(3) {
+		t.Errorf("expected lines 1 and 2 to be marked, got %v", m.marked)
+	}
+
+	// Shift-Tab toggles the line under the cursor and moves up
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.cursor != 0 {
+		t.Errorf("expected Shift-Tab to move the cursor up, got %d", m.cursor)
+	}
+	if !m.isMarked(1) || m.isMarked(2) || !m.isMarked(3) {
+		t.Errorf("expected lines 1 and 3 to be marked, got %v", m.marked)
+	}
+}
+
+func TestMarkedContent(t *testing.T) {
+	m := testModelWithLines()
+	m.lines[3].Content = "\x1b[31mbaz\x1b[0m qux"
+	m.marked = map[int]bool{4: true, 2: true}
+
+	lines := m.markedLines(true)
+	if want := []string{"foo bar", "baz qux"}; !slices.Equal(lines, want) {
+		t.Errorf("expected marked lines in output order %q, got %q", want, lines)
+	}
+	if lines := m.markedLines(false); !strings.Contains(lines[1], "\x1b[31m") {
+		t.Errorf("expected colors to be kept, got %q", lines[1])
+	}
+}
+
+func TestMarkGutter(t *testing.T) {
+	m := testModelWithLines()
+	m.marked = map[int]bool{2: true}
+
+	lines := m.renderListLines(4, 40)
+	if !strings.Contains(stripANSI(lines[1]), markMarker+" foo bar") {
+		t.Errorf("expected the mark marker before the marked line, got %q", stripANSI(lines[1]))
+	}
+	if strings.Contains(lines[0], markMarker) {
+		t.Errorf("expected no marker on unmarked lines, got %q", lines[0])
+	}
+}
+
+func TestEscClearsMarks(t *testing.T) {
+	m := testModelWithLines()
+	m.marked = map[int]bool{1: true}
+
+	_, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.marked) != 0 {
+		t.Errorf("expected Esc to clear the marks, got %v", m.marked)
+	}
+	if cmd != nil {
+		t.Error("expected Esc not to quit while lines are marked")
+	}
+}
@@ -2,10 +2,25 @@ package ui
 
 import (
 	"context"
+	"io"
+	"regexp"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/anomaly"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/runlog"
 	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
+)
+
+// ExitCodePolicy defines which exit status watchr itself exits with
+type ExitCodePolicy string
+
+const (
+	ExitCodeNone ExitCodePolicy = ""     // always exit 0
+	ExitCodeLast ExitCodePolicy = "last" // exit with the last run's exit code
+	ExitCodeAny  ExitCodePolicy = "any"  // exit with the first failing run's exit code, if any run failed
 )
 
 // PreviewPosition defines where the preview panel is displayed
@@ -26,66 +41,226 @@ type Config struct {
 	PreviewSizeIsPercent bool
 	PreviewPosition      PreviewPosition
 	ShowLineNums         bool
+	RelativeLineNums     bool // Number lines by their distance from the selected line
+	DualLineNums         bool // While filtering, also number lines by their position among the matches
 	LineNumWidth         int
+	Differences          bool // If true, lines that changed since the previous run are highlighted
 	Prompt               string
 	RefreshInterval      time.Duration
-	RefreshFromStart     bool // If true, refresh timer starts when command starts; if false, when command ends (default)
+	RefreshFromStart     bool          // If true, refresh timer starts when command starts; if false, when command ends (default)
+	Precise              bool          // If true, runs are aligned to wall-clock multiples of RefreshInterval
+	AutoPreview          bool          // If true, the preview opens while the selected line is truncated
+	PreviewOpen          bool          // If true, the preview starts open
+	PreviewCommand       string        // If set, the preview shows this command's output, with {} replaced by the selected line
+	PreviewContext       int           // Lines shown above and below the selected line in context preview (0 = default)
+	FilePreview          bool          // If true, a line naming a local file (path or path:line) previews the file
+	RefreshJitter        time.Duration // Random extra delay (up to this much) added to each refresh
 	Interactive          bool
+	KeepProgressFrames   bool                 // If true, every frame of a "\r"-redrawn line is kept as its own line
+	ControlChars         runner.ControlPolicy // How control characters in output are handled
+	TabWidth             int                  // Columns between tab stops in output (0 = runner.DefaultTabWidth)
+	SSH                  string               // If set, the command runs on this host over ssh
+	Docker               string               // If set, the command runs in this container with docker exec
+	KubectlPod           string               // If set, the command runs in this pod with kubectl exec
+	RemoteShell          string               // Shell the command runs with on those targets (Shell if empty)
+	PreRun               string               // Hook command run before each run
+	PostRun              string               // Hook command run after each run, with WATCHR_EXIT_CODE set
+	MinInterval          time.Duration        // Minimum time between run starts; extra triggers are coalesced
+	Replay               []runlog.Run         // If set, these recorded runs are browsed instead of running the command
+	ExitCode             ExitCodePolicy       // Which exit status watchr exits with
+	TransitionBell       bool                 // If true, ring the bell when the command starts failing or passes again
+	OnTransition         string               // Hook command run when the command starts failing or passes again
+	WebhookURL           string               // If set, run summaries are POSTed here as JSON
+	WebhookOnChange      bool                 // Post to WebhookURL whenever the output changes
+	WebhookMatch         filter.Matcher       // Post to WebhookURL when new lines match (nil = disabled)
+	Record               string               // If set, every run is appended to this session file
+	LogOutput            string               // If set, every run's output is saved to a file in this directory
+	AnomalyAlert         bool                 // If true, ring the bell when a run's line count is far off the recent average
+	Notes                map[string]string    // Initial line notes, keyed by content hash
+	Filter               string               // Initial filter text
+	FilterKind           filter.Kind          // Initial filter matching algorithm
+	FilterMode           filter.Kind          // Matching algorithm the filter resets to (default substring)
+	PinnedFilters        []state.Filter       // Initial filter stack
+	FilterCase           filter.Case          // How filters and search match letter case (default ignore)
+	Scrollbar            bool                 // Show a scrollbar beside the list when output overflows
+	ScrollOff            int                  // Lines kept visible around the cursor; 0 scrolls only at the edges, negative centers it
+	JournalPath          string               // If set, session state is periodically saved here for --resume
+	Baseline             string               // Output hash of a resumed session's last run, compared with the first run
+	HeaderLines          int                  // Leading output lines pinned above the list and left out of filtering
+	LayoutPath           string               // If set, table mode's column layout is saved here when the column chooser closes, keyed by Profile
+	Profile              string               // Name the column layout is saved under: the preset, or else the command
+	Columns              []state.Column       // Initial table mode column layout
+	YankFormat           YankFormat           // How yanked lines are formatted
+	TimeFormat           TimeFormat           // How timestamps are shown (zero value = each place's default)
+	Theme                Theme                // Colors the UI is drawn with (zero value = the default theme)
+	Border               Border               // Characters boxes are drawn with (zero value = rounded)
+	Compact              bool                 // Leave out the box around the view to fit more output
+	HeaderTemplate       string               // Header line text with {command}, {cwd}, ... placeholders (empty = built-in)
+	HideTitle            bool                 // Start with the title line (the command) hidden
+	HideHints            bool                 // Leave the "h for help" hint out of the prompt line
+	StatusBar            StatusBar            // Modules shown in the prompt line (zero value = DefaultStatusBar)
+	HighlightNew         time.Duration        // How long lines new since the previous run are marked (0 = disabled)
+	Marks                []string             // Content hashes of lines marked in a resumed session
+	StatePath            string               // If set, the filter, preview and selected line are saved here on quit, keyed by the command
+	Anchor               string               // Plain content of the line to select once the first run finishes
+	NoInitialRun         bool                 // If true, the command is not run until the first manual reload or refresh tick
+	Mouse                bool                 // If true, mouse events scroll, select and resize the preview
+	Wrap                 bool                 // If true, list lines start soft-wrapped
+	Ellipsis             string               // Ends list lines cut off at the edge (default …)
+	WrapMarker           string               // Starts soft-wrapped continuation rows (default none)
+	Filler               string               // Fills the list rows past the end of the output (default blank)
+	Append               bool                 // If true, each run's output is appended after the previous runs' instead of replacing it
+	AppendRuns           int                  // Runs kept in Append mode, the oldest dropped first (0 = all)
+	MaxRuns              int                  // Stop auto-refreshing after this many runs (0 = unlimited)
+	RunFor               time.Duration        // Stop auto-refreshing after this long (0 = unlimited)
+	ExitOnLimit          bool                 // Quit instead of freezing when MaxRuns or RunFor is reached
+	Bindings             map[string]Binding   // Commands bound to keys, which take precedence over built-in keys
+	Keymap               Keymap               // Translates keys to the default keys they act as (nil means vim)
+	PrintSelection       bool                 // If true, Enter quits and prints the selected (or marked) lines to stdout
+	Delimiter            *regexp.Regexp       // Splits lines into fields for WithNth (nil splits on whitespace)
+	WithNth              []FieldRange         // Fields of each line that are displayed and matched (nil = the whole line)
+	YankDisplayed        bool                 // If true, yanking and PrintSelection emit the displayed fields instead of whole lines
+	Table                bool                 // If true, start in table mode
+	DimAge               []time.Duration      // Ages past which lines are dimmed a step further, ascending
+	NoLogLevels          bool                 // Don't color lines by the log level they mention (ERROR, WARN, ...)
 }
 
 // model represents the application state
 type model struct {
-	config            Config
-	lines             []runner.Line
-	filtered          []int     // indices into lines that match filter
-	cursor            int       // cursor position in filtered list
-	offset            int       // scroll offset for visible window
-	filterInput       textInput // filter text and cursor
-	filterMode        bool
-	filterRegex       bool  // true when filter is in regex mode
-	filterRegexErr    error // non-nil when regex pattern is invalid
-	showPreview       bool
-	previewOffset     int  // scroll offset for preview pane
-	showHelp          bool // help overlay visible
-	width             int
-	height            int
-	runner            *runner.Runner
-	ctx               context.Context
-	cancel            context.CancelFunc
-	loading           bool
-	streaming         bool                    // true while command is running (streaming output)
-	streamResult      *runner.StreamingResult // current streaming result
-	lastLineCount     int                     // track line count for updates
-	userScrolled      bool                    // true if user manually scrolled during streaming
-	refreshGeneration int                     // incremented on manual refresh to reset timer
-	refreshStartTime  time.Time               // when the refresh timer was started
-	spinnerFrame      int                     // current spinner animation frame
-	errorMsg          string
-	statusMsg         string // temporary status message (e.g., "Yanked!")
-	exitCode          int    // last command exit code
+	config               Config
+	lines                []runner.Line
+	filtered             []int     // indices into lines that match filter
+	cursor               int       // cursor position in filtered list
+	offset               int       // scroll offset for visible window
+	filterInput          textInput // filter text and cursor
+	filterMode           bool
+	filterRegex          bool           // true when filter is in regex mode
+	filterGlob           bool           // true when filter is in glob mode
+	filterFuzzy          bool           // true when filter is in fuzzy mode
+	filterCase           filter.Case    // how filters and search match letter case
+	pinnedFilters        []pinnedFilter // filters pinned to the stack, ANDed with the typed one
+	searchMode           bool           // whether the search prompt is open
+	searchInput          textInput      // search text being typed
+	searchQuery          string         // active search, for n / N
+	searchOrigin         int            // cursor position when the search prompt opened
+	filterRegexErr       error          // non-nil when regex pattern is invalid
+	showPreview          bool
+	notes                map[string]string        // line notes keyed by noteKey of the line content
+	marked               map[int]bool             // line numbers marked for multi-yank
+	dedup                dedupMode                // how duplicate lines are collapsed
+	dupCounts            map[int]int              // line number -> lines it stands for when deduplicating
+	noteMode             bool                     // whether the note for the selected line is being edited
+	noteInput            textInput                // note text and cursor
+	pipeMode             bool                     // whether the pipe command prompt is open
+	pipeInput            textInput                // pipe command being typed
+	pipeAll              bool                     // pipe the whole filtered output rather than the selection
+	pipeCommand          string                   // last command the selection was piped to, shown in the preview
+	pipeOutput           string                   // output of pipeCommand
+	pipeErr              error                    // non-nil when pipeCommand failed
+	selection            []string                 // lines accepted with Enter, printed on exit with PrintSelection
+	registers            map[string]string        // named registers ("a-"z) holding earlier yanks
+	register             string                   // register the next yank goes into, after "x
+	registerPending      bool                     // " was pressed and a register name is expected
+	yankedInto           string                   // register the last key's yank went into, for its status
+	yankHistory          []string                 // recent yanks, newest first
+	showRegisters        bool                     // the register viewer is open
+	autoPreviewOpened    bool                     // preview was opened by AutoPreview rather than the user
+	autoPreviewDismissed int                      // line index the user closed an auto-opened preview on
+	previewOffset        int                      // scroll offset for preview pane
+	previewCache         map[string]cachedPreview // PreviewCommand output keyed by plain line content
+	previewPending       string                   // line whose preview command is debouncing or running
+	previewContext       bool                     // preview shows the unfiltered output around the selected line
+	previewTruncate      bool                     // long preview lines are truncated instead of wrapped
+	previewSearchMode    bool                     // whether the preview search prompt is open
+	previewSearchInput   textInput                // preview search text being typed
+	previewSearchQuery   string                   // active preview search, for n / N in a focused preview
+	previewSearchOrigin  int                      // preview offset when the preview search prompt opened
+	anchor               string                   // plain content of the selected line, followed across refreshes
+	pendingAnchor        string                   // remembered selected line, restored when the first run finishes
+	pendingCount         int                      // vim-style count typed before a motion, 0 when none
+	previewFocused       bool                     // preview was clicked; j/k scroll it instead of the list
+	wrapLines            bool                     // long list lines are soft-wrapped instead of truncated
+	resizingPreview      bool                     // the preview border is being dragged
+	showHelp             bool                     // help overlay visible
+	width                int
+	height               int
+	out                  io.Writer // where the UI is drawn, for the bell (nil = stdout)
+	runner               *runner.Runner
+	ctx                  context.Context
+	cancel               context.CancelFunc
+	loading              bool
+	idle                 bool                    // true until the first run when the initial run is skipped
+	streaming            bool                    // true while command is running (streaming output)
+	streamResult         *runner.StreamingResult // current streaming result
+	lastLineCount        int                     // track line count for updates
+	userScrolled         bool                    // true if user manually scrolled during streaming
+	refreshGeneration    int                     // incremented on every run so stale ticks and results are ignored
+	refreshStartTime     time.Time               // when the refresh timer was started
+	startTime            time.Time               // when the UI started, for the RunFor limit
+	runCount             int                     // number of runs started so far
+	appendBase           []runner.Line           // output of the previous runs and the current run's separator, in Append mode
+	appendRuns           []int                   // line number of each kept run's separator, in Append mode
+	runStartTime         time.Time               // when the current or last run started
+	lastRunEnd           time.Time               // when the last run finished
+	lastRunDuration      time.Duration           // how long the last finished run took
+	replayIndex          int                     // index of the recorded run being shown in replay mode
+	runDeferred          bool                    // a run is waiting for MinInterval to pass
+	refreshStopped       bool                    // true once MaxRuns or RunFor has been reached
+	spinnerFrame         int                     // current spinner animation frame
+	prevRunLines         []string                // ANSI-stripped output of the last run, for Differences and HighlightNew
+	prevRunPrimed        bool                    // whether prevRunLines holds a finished run
+	changedLines         map[int]bool            // line numbers of lines that changed since the previous run
+	errorMsg             string
+	statusMsg            string         // temporary status message (e.g., "Yanked!")
+	finishedRuns         int            // number of runs that ran to completion
+	lastExitCode         int            // exit code of the last finished run
+	failedExitCode       int            // exit code of the first failed run, or 0 if none failed
+	exitCode             int            // last command exit code
+	journaled            *state.Session // session state last written to the journal
+	baseline             string         // content hash of the last finished run's output, for the journal
+	resumeBaseline       string         // baseline of the resumed session, compared with the first run
+	pendingMarks         []string       // content hashes of resumed marks, applied once the first run finishes
+	binary               bool           // output was binary and is shown as a hexdump
+	outputDoc            []string       // highlighted rows of the output when it's one JSON or YAML document
+	lineCounts           anomaly.Stats  // line counts of recent runs, for anomaly detection
+	anomaly              string         // description of the last run's line count anomaly, if any
+	webhookLines         []string       // ANSI-stripped output of the last run, for webhook diffs
+	webhookPrimed        bool           // whether webhookLines holds a finished run
+	newLinesAt           time.Time      // when the lines changed in the last run were marked, for fading them
 
 	cmdPaletteMode     bool      // whether command palette is open
 	cmdPaletteInput    textInput // palette filter text and cursor
 	cmdPaletteSelected int       // selected item index in filtered list
 
+	pinHeader     bool           // the first HeaderLines lines are pinned above the list
+	hideTitle     bool           // the title line with the command is hidden
+	relativeNums  bool           // the gutter shows distances from the selected line
+	tableMode     bool           // lines are split into aligned columns under the last header line
+	tableCol      int            // selected table mode column, as an index into tableShown
+	tableWidths   []int          // width of each table mode column, from the header's column count
+	tableLayout   []state.Column // table mode's column order, hidden columns and widths, by column name
+	tableShown    []int          // columns shown in table mode, in order, from tableLayout
+	tableSizes    map[int]int    // widths set in the column chooser, by column
+	columnsMode   bool           // the column chooser is open
+	columnsCursor int            // column selected in the column chooser
+
 	confirmMode    bool   // whether a confirmation dialog is visible
 	confirmMessage string // message to display in confirmation dialog
 	confirmAction  func(m *model) (tea.Model, tea.Cmd)
 }
 
 // messages
-type resultMsg struct {
-	lines    []runner.Line
-	exitCode int
-}
 type errMsg struct{ err error }
 type tickMsg struct {
 	generation int
 }
 type clearStatusMsg struct{}
 type spinnerTickMsg time.Time
-type streamTickMsg time.Time   // periodic check for streaming updates
+type streamTickMsg struct { // periodic check for streaming updates
+	generation int
+}
 type startStreamMsg struct{}   // trigger to start streaming
+type deferredRunMsg struct{}   // a run deferred by MinInterval is due
 type countdownTickMsg struct { // periodic update for refresh countdown display
 	generation int
 }
@@ -0,0 +1,213 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// mouseWheelStep is how many lines one wheel notch scrolls
+const mouseWheelStep = 3
+
+// mouseRegion identifies the part of the main view under the pointer
+type mouseRegion int
+
+const (
+	regionNone mouseRegion = iota
+	regionList
+	regionPreview
+	regionDivider // the border between the list and the preview
+)
+
+// handleMouse scrolls with the wheel, selects clicked lines, focuses the
+// preview when it's clicked and resizes the preview when its border is
+// dragged.
+func (m *model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp || m.confirmMode || m.cmdPaletteMode || m.noteMode || m.pipeMode {
+		return m, nil
+	}
+	if m.resizingPreview {
+		switch msg.Action {
+		case tea.MouseActionMotion:
+			m.resizePreviewTo(msg.X, msg.Y)
+		case tea.MouseActionRelease:
+			m.resizingPreview = false
+		}
+		return m, nil
+	}
+
+	region, row := m.regionAt(msg.X, msg.Y)
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		delta := mouseWheelStep
+		if msg.Button == tea.MouseButtonWheelUp {
+			delta = -delta
+		}
+		if region == regionPreview {
+			m.scrollPreview(delta)
+		} else {
+			m.userScrolled = true
+			m.moveCursor(delta)
+		}
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		switch region {
+		case regionList:
+			m.previewFocused = false
+			if idx, ok := m.lineAtRow(row); ok {
+				m.userScrolled = true
+				m.cursor = idx
+				m.previewOffset = 0
+			}
+		case regionPreview:
+			m.previewFocused = true
+		case regionDivider:
+			m.resizingPreview = true
+		}
+	}
+	return m, nil
+}
+
+// regionAt returns the region of the main view at screen cell (x, y) and, for
+// the list, the row within it.
+func (m model) regionAt(x, y int) (mouseRegion, int) {
+	region, row := m.paneAt(x, y)
+	if region == regionList {
+		// Pinned header lines sit above the list's first row
+		row -= m.headerLines()
+		if row < 0 {
+			return regionNone, 0
+		}
+	}
+	return region, row
+}
+
+// paneAt returns the region of the content area at x, y, and the row within
+// it.
+func (m model) paneAt(x, y int) (mouseRegion, int) {
+	side := m.frameSide()
+	if y < m.contentTop() || y >= m.height-m.bottomRows() || x < side || x >= m.width-side {
+		return regionNone, 0
+	}
+	row, col := y-m.contentTop(), x-side
+	if !m.showPreview {
+		return regionList, row
+	}
+
+	size := m.previewSize()
+	switch m.config.PreviewPosition {
+	case PreviewTop:
+		switch {
+		case row < size:
+			return regionPreview, 0
+		case row == size:
+			return regionDivider, 0
+		}
+		return regionList, row - size - 1
+	case PreviewBottom:
+		listHeight := m.visibleLines() + m.headerLines()
+		switch {
+		case row < listHeight:
+			return regionList, row
+		case row == listHeight:
+			return regionDivider, 0
+		}
+		return regionPreview, 0
+	case PreviewLeft:
+		switch {
+		case col < size:
+			return regionPreview, 0
+		case col == size:
+			return regionDivider, 0
+		}
+		return regionList, row
+	case PreviewRight:
+		split := m.innerWidth() - size - 1
+		switch {
+		case col < split:
+			return regionList, row
+		case col == split:
+			return regionDivider, 0
+		}
+		return regionPreview, 0
+	}
+	return regionNone, 0
+}
+
+// maxPreviewCells returns the largest preview size in rows or columns that
+// still leaves one row or column for the list.
+func (m model) maxPreviewCells() int {
+	if m.config.PreviewPosition == PreviewTop || m.config.PreviewPosition == PreviewBottom {
+		return m.height - m.contentTop() - m.bottomRows() - 2 // the separator and one list row
+	}
+	return m.innerWidth() - 3 // the divider, the list margin and one column
+}
+
+// resizePreviewTo moves the preview border to screen cell (x, y), keeping at
+// least one row or column for both the list and the preview.
+func (m *model) resizePreviewTo(x, y int) {
+	var size, total int
+	switch m.config.PreviewPosition {
+	case PreviewTop:
+		size, total = y-m.contentTop(), m.height
+	case PreviewBottom:
+		size, total = m.height-m.bottomRows()-1-y, m.height
+	case PreviewLeft:
+		size, total = x-m.frameSide(), m.width
+	case PreviewRight:
+		size, total = m.width-m.frameSide()-1-x, m.width
+	}
+	size = max(min(size, m.maxPreviewCells()), 1)
+
+	if m.config.PreviewSizeIsPercent && total > 0 {
+		m.config.PreviewSize = max((size*100+total/2)/total, 1)
+	} else {
+		m.config.PreviewSize = size
+	}
+	m.adjustOffset()
+	m.clampPreviewOffset()
+}
+
+// handlePreviewFocus lets j/k scroll a focused preview, w toggle its wrap and
+// / search it. Esc clears the preview search, then returns focus to the list;
+// any other key returns focus too, and is then handled as usual. Reports
+// whether the key was consumed.
+func (m *model) handlePreviewFocus(msg tea.KeyMsg) bool {
+	if !m.showPreview {
+		m.previewFocused = false
+		return false
+	}
+	switch msg.String() {
+	case "j", "down", "ctrl+e":
+		m.scrollPreview(1)
+		return true
+	case "k", "up", "ctrl+y":
+		m.scrollPreview(-1)
+		return true
+	case "ctrl+d", "pgdown":
+		m.scrollPreview(max(m.previewHeight()/2, 1))
+		return true
+	case "ctrl+u", "pgup":
+		m.scrollPreview(-max(m.previewHeight()/2, 1))
+		return true
+	case "w":
+		m.actionTogglePreviewWrap()
+		return true
+	case "/":
+		m.actionEnterPreviewSearch()
+		return true
+	case "n":
+		m.actionPreviewSearchNext(1)
+		return true
+	case "N":
+		m.actionPreviewSearchNext(-1)
+		return true
+	case "esc":
+		if m.previewSearchQuery != "" {
+			m.previewSearchQuery = ""
+			return true
+		}
+		m.previewFocused = false
+		return true
+	}
+	m.previewFocused = false
+	return false
+}
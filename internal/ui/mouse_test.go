@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dividerCell finds the list/preview border in the rendered view
+func dividerCell(t *testing.T, m *model) (x, y int) {
+	t.Helper()
+	rows := strings.Split(stripANSI(m.View()), "\n")
+	switch m.config.PreviewPosition {
+	case PreviewTop, PreviewBottom:
+		for i := m.contentTop(); i < len(rows); i++ {
+			// Compact mode draws the separator without the junction
+			if strings.HasPrefix(rows[i], m.config.Border.LeftT) || m.config.Compact && strings.HasPrefix(rows[i], m.config.Border.Horizontal) {
+				return 1, i
+			}
+		}
+	default:
+		row := []rune(rows[m.contentTop()])
+		for i := 1; i < len(row)-1; i++ {
+			if string(row[i]) == m.config.Border.Vertical {
+				return i, m.contentTop()
+			}
+		}
+	}
+	t.Fatal("divider not found")
+	return 0, 0
+}
+
+func press(m *model, x, y int) {
+	m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+}
+
+func TestMouseRegionsMatchView(t *testing.T) {
+	for _, pos := range []PreviewPosition{PreviewTop, PreviewBottom, PreviewLeft, PreviewRight} {
+		t.Run(string(pos), func(t *testing.T) { testMouseRegions(t, pos, false) })
+		t.Run(string(pos)+"/compact", func(t *testing.T) { testMouseRegions(t, pos, true) })
+	}
+}
+
+func testMouseRegions(t *testing.T, pos PreviewPosition, compact bool) {
+	m := testModelWithLines()
+	m.config.Compact = compact
+	m.config.PreviewPosition = pos
+	m.config.PreviewSize = 10
+	m.showPreview = true
+
+	x, y := dividerCell(t, m)
+	if region, _ := m.regionAt(x, y); region != regionDivider {
+		t.Fatalf("expected divider at (%d, %d), got %v", x, y, region)
+	}
+
+	// Drag the border and check the view follows the pointer
+	press(m, x, y)
+	nx, ny := x, y
+	switch pos {
+	case PreviewTop, PreviewBottom:
+		ny = y - 2
+	default:
+		nx = x + 4
+	}
+	m.Update(tea.MouseMsg{X: nx, Y: ny, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	m.Update(tea.MouseMsg{X: nx, Y: ny, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	if m.resizingPreview {
+		t.Error("expected resize to end on release")
+	}
+	if gx, gy := dividerCell(t, m); gx != nx || gy != ny {
+		t.Errorf("expected divider at (%d, %d) after drag, got (%d, %d)", nx, ny, gx, gy)
+	}
+}
+
+func TestMouseClickSelectsLine(t *testing.T) {
+	m := testModelWithLines()
+	rows := strings.Split(stripANSI(m.View()), "\n")
+	y := -1
+	for i, row := range rows {
+		if strings.Contains(row, "hello foo") {
+			y = i
+		}
+	}
+	press(m, 5, y)
+	if m.cursor != 2 {
+		t.Errorf("expected cursor on the clicked line (2), got %d", m.cursor)
+	}
+
+	// Clicks below the last line don't move the cursor
+	press(m, 5, y+5)
+	if m.cursor != 2 {
+		t.Errorf("expected cursor to stay at 2, got %d", m.cursor)
+	}
+}
+
+func TestMouseWheelScrollsList(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(tea.MouseMsg{X: 5, Y: 5, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if m.cursor != 3 {
+		t.Errorf("expected wheel down to move the cursor to 3, got %d", m.cursor)
+	}
+	if !m.userScrolled {
+		t.Error("expected wheel scrolling to stop auto-scroll")
+	}
+	m.Update(tea.MouseMsg{X: 5, Y: 5, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if m.cursor != 0 {
+		t.Errorf("expected wheel up to move the cursor to 0, got %d", m.cursor)
+	}
+}
+
+func TestMousePreviewFocus(t *testing.T) {
+	m := testModelWithLines()
+	m.lines[0].Content = strings.Repeat("long preview content ", 200)
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 5
+	m.showPreview = true
+
+	_, y := dividerCell(t, m)
+	press(m, 5, y+1)
+	if !m.previewFocused {
+		t.Fatal("expected clicking the preview to focus it")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.previewOffset != 1 || m.cursor != 0 {
+		t.Errorf("expected j to scroll the focused preview, got offset %d cursor %d", m.previewOffset, m.cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.previewFocused {
+		t.Error("expected esc to return focus to the list")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.cursor != 1 {
+		t.Errorf("expected j to move the cursor after unfocusing, got %d", m.cursor)
+	}
+}
+
+func TestMouseIgnoredInOverlays(t *testing.T) {
+	m := testModelWithLines()
+	m.showHelp = true
+	m.Update(tea.MouseMsg{X: 5, Y: 5, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if m.cursor != 0 {
+		t.Errorf("expected mouse to be ignored while help is open, got cursor %d", m.cursor)
+	}
+}
+
+func TestPreviewFocusPageScroll(t *testing.T) {
+	m := testModelWithLines()
+	m.lines[0].Content = strings.Repeat("word ", 200)
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 6
+	m.showPreview = true
+	m.previewFocused = true
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if m.previewOffset != 3 {
+		t.Errorf("expected Ctrl+d to scroll the preview half a page, got %d", m.previewOffset)
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlY})
+	if m.previewOffset != 2 {
+		t.Errorf("expected Ctrl+y to scroll the preview up a line, got %d", m.previewOffset)
+	}
+	if m.cursor != 0 {
+		t.Errorf("expected the list cursor not to move, got %d", m.cursor)
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newMarker is shown in the gutter of lines that weren't in the previous run
+const newMarker = "+"
+
+// newLinesFadedMsg redraws the list once the new-line markers of a run fade
+type newLinesFadedMsg struct{}
+
+// markNewLines marks the lines that changed in the finished run, as found by
+// markChanges, for HighlightNew, and fades the markers after it.
+func (m *model) markNewLines() tea.Cmd {
+	if m.config.HighlightNew <= 0 || len(m.changedLines) == 0 {
+		return nil
+	}
+	m.newLinesAt = time.Now()
+	return tea.Tick(m.config.HighlightNew, func(time.Time) tea.Msg {
+		return newLinesFadedMsg{}
+	})
+}
+
+// isNew reports whether line is new since the previous run and its marker
+// hasn't faded yet.
+func (m model) isNew(line int) bool {
+	return m.config.HighlightNew > 0 && m.changedLines[line] && time.Since(m.newLinesAt) < m.config.HighlightNew
+}
+
+// gutterStyle returns the style of line's gutter, which stands out while the
+// line is new.
+func (m model) gutterStyle(line int) lipgloss.Style {
+	if m.isNew(line) {
+		return lipgloss.NewStyle().Foreground(m.config.Theme.Status).Bold(true)
+	}
+	return lipgloss.NewStyle().Foreground(m.config.Theme.LineNumber)
+}
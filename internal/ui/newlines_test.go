@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestMarkNewLines(t *testing.T) {
+	m := testModelWithLines()
+	m.config.HighlightNew = time.Minute
+	m.markChanges()
+	if cmd := m.markNewLines(); cmd != nil {
+		t.Fatal("expected nothing marked on the first run")
+	}
+
+	m.lines = append(m.lines[:2:2], runner.Line{Number: 3, Content: "brand new"}, runner.Line{Number: 4, Content: "hello foo"})
+	m.markChanges()
+	if cmd := m.markNewLines(); cmd == nil {
+		t.Error("expected a command to fade the markers")
+	}
+	if !m.isNew(3) || m.isNew(4) || m.isNew(1) {
+		t.Errorf("expected only line 3 to be new, got %v", m.changedLines)
+	}
+	if got := m.renderedRows(m.lines[2])[0]; !strings.HasPrefix(got, newMarker+" brand new") {
+		t.Errorf("expected the new line marker, got %q", got)
+	}
+	if got := m.highlightChange(m.lines[2], "brand new"); got != "brand new" {
+		t.Errorf("expected no -d highlighting without Differences, got %q", got)
+	}
+
+	m.newLinesAt = time.Now().Add(-2 * time.Minute)
+	if m.isNew(3) {
+		t.Error("expected the marker to fade")
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// noteMarker marks annotated lines in the gutter and preview
+const noteMarker = "✎"
+
+// noteKey identifies a line by a hash of its plain content, so a note stays
+// attached to the same text across refreshes even when line numbers shift.
+func noteKey(content string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(stripANSI(content)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// noteFor returns the note attached to content, if any.
+func (m model) noteFor(content string) string {
+	if len(m.notes) == 0 {
+		return ""
+	}
+	return m.notes[noteKey(content)]
+}
+
+func (m *model) actionEditNote() (tea.Model, tea.Cmd) {
+	idx := m.selectedIndex()
+	if idx < 0 || idx >= len(m.lines) {
+		return m, nil
+	}
+	m.noteMode = true
+	m.noteInput.Text = m.noteFor(m.lines[idx].Content)
+	m.noteInput.Cursor = len(m.noteInput.Text)
+	return m, nil
+}
+
+// saveNote attaches the note being edited to the selected line, or removes
+// the line's note when the input is empty.
+func (m *model) saveNote() (tea.Model, tea.Cmd) {
+	m.noteMode = false
+	idx := m.selectedIndex()
+	if idx < 0 || idx >= len(m.lines) {
+		m.noteInput.clear()
+		return m, nil
+	}
+	key := noteKey(m.lines[idx].Content)
+	if m.noteInput.Text == "" {
+		delete(m.notes, key)
+		m.statusMsg = i18n.T("status.note_removed")
+	} else {
+		if m.notes == nil {
+			m.notes = make(map[string]string)
+		}
+		m.notes[key] = m.noteInput.Text
+		m.statusMsg = i18n.T("status.note_saved")
+	}
+	m.noteInput.clear()
+	return m, m.statusTimeoutCmd()
+}
+
+// actionNextNote moves the cursor to the next annotated line, wrapping around.
+func (m *model) actionNextNote() (tea.Model, tea.Cmd) {
+	n := len(m.filtered)
+	for step := 1; step <= n; step++ {
+		i := (m.cursor + step) % n
+		if idx := m.filtered[i]; idx < len(m.lines) && m.noteFor(m.lines[idx].Content) != "" {
+			m.userScrolled = true
+			m.moveCursor(i - m.cursor)
+			return m, nil
+		}
+	}
+	m.statusMsg = i18n.T("status.no_notes")
+	return m, m.statusTimeoutCmd()
+}
+
+func (m *model) handleNoteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.noteMode = false
+		m.noteInput.clear()
+		return m, nil
+	case tea.KeyEnter:
+		return m.saveNote()
+	default:
+		m.noteInput.handleKey(msg)
+		return m, nil
+	}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func typeText(m *model, s string) {
+	for _, r := range s {
+		m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestNoteKeyIgnoresStyling(t *testing.T) {
+	if noteKey("\x1b[31merror\x1b[0m") != noteKey("error") {
+		t.Error("expected styled and plain content to share a note key")
+	}
+	if noteKey("error") == noteKey("errors") {
+		t.Error("expected different content to have different keys")
+	}
+}
+
+func TestEditNote(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 1
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if !m.noteMode {
+		t.Fatal("expected note mode after pressing a")
+	}
+	typeText(m, "first bad")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.noteMode {
+		t.Error("expected note mode to end on enter")
+	}
+	if got := m.noteFor("foo bar"); got != "first bad" {
+		t.Errorf("expected note 'first bad', got %q", got)
+	}
+
+	// Editing again starts from the existing note; clearing it removes the note
+	m.actionEditNote()
+	if m.noteInput.Text != "first bad" {
+		t.Errorf("expected existing note to be prefilled, got %q", m.noteInput.Text)
+	}
+	m.noteInput.clear()
+	m.saveNote()
+	if len(m.notes) != 0 {
+		t.Errorf("expected note to be removed, got %v", m.notes)
+	}
+}
+
+func TestEditNoteEscCancels(t *testing.T) {
+	m := testModelWithLines()
+	m.actionEditNote()
+	typeText(m, "draft")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.noteMode || len(m.notes) != 0 {
+		t.Error("expected esc to discard the note")
+	}
+}
+
+func TestNoteSurvivesRefresh(t *testing.T) {
+	m := testModelWithLines()
+	m.notes = map[string]string{noteKey("hello foo"): "look here"}
+
+	// The annotated line moves to a different position after a refresh
+	m.lines = []runner.Line{
+		{Number: 1, Content: "new line"},
+		{Number: 2, Content: "hello foo"},
+	}
+	m.updateFiltered()
+
+	m.actionNextNote()
+	if m.cursor != 1 {
+		t.Errorf("expected cursor on the annotated line, got %d", m.cursor)
+	}
+}
+
+func TestNextNoteWraps(t *testing.T) {
+	m := testModelWithLines()
+	m.notes = map[string]string{noteKey("hello world"): "a", noteKey("hello foo"): "b"}
+	m.cursor = 2
+	m.actionNextNote()
+	if m.cursor != 0 {
+		t.Errorf("expected next note to wrap to line 0, got %d", m.cursor)
+	}
+	m.actionNextNote()
+	if m.cursor != 2 {
+		t.Errorf("expected next note at line 2, got %d", m.cursor)
+	}
+}
+
+func TestNextNoteNone(t *testing.T) {
+	m := testModelWithLines()
+	m.actionNextNote()
+	if m.statusMsg != "No annotated lines" {
+		t.Errorf("expected status message, got %q", m.statusMsg)
+	}
+}
+
+func TestNoteMarkerInView(t *testing.T) {
+	m := testModelWithLines()
+	m.config.ShowLineNums = true
+	m.config.LineNumWidth = 3
+	m.notes = map[string]string{noteKey("foo bar"): "suspicious"}
+	m.showPreview = true
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 5
+	m.cursor = 1
+
+	view := m.View()
+	if !strings.Contains(view, "  2"+noteMarker+" foo bar") {
+		t.Errorf("expected note marker in gutter, got %q", view)
+	}
+	if !strings.Contains(view, noteMarker+" suspicious") {
+		t.Errorf("expected note in preview, got %q", view)
+	}
+}
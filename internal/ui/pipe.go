@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// pipeOutputMsg carries the output of a command the selection was piped to
+type pipeOutputMsg struct {
+	command string
+	output  string
+	err     error
+}
+
+// actionEnterPipe opens a prompt for a shell command to pipe the selected
+// (or marked) lines to.
+func (m *model) actionEnterPipe() (tea.Model, tea.Cmd) {
+	m.pipeMode = true
+	m.pipeAll = false
+	m.pipeInput.clear()
+	return m, nil
+}
+
+func (m *model) handlePipeMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.pipeMode = false
+		m.pipeInput.clear()
+		return m, nil
+	case tea.KeyTab:
+		// Switch between the selection and the whole filtered output
+		m.pipeAll = !m.pipeAll
+		return m, nil
+	case tea.KeyEnter:
+		m.pipeMode = false
+		command := strings.TrimSpace(m.pipeInput.Text)
+		m.pipeInput.clear()
+		if command == "" {
+			return m, nil
+		}
+		return m, m.pipeCmd(command, strings.Join(m.pipeLines(), "\n")+"\n")
+	default:
+		m.pipeInput.handleKey(msg)
+		return m, nil
+	}
+}
+
+// pipeLines returns the plain lines piped to the command: the whole filtered
+// output, or the marked lines, or the selected one.
+func (m model) pipeLines() []string {
+	if !m.pipeAll && len(m.marked) > 0 {
+		return m.markedLines(true)
+	}
+	if !m.pipeAll {
+		if line, ok := m.selectedPlain(); ok {
+			return []string{line}
+		}
+		return nil
+	}
+	lines := make([]string, 0, len(m.filtered))
+	for _, idx := range m.filtered {
+		if idx < len(m.lines) {
+			lines = append(lines, stripANSI(m.lines[idx].Content))
+		}
+	}
+	return lines
+}
+
+// pipeCmd runs command with input on its stdin, reporting its output.
+func (m *model) pipeCmd(command, input string) tea.Cmd {
+	m.statusMsg = i18n.T("status.running", command)
+	r := m.runner
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+		defer cancel()
+		out, err := r.RunPipe(ctx, command, input)
+		return pipeOutputMsg{command: command, output: out, err: err}
+	}
+}
+
+// showPipeOutput opens the preview on a piped command's output, which it
+// shows until Esc or the preview is closed.
+func (m *model) showPipeOutput(msg pipeOutputMsg) (tea.Model, tea.Cmd) {
+	m.pipeCommand = msg.command
+	m.pipeOutput = msg.output
+	m.pipeErr = msg.err
+	m.previewOffset = 0
+	m.statusMsg = ""
+	if !m.showPreview {
+		m.showPreview = true
+		m.autoPreviewOpened = false
+		m.adjustOffset()
+	}
+	return m, nil
+}
+
+// clearPipeOutput returns the preview to the selected line, reporting whether
+// it was showing piped output.
+func (m *model) clearPipeOutput() bool {
+	if m.pipeCommand == "" {
+		return false
+	}
+	m.pipeCommand, m.pipeOutput, m.pipeErr = "", "", nil
+	m.previewOffset = 0
+	return true
+}
+
+// pipePreview renders the last piped command's output under a header naming
+// the command, and its error if it failed.
+func (m model) pipePreview() string {
+	headerStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	header := "── | " + m.pipeCommand
+	if m.pipeErr != nil {
+		errStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Error)
+		header = headerStyle.Render(header) + " " + errStyle.Render("("+m.pipeErr.Error()+")")
+	} else {
+		header = headerStyle.Render(header + i18n.T("preview.esc_to_close"))
+	}
+	return header + "\n" + m.pipeOutput
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPipeLines(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 1
+	if got := m.pipeLines(); !slices.Equal(got, []string{"foo bar"}) {
+		t.Errorf("expected the selected line, got %q", got)
+	}
+
+	m.marked = map[int]bool{1: true, 4: true}
+	if got := m.pipeLines(); !slices.Equal(got, []string{"hello world", "baz qux"}) {
+		t.Errorf("expected the marked lines, got %q", got)
+	}
+
+	m.pipeAll = true
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	if got := m.pipeLines(); !slices.Equal(got, []string{"hello world", "hello foo"}) {
+		t.Errorf("expected the filtered output, got %q", got)
+	}
+}
+
+func TestPipeToCommand(t *testing.T) {
+	m := testModelWithLines()
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 5
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	if !m.pipeMode {
+		t.Fatal("expected | to open the pipe prompt")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	for _, r := range "wc -l" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected the command to run")
+	}
+	m.Update(cmd())
+
+	if !m.showPreview {
+		t.Error("expected the preview to open on the output")
+	}
+	rows := strings.Split(stripANSI(m.previewContent()), "\n")
+	if len(rows) != 2 || rows[0] != "── | wc -l (Esc to close)" || strings.TrimSpace(rows[1]) != "4" {
+		t.Errorf("unexpected preview %q", rows)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.pipeCommand != "" || m.previewContent() != "hello world" {
+		t.Errorf("expected Esc to return the preview to the selected line, got %q", m.previewContent())
+	}
+}
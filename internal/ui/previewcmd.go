@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// previewDebounce is how long the cursor must rest on a line before its
+// preview command runs, so scrolling through the list doesn't spawn a
+// command for every line passed
+const previewDebounce = 150 * time.Millisecond
+
+// previewTimeout bounds how long a single preview command may run
+const previewTimeout = 10 * time.Second
+
+// maxPreviewCache caps how many preview outputs are kept; the cache is
+// emptied when it fills up
+const maxPreviewCache = 256
+
+// cachedPreview is the output of the preview command for one line
+type cachedPreview struct {
+	output string
+	run    int // finishedRuns when the preview was requested
+}
+
+// previewDueMsg fires once the cursor has rested on line for previewDebounce
+type previewDueMsg struct{ line string }
+
+// previewOutputMsg carries the output of the preview command for line
+type previewOutputMsg struct {
+	line   string
+	output string
+	run    int
+}
+
+// selectedPlain returns the selected line without ANSI styling, and whether
+// a line is selected.
+func (m model) selectedPlain() (string, bool) {
+	idx := m.selectedIndex()
+	if idx < 0 || idx >= len(m.lines) {
+		return "", false
+	}
+	return stripANSI(m.lines[idx].Content), true
+}
+
+// syncPreviewCommand schedules the preview command for the selected line
+// when the preview is open and its output isn't cached for the current run.
+// Outputs cached before the latest run are still shown until they're
+// replaced, so refreshing doesn't blank the preview.
+func (m *model) syncPreviewCommand() tea.Cmd {
+	if m.config.PreviewCommand == "" || !m.showPreview || m.previewContext {
+		return nil
+	}
+	line, ok := m.selectedPlain()
+	if !ok || line == m.previewPending {
+		return nil
+	}
+	if cached, ok := m.previewCache[line]; ok && cached.run == m.finishedRuns {
+		return nil
+	}
+	m.previewPending = line
+	return tea.Tick(previewDebounce, func(time.Time) tea.Msg {
+		return previewDueMsg{line: line}
+	})
+}
+
+// runPreviewCmd runs the preview command for line, unless the cursor has
+// moved on to another line while the debounce was pending.
+func (m *model) runPreviewCmd(line string) tea.Cmd {
+	if line != m.previewPending {
+		return nil
+	}
+	r := m.runner
+	command := runner.PreviewCommand(m.config.Shell, m.config.PreviewCommand, line)
+	run := m.finishedRuns
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+		defer cancel()
+		out, err := r.RunPreview(ctx, command)
+		if err != nil && out == "" {
+			out = err.Error()
+		}
+		return previewOutputMsg{line: line, output: out, run: run}
+	}
+}
+
+// storePreview caches a finished preview command's output.
+func (m *model) storePreview(msg previewOutputMsg) {
+	if m.previewCache == nil || len(m.previewCache) >= maxPreviewCache {
+		m.previewCache = make(map[string]cachedPreview)
+	}
+	m.previewCache[msg.line] = cachedPreview{output: msg.output, run: msg.run}
+	if m.previewPending == msg.line {
+		m.previewPending = ""
+	}
+	m.clampPreviewOffset()
+}
+
+// commandPreview returns the preview command's output for line, or a
+// placeholder while it hasn't finished yet.
+func (m model) commandPreview(line string) string {
+	if cached, ok := m.previewCache[line]; ok {
+		return cached.output
+	}
+	return lipgloss.NewStyle().Foreground(m.config.Theme.Muted).Render(i18n.T("preview.running"))
+}
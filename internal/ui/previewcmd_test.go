@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreviewCommandDebounce(t *testing.T) {
+	m := testModelWithLines()
+	m.config.PreviewCommand = "echo preview of {}"
+
+	if cmd := m.syncPreviewCommand(); cmd != nil {
+		t.Fatal("expected no preview command while the preview is closed")
+	}
+
+	m.showPreview = true
+	if cmd := m.syncPreviewCommand(); cmd == nil {
+		t.Fatal("expected the preview command to be scheduled")
+	}
+	if m.previewPending != "hello world" {
+		t.Errorf("expected pending preview for the selected line, got %q", m.previewPending)
+	}
+	if cmd := m.syncPreviewCommand(); cmd != nil {
+		t.Error("expected no second schedule while one is pending")
+	}
+
+	// Moving on before the debounce fires drops the stale request
+	m.moveCursor(1)
+	m.syncPreviewCommand()
+	if cmd := m.runPreviewCmd("hello world"); cmd != nil {
+		t.Error("expected the stale preview to be dropped")
+	}
+
+	cmd := m.runPreviewCmd("foo bar")
+	if cmd == nil {
+		t.Fatal("expected the preview command to run")
+	}
+	msg, ok := cmd().(previewOutputMsg)
+	if !ok {
+		t.Fatalf("expected previewOutputMsg, got %T", msg)
+	}
+	if msg.output != "preview of foo bar" {
+		t.Errorf("unexpected preview output %q", msg.output)
+	}
+	m.Update(msg)
+	if !strings.Contains(m.previewContent(), "preview of foo bar") {
+		t.Errorf("expected preview to show the command output, got %q", m.previewContent())
+	}
+	if m.previewPending != "" {
+		t.Errorf("expected no pending preview, got %q", m.previewPending)
+	}
+	if cmd := m.syncPreviewCommand(); cmd != nil {
+		t.Error("expected the cached preview to be reused")
+	}
+}
+
+func TestPreviewCommandRefresh(t *testing.T) {
+	m := testModelWithLines()
+	m.config.PreviewCommand = "echo {}"
+	m.showPreview = true
+	m.storePreview(previewOutputMsg{line: "hello world", output: "old"})
+
+	// A finished run makes the cached output stale, but it's shown until replaced
+	m.finishedRuns++
+	if cmd := m.syncPreviewCommand(); cmd == nil {
+		t.Error("expected the preview to re-run after a new run")
+	}
+	if !strings.Contains(m.previewContent(), "old") {
+		t.Errorf("expected the stale output meanwhile, got %q", m.previewContent())
+	}
+}
+
+func TestPreviewCommandPlaceholder(t *testing.T) {
+	m := testModelWithLines()
+	m.config.PreviewCommand = "cat {}"
+	m.showPreview = true
+	if !strings.Contains(m.previewContent(), "Running preview") {
+		t.Errorf("expected a placeholder before the output arrives, got %q", m.previewContent())
+	}
+}
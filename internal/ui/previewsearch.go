@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+func (m *model) actionTogglePreviewWrap() (tea.Model, tea.Cmd) {
+	m.previewTruncate = !m.previewTruncate
+	m.clampPreviewOffset()
+	if m.previewTruncate {
+		m.statusMsg = i18n.T("status.preview_wrap_off")
+	} else {
+		m.statusMsg = i18n.T("status.preview_wrap_on")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+// previewRows splits preview content into the rows shown in a preview pane
+// of the given width, wrapping long lines or truncating them when wrap is
+// off. Matches of the preview search are highlighted.
+func (m model) previewRows(content string, width int) []string {
+	var rows []string
+	if m.previewTruncate {
+		for line := range strings.SplitSeq(content, "\n") {
+			rows = append(rows, truncateToWidth(line, width))
+		}
+	} else {
+		rows = wrapPreviewContent(content, width)
+	}
+	if l, ok := m.previewSearchMatcher().(filter.Locator); ok {
+		for i, row := range rows {
+			rows[i] = highlightRanges(row, l.Locate(stripANSI(row)), 0)
+		}
+	}
+	return rows
+}
+
+// previewWidth returns how many columns of content the preview pane shows.
+func (m model) previewWidth() int {
+	if m.config.PreviewPosition == PreviewTop || m.config.PreviewPosition == PreviewBottom {
+		return m.innerWidth()
+	}
+	return m.previewSize()
+}
+
+// actionEnterPreviewSearch focuses the preview and opens a search prompt
+// that scrolls it to matches.
+func (m *model) actionEnterPreviewSearch() (tea.Model, tea.Cmd) {
+	if !m.showPreview {
+		return m, nil
+	}
+	m.previewFocused = true
+	m.previewSearchMode = true
+	m.previewSearchOrigin = m.previewOffset
+	m.previewSearchInput.clear()
+	return m, nil
+}
+
+func (m *model) handlePreviewSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Cancel: go back to where the search started
+		m.previewSearchMode = false
+		m.previewSearchQuery = ""
+		m.previewOffset = m.previewSearchOrigin
+		return m, nil
+	case tea.KeyEnter:
+		m.previewSearchMode = false
+		m.previewSearchQuery = m.previewSearchInput.Text
+		if m.previewSearchQuery != "" {
+			if _, _, ok := m.findPreviewMatch(m.previewSearchOrigin, 1, true); !ok {
+				m.statusMsg = i18n.T("status.not_found", m.previewSearchQuery)
+				return m, m.statusTimeoutCmd()
+			}
+		}
+		return m, nil
+	default:
+		m.previewSearchInput.handleKey(msg)
+		m.previewSearchQuery = m.previewSearchInput.Text
+		m.previewOffset = m.previewSearchOrigin
+		if row, _, ok := m.findPreviewMatch(m.previewSearchOrigin, 1, true); ok {
+			m.previewOffset = row
+		}
+		m.clampPreviewOffset()
+		return m, nil
+	}
+}
+
+// actionPreviewSearchNext scrolls the preview to the next match in direction
+// dir, wrapping around its ends.
+func (m *model) actionPreviewSearchNext(dir int) (tea.Model, tea.Cmd) {
+	if m.previewSearchQuery == "" {
+		return m, nil
+	}
+	row, wrapped, ok := m.findPreviewMatch(m.previewOffset, dir, false)
+	if !ok {
+		m.statusMsg = i18n.T("status.not_found", m.previewSearchQuery)
+		return m, m.statusTimeoutCmd()
+	}
+	m.previewOffset = row
+	m.clampPreviewOffset()
+	if wrapped {
+		m.statusMsg = i18n.T("status.search_wrapped")
+		return m, m.statusTimeoutCmd()
+	}
+	return m, nil
+}
+
+// previewSearchMatcher compiles the preview search query, or returns nil
+// when there is none.
+func (m model) previewSearchMatcher() filter.Matcher {
+	if m.previewSearchQuery == "" {
+		return nil
+	}
+	matcher, err := m.compileFilter(filter.Substring, m.previewSearchQuery)
+	if err != nil {
+		return nil
+	}
+	return matcher
+}
+
+// findPreviewMatch searches the preview rows from row from in direction dir,
+// wrapping around once. With inclusive, from itself may match. Reports
+// whether the search wrapped.
+func (m model) findPreviewMatch(from, dir int, inclusive bool) (int, bool, bool) {
+	matcher := m.previewSearchMatcher()
+	if matcher == nil {
+		return 0, false, false
+	}
+	rows := wrapPreviewContent(m.previewContent(), m.previewWidth())
+	if m.previewTruncate {
+		rows = strings.Split(m.previewContent(), "\n")
+	}
+	n := len(rows)
+	if n == 0 {
+		return 0, false, false
+	}
+	start := 1
+	if inclusive {
+		start = 0
+	}
+	for step := start; step <= n; step++ {
+		i := from + dir*step
+		wrapped := i < 0 || i >= n
+		i = ((i % n) + n) % n
+		if matcher.Match(stripANSI(rows[i])) {
+			return i, wrapped, true
+		}
+	}
+	return 0, false, false
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestPreviewRowsTruncate(t *testing.T) {
+	m := testModelWithLines()
+	content := strings.Repeat("x", 30) + "\nshort"
+
+	if rows := m.previewRows(content, 10); len(rows) != 4 {
+		t.Errorf("expected wrapped content to take 4 rows, got %d", len(rows))
+	}
+	m.actionTogglePreviewWrap()
+	rows := m.previewRows(content, 10)
+	if len(rows) != 2 {
+		t.Fatalf("expected truncated content to take 2 rows, got %d", len(rows))
+	}
+	if rows[0] != strings.Repeat("x", 9)+ellipsis {
+		t.Errorf("expected first row to be truncated, got %q", rows[0])
+	}
+	if m.statusMsg != "Preview wrap off" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+}
+
+func TestPreviewSearch(t *testing.T) {
+	m := testModelWithLines()
+	m.lines = nil
+	for i := range 20 {
+		content := "line"
+		if i == 5 || i == 12 {
+			content = "baz"
+		}
+		m.lines = append(m.lines, runner.Line{Number: i + 1, Content: content})
+	}
+	m.updateFiltered()
+	m.config.PreviewPosition = PreviewBottom
+	m.config.PreviewSize = 3
+	m.showPreview = true
+	m.previewContext = true
+	m.config.PreviewContext = 20
+	m.previewFocused = true
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !m.previewSearchMode {
+		t.Fatal("expected / in a focused preview to open the preview search")
+	}
+	for _, r := range "baz" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.previewSearchQuery != "baz" {
+		t.Fatalf("expected query baz, got %q", m.previewSearchQuery)
+	}
+	if m.previewOffset != 5 {
+		t.Errorf("expected the preview to scroll to the first match, got offset %d", m.previewOffset)
+	}
+	if m.cursor != 0 {
+		t.Errorf("expected the list cursor to stay put, got %d", m.cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.previewOffset != 12 {
+		t.Errorf("expected n to scroll to the next match, got offset %d", m.previewOffset)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.previewOffset != 5 || m.statusMsg != "Search wrapped" {
+		t.Errorf("expected n to wrap to the first match, got offset %d, status %q", m.previewOffset, m.statusMsg)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.previewSearchQuery != "" || !m.previewFocused {
+		t.Error("expected the first Esc to clear the preview search and keep focus")
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expandPrompt fills in the Prompt placeholders: {exit_code}, {time},
+// {matches} and {interval}, so the prompt can double as a compact status
+// display. {exit_code} is empty until the first run finishes and
+// {interval} while auto-refresh is off.
+func (m model) expandPrompt() string {
+	if !strings.Contains(m.config.Prompt, "{") {
+		return m.config.Prompt
+	}
+	var exitCode, interval string
+	if m.finishedRuns > 0 {
+		exitCode = strconv.Itoa(m.lastExitCode)
+	}
+	if m.config.RefreshInterval > 0 {
+		interval = m.config.RefreshInterval.String()
+	}
+	return strings.NewReplacer(
+		"{exit_code}", exitCode,
+		"{time}", m.config.TimeFormat.format(time.Now(), time.TimeOnly),
+		"{matches}", strconv.Itoa(len(m.filtered)),
+		"{interval}", interval,
+	).Replace(m.config.Prompt)
+}
+
+// promptHasClock reports whether the prompt shows the time, which then has
+// to be redrawn every second.
+func (m model) promptHasClock() bool {
+	return strings.Contains(m.config.Prompt, "{time}")
+}
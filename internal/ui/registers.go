@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// maxYankHistory caps how many recent yanks the register viewer lists
+const maxYankHistory = 10
+
+// yank copies text to the clipboard and keeps it in the yank history and,
+// after "x, in register x.
+func (m *model) yank(text string) error {
+	m.yankHistory = append([]string{text}, m.yankHistory...)
+	if len(m.yankHistory) > maxYankHistory {
+		m.yankHistory = m.yankHistory[:maxYankHistory]
+	}
+	if m.register != "" {
+		if m.registers == nil {
+			m.registers = make(map[string]string)
+		}
+		m.registers[m.register] = text
+		m.yankedInto, m.register = m.register, ""
+	}
+	return copyToClipboard(text)
+}
+
+// registerNote returns the status suffix naming the register the last key's
+// yank went into, if any.
+func (m model) registerNote() string {
+	if m.yankedInto == "" {
+		return ""
+	}
+	return i18n.T("status.register_note", m.yankedInto)
+}
+
+// isRegisterName reports whether key names a register, a lowercase letter.
+func isRegisterName(key string) bool {
+	return len(key) == 1 && key[0] >= 'a' && key[0] <= 'z'
+}
+
+// handleRegisterKey reads the key after ": a register name for the next
+// yank or put, or " again for the register viewer.
+func (m *model) handleRegisterKey(key string) (tea.Model, tea.Cmd) {
+	m.registerPending = false
+	switch {
+	case key == `"`:
+		m.showRegisters = true
+	case isRegisterName(key):
+		m.register = key
+	}
+	return m, nil
+}
+
+// actionPutRegister copies a register back to the clipboard, showing what
+// it holds.
+func (m *model) actionPutRegister(name string) (tea.Model, tea.Cmd) {
+	text, ok := m.registers[name]
+	if !ok {
+		m.statusMsg = i18n.T("status.register_empty", name)
+		return m, m.statusTimeoutCmd()
+	}
+	if err := copyToClipboard(text); err != nil {
+		m.statusMsg = i18n.T("error.copy_failed")
+	} else {
+		first, _, _ := strings.Cut(text, "\n")
+		m.statusMsg = i18n.T("status.register_copied", name, truncateToWidth(first, 40))
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+func (m *model) actionShowRegisters() (tea.Model, tea.Cmd) {
+	m.showRegisters = true
+	return m, nil
+}
+
+// handleRegistersMode handles keys in the register viewer: a register's
+// letter or a recent yank's number copies it back to the clipboard.
+func (m *model) handleRegistersMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	m.showRegisters = false
+	if isRegisterName(key) {
+		return m.actionPutRegister(key)
+	}
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		if i := int(key[0] - '0'); i < len(m.yankHistory) {
+			if err := copyToClipboard(m.yankHistory[i]); err != nil {
+				m.statusMsg = i18n.T("error.copy_failed")
+			} else {
+				m.statusMsg = i18n.T("status.copied")
+			}
+			return m, m.statusTimeoutCmd()
+		}
+	}
+	return m, nil
+}
+
+// renderRegistersOverlay lists the named registers and the recent yanks.
+func (m model) renderRegistersOverlay() (box string, boxWidth, boxHeight int) {
+	titleStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Header).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Filter)
+	dimStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	const valueWidth = 50
+
+	entry := func(key, text string) string {
+		first, _, multiline := strings.Cut(text, "\n")
+		first = truncateToWidth(first, valueWidth)
+		if multiline {
+			first += dimStyle.Render(i18n.T("registers.more_lines", strings.Count(text, "\n")))
+		}
+		return "  " + keyStyle.Render(key) + "  " + first + "\n"
+	}
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(i18n.T("registers.title")) + "\n\n")
+	names := make([]string, 0, len(m.registers))
+	for name := range m.registers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		content.WriteString(entry(`"`+name, m.registers[name]))
+	}
+	if len(names) == 0 {
+		content.WriteString(dimStyle.Render("  "+i18n.T("registers.none")) + "\n")
+	}
+
+	content.WriteString("\n" + titleStyle.Render(i18n.T("registers.recent")) + "\n\n")
+	for i, text := range m.yankHistory {
+		content.WriteString(entry(fmt.Sprintf(" %d", i), text))
+	}
+	if len(m.yankHistory) == 0 {
+		content.WriteString(dimStyle.Render("  "+i18n.T("registers.no_yanks")) + "\n")
+	}
+	content.WriteString("\n" + dimStyle.Render(i18n.T("registers.close")))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(m.config.Border.lipgloss()).
+		BorderForeground(m.config.Theme.Header).
+		Padding(1, 2)
+	box = boxStyle.Render(content.String())
+	return box, lipgloss.Width(box), lipgloss.Height(box)
+}
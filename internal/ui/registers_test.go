@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYankIntoRegister(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 1
+
+	pressKeys(m, `"aY`)
+	if got := m.registers["a"]; got != "foo bar" {
+		t.Errorf("expected register a to hold the line, got %q", got)
+	}
+	if m.register != "" || m.registerPending {
+		t.Error("expected the register to apply only to one yank")
+	}
+	if m.statusMsg != "Failed to copy" && !strings.HasSuffix(m.statusMsg, "(register a)") {
+		t.Errorf("expected the status to name the register, got %q", m.statusMsg)
+	}
+
+	m.cursor = 3
+	pressKeys(m, "Y")
+	if len(m.registers) != 1 || m.registers["a"] != "foo bar" {
+		t.Errorf("expected a plain yank to leave the registers alone, got %v", m.registers)
+	}
+	if len(m.yankHistory) != 2 || m.yankHistory[0] != "baz qux" {
+		t.Errorf("expected both yanks in the history, newest first, got %q", m.yankHistory)
+	}
+
+	pressKeys(m, `"bp`)
+	if m.statusMsg != "Register b is empty" {
+		t.Errorf("expected an empty register to be reported, got %q", m.statusMsg)
+	}
+	if m.showPreview {
+		t.Error("expected p after a register not to toggle the preview")
+	}
+
+	pressKeys(m, `"aj`)
+	if m.register != "" || m.cursor != 3 {
+		t.Errorf("expected a register before a motion to be dropped, got %q", m.register)
+	}
+}
+
+func TestRegisterViewer(t *testing.T) {
+	m := testModelWithLines()
+	m.registers = map[string]string{"a": "pod/api", "b": "one\ntwo"}
+	m.yankHistory = []string{"pod/api"}
+
+	pressKeys(m, `""`)
+	if !m.showRegisters {
+		t.Fatal(`expected "" to open the register viewer`)
+	}
+	box, _, _ := m.renderRegistersOverlay()
+	box = stripANSI(box)
+	for _, want := range []string{`"a  pod/api`, `"b  one (+1 lines)`, "0  pod/api"} {
+		if !strings.Contains(box, want) {
+			t.Errorf("expected the viewer to list %q, got:\n%s", want, box)
+		}
+	}
+
+	pressKeys(m, "z")
+	if m.showRegisters || m.statusMsg != "Register z is empty" {
+		t.Errorf("expected a letter to close the viewer and put the register, got %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// replaying reports whether the UI is browsing recorded runs instead of
+// executing the command.
+func (m model) replaying() bool {
+	return len(m.config.Replay) > 0
+}
+
+// showReplayRun displays recorded run i as if it had just finished.
+func (m *model) showReplayRun(i int) {
+	if i < 0 || i >= len(m.config.Replay) {
+		return
+	}
+	run := m.config.Replay[i]
+	m.replayIndex = i
+	m.lines = make([]runner.Line, len(run.Lines))
+	for n, content := range run.Lines {
+		m.lines[n] = runner.Line{Number: n + 1, Content: content}
+	}
+	m.config.Command = run.Command
+	m.exitCode = run.ExitCode
+	m.outputDoc = documentRows(m.lines)
+	m.loading = false
+	m.idle = false
+	m.updateFiltered()
+}
+
+// actionReplayStep moves delta runs forward or backward through the recording.
+func (m *model) actionReplayStep(delta int) (tea.Model, tea.Cmd) {
+	if !m.replaying() {
+		return m, nil
+	}
+	i := m.replayIndex + delta
+	if i < 0 || i >= len(m.config.Replay) {
+		m.statusMsg = i18n.T("status.no_more_runs")
+		return m, m.statusTimeoutCmd()
+	}
+	m.showReplayRun(i)
+	return m, nil
+}
+
+// replayLabel describes the run being replayed, for the header.
+func (m model) replayLabel() string {
+	run := m.config.Replay[m.replayIndex]
+	return i18n.T("view.replay", m.replayIndex+1, len(m.config.Replay),
+		m.config.TimeFormat.since(run.Start, time.DateTime), run.Duration.Round(time.Millisecond))
+}
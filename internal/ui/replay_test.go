@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/runlog"
+)
+
+func testReplayModel() *model {
+	start := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	return testModel(Config{
+		Command:      "queue ls",
+		Shell:        "sh",
+		NoInitialRun: true,
+		Replay: []runlog.Run{
+			{Command: "queue ls", Start: start, Duration: time.Second, Lines: []string{"job 1"}},
+			{Command: "queue ls", Start: start.Add(time.Minute), Duration: 2 * time.Second, ExitCode: 1, Lines: []string{"job 1", "job 2"}},
+		},
+	})
+}
+
+func TestReplayOpensOnLatestRun(t *testing.T) {
+	m := testReplayModel()
+	if m.replayIndex != 1 {
+		t.Errorf("expected latest run to be shown, got index %d", m.replayIndex)
+	}
+	if len(m.lines) != 2 || m.exitCode != 1 {
+		t.Errorf("expected 2 lines with exit code 1, got %d lines, exit %d", len(m.lines), m.exitCode)
+	}
+	if m.idle || m.loading {
+		t.Error("expected replay not to be idle or loading")
+	}
+}
+
+func TestReplayStep(t *testing.T) {
+	m := testReplayModel()
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+	if m.replayIndex != 0 || len(m.lines) != 1 {
+		t.Errorf("expected previous run with 1 line, got index %d with %d lines", m.replayIndex, len(m.lines))
+	}
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+	if m.replayIndex != 0 || m.statusMsg != "No more runs" {
+		t.Errorf("expected to stay on the first run, got index %d (status %q)", m.replayIndex, m.statusMsg)
+	}
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	if m.replayIndex != 1 {
+		t.Errorf("expected next run, got index %d", m.replayIndex)
+	}
+}
+
+func TestReplayDoesNotRun(t *testing.T) {
+	m := testReplayModel()
+	m.actionReload()
+	if m.runCount != 0 || m.streaming {
+		t.Error("expected reload not to run anything while replaying")
+	}
+}
+
+func TestReplayHeader(t *testing.T) {
+	m := testReplayModel()
+	m.width = 100
+	m.height = 20
+	if header := m.renderHeaderLine(98); !strings.Contains(header, "[replay 2/2 • 2026-05-01 03:01:00 • 2s]") {
+		t.Errorf("expected replay label in header, got %q", header)
+	}
+}
+
+func TestStepOutsideReplay(t *testing.T) {
+	m := testModelWithLines()
+	m.actionReplayStep(1)
+	if len(m.lines) != 4 {
+		t.Error("expected stepping to do nothing outside replay mode")
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runlog"
+)
+
+// logRun saves the output of the finished run to the LogOutput directory and
+// appends it to the Record session file.
+func (m *model) logRun() {
+	if m.config.LogOutput == "" && m.config.Record == "" {
+		return
+	}
+	run := m.runLines()
+	lines := make([]string, len(run))
+	for i, line := range run {
+		lines[i] = line.Content
+	}
+	logged := runlog.Run{
+		Command:  m.config.Command,
+		Start:    m.runStartTime,
+		Duration: time.Since(m.runStartTime),
+		ExitCode: m.exitCode,
+		Lines:    lines,
+	}
+
+	if m.config.LogOutput != "" {
+		if _, err := runlog.Write(m.config.LogOutput, logged); err != nil {
+			m.errorMsg = i18n.T("error.log_failed", err)
+		}
+	}
+	if m.config.Record != "" {
+		if err := runlog.AppendRun(m.config.Record, logged); err != nil {
+			m.errorMsg = i18n.T("error.record_failed", err)
+		}
+	}
+}
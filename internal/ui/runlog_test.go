@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/runlog"
+)
+
+func TestLogRun(t *testing.T) {
+	dir := t.TempDir()
+	m := testModelWithLines()
+	m.config.LogOutput = dir
+	m.runStartTime = time.Now().Add(-time.Second)
+	m.exitCode = 1
+
+	m.logRun()
+
+	paths, err := runlog.List(dir)
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("expected 1 logged run, got %v (err %v)", paths, err)
+	}
+	run, err := runlog.Read(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Command != "echo test" || run.ExitCode != 1 {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if run.Duration < time.Second {
+		t.Errorf("expected duration of at least 1s, got %v", run.Duration)
+	}
+	want := []string{"hello world", "foo bar", "hello foo", "baz qux"}
+	if !slices.Equal(run.Lines, want) {
+		t.Errorf("expected lines %q, got %q", want, run.Lines)
+	}
+}
+
+func TestLogRunDisabled(t *testing.T) {
+	m := testModelWithLines()
+	m.logRun()
+	if m.errorMsg != "" {
+		t.Errorf("expected no error without LogOutput, got %q", m.errorMsg)
+	}
+}
+
+func TestLogRunRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.watchr")
+	if err := runlog.CreateSession(path, runlog.SessionInfo{Command: "echo test"}); err != nil {
+		t.Fatal(err)
+	}
+	m := testModelWithLines()
+	m.config.Record = path
+	m.runStartTime = time.Now()
+
+	m.logRun()
+	m.logRun()
+
+	_, runs, err := runlog.ReadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || len(runs[0].Lines) != 4 {
+		t.Errorf("expected 2 recorded runs of 4 lines, got %+v", runs)
+	}
+	if m.errorMsg != "" {
+		t.Errorf("unexpected error: %s", m.errorMsg)
+	}
+}
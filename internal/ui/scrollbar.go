@@ -0,0 +1,33 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// scrollbarThumbRange returns the list rows the scrollbar thumb covers, sized
+// and placed by how much of the filtered output is on screen. ok is false when
+// everything fits and no scrollbar is needed.
+func (m model) scrollbarThumbRange(listHeight int) (top, size int, ok bool) {
+	total := len(m.filtered)
+	if listHeight <= 0 || total <= listHeight {
+		return 0, 0, false
+	}
+	size = max(listHeight*listHeight/total, 1)
+	if maxOff := m.maxOffset(); maxOff > 0 {
+		top = min(m.offset, maxOff) * (listHeight - size) / maxOff
+	}
+	return top, size, true
+}
+
+// withScrollbar draws the scrollbar thumb (Border.Thumb) in the margin column after each
+// list row.
+func (m model) withScrollbar(listLines []string, listWidth int) []string {
+	top, size, ok := m.scrollbarThumbRange(len(listLines))
+	if !ok || !m.config.Scrollbar {
+		return listLines
+	}
+	thumbStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	for i := top; i < top+size && i < len(listLines); i++ {
+		row, _ := splitAtVisualWidth(listLines[i], listWidth)
+		listLines[i] = row + "\x1b[0m" + thumbStyle.Render(m.config.Border.Thumb)
+	}
+	return listLines
+}
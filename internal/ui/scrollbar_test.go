@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestScrollbarThumb(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Scrollbar = true
+	m.height = 15 // visibleLines = 10
+	if _, _, ok := m.scrollbarThumbRange(m.visibleLines()); ok {
+		t.Error("expected no scrollbar when all lines fit")
+	}
+
+	for i := range 36 {
+		m.lines = append(m.lines, runner.Line{Number: 5 + i, Content: "more"})
+	}
+	m.updateFiltered()
+
+	top, size, ok := m.scrollbarThumbRange(10)
+	if !ok || top != 0 || size != 2 {
+		t.Errorf("expected a 2-row thumb at the top, got top %d size %d", top, size)
+	}
+
+	m.offset = m.maxOffset()
+	if top, size, _ := m.scrollbarThumbRange(10); top+size != 10 {
+		t.Errorf("expected the thumb at the bottom when scrolled to the end, got top %d size %d", top, size)
+	}
+
+	rows := m.withScrollbar(m.renderListLines(10, 20), 20)
+	if got := stripANSI(rows[9]); !strings.HasSuffix(got, m.config.Border.Thumb) || len([]rune(got)) != 21 {
+		t.Errorf("expected the thumb in the margin column, got %q", got)
+	}
+	if strings.Contains(rows[0], m.config.Border.Thumb) {
+		t.Errorf("expected no thumb beside rows away from it, got %q", rows[0])
+	}
+
+	m.config.Scrollbar = false
+	if rows := m.withScrollbar(m.renderListLines(10, 20), 20); strings.Contains(rows[9], m.config.Border.Thumb) {
+		t.Error("expected no scrollbar when disabled")
+	}
+}
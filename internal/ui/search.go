@@ -0,0 +1,123 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// actionEnterSearch opens the search prompt. Unlike the filter, search keeps
+// every line visible and only moves the cursor between matches.
+func (m *model) actionEnterSearch() (tea.Model, tea.Cmd) {
+	m.searchMode = true
+	m.searchOrigin = m.cursor
+	m.searchInput.clear()
+	return m, nil
+}
+
+func (m *model) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "alt+c" {
+		result, cmd := m.actionCycleCase()
+		m.searchIncremental()
+		return result, cmd
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Cancel: go back to where the search started
+		m.searchMode = false
+		m.cursor = m.searchOrigin
+		m.adjustOffset()
+		return m, nil
+	case tea.KeyEnter:
+		m.searchMode = false
+		m.searchQuery = m.searchInput.Text
+		if m.searchQuery != "" && !m.searchMatches(m.cursor) {
+			m.statusMsg = i18n.T("status.not_found", m.searchQuery)
+			return m, m.statusTimeoutCmd()
+		}
+		return m, nil
+	default:
+		m.searchInput.handleKey(msg)
+		m.searchIncremental()
+		return m, nil
+	}
+}
+
+// searchIncremental moves the cursor to the first match at or after where the
+// search started, as the query is typed.
+func (m *model) searchIncremental() {
+	m.searchQuery = m.searchInput.Text
+	if m.searchQuery == "" {
+		m.cursor = m.searchOrigin
+	} else if i, _, ok := m.findMatch(m.searchOrigin, 1, true); ok {
+		m.cursor = i
+	}
+	m.userScrolled = true
+	m.previewOffset = 0
+	m.adjustOffset()
+}
+
+// actionSearchNext jumps to the next match in direction dir (1 for n, -1 for
+// N), wrapping around the ends of the list.
+func (m *model) actionSearchNext(dir int) (tea.Model, tea.Cmd) {
+	if m.searchQuery == "" {
+		return m, nil
+	}
+	i, wrapped, ok := m.findMatch(m.cursor, dir, false)
+	if !ok {
+		m.statusMsg = i18n.T("status.not_found", m.searchQuery)
+		return m, m.statusTimeoutCmd()
+	}
+	m.cursor = i
+	m.userScrolled = true
+	m.previewOffset = 0
+	m.adjustOffset()
+	if wrapped {
+		m.statusMsg = i18n.T("status.search_wrapped")
+		return m, m.statusTimeoutCmd()
+	}
+	return m, nil
+}
+
+// searchMatcher compiles the search query
+func (m model) searchMatcher() filter.Matcher {
+	matcher, err := m.compileFilter(filter.Substring, m.searchQuery)
+	if err != nil {
+		return nil
+	}
+	return matcher
+}
+
+// searchMatches reports whether the filtered line at i matches the search.
+func (m model) searchMatches(i int) bool {
+	if i < 0 || i >= len(m.filtered) || m.filtered[i] >= len(m.lines) {
+		return false
+	}
+	matcher := m.searchMatcher()
+	return matcher != nil && matcher.Match(m.shown(m.lines[m.filtered[i]].Content))
+}
+
+// findMatch searches the filtered lines from index from in direction dir,
+// wrapping around once. With inclusive, from itself may match. Reports
+// whether the search wrapped.
+func (m model) findMatch(from, dir int, inclusive bool) (int, bool, bool) {
+	n := len(m.filtered)
+	matcher := m.searchMatcher()
+	if n == 0 || matcher == nil {
+		return 0, false, false
+	}
+	start := 1
+	if inclusive {
+		start = 0
+	}
+	for step := start; step <= n; step++ {
+		i := from + dir*step
+		wrapped := i < 0 || i >= n
+		i = ((i % n) + n) % n
+		if m.filtered[i] < len(m.lines) && matcher.Match(m.shown(m.lines[m.filtered[i]].Content)) {
+			return i, wrapped, true
+		}
+	}
+	return 0, false, false
+}
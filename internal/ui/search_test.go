@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func searchFor(m *model, query string) {
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	for _, r := range query {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestSearchKeepsLinesVisible(t *testing.T) {
+	m := testModelWithLines()
+	searchFor(m, "foo")
+
+	if len(m.filtered) != 4 {
+		t.Errorf("expected search not to hide lines, got %d visible", len(m.filtered))
+	}
+	// "hello world", "foo bar", "hello foo", "baz qux"
+	if m.cursor != 1 {
+		t.Errorf("expected cursor on the first match, got %d", m.cursor)
+	}
+	if prompt := stripANSI(m.renderPromptLine()); !strings.Contains(prompt, "?foo") {
+		t.Errorf("expected the search in the prompt, got %q", prompt)
+	}
+}
+
+func TestSearchNextPrevious(t *testing.T) {
+	m := testModelWithLines()
+	searchFor(m, "hello")
+	if m.cursor != 0 {
+		t.Fatalf("expected the first match to include the current line, got %d", m.cursor)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.cursor != 2 {
+		t.Errorf("expected n to move to 2, got %d", m.cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.cursor != 0 || m.statusMsg != "Search wrapped" {
+		t.Errorf("expected n to wrap to 0, got %d (%q)", m.cursor, m.statusMsg)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m.cursor != 2 {
+		t.Errorf("expected N to wrap back to 2, got %d", m.cursor)
+	}
+}
+
+func TestSearchWithinFilter(t *testing.T) {
+	m := testModelWithLines()
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	searchFor(m, "foo")
+
+	// Filtered: "hello world", "hello foo"
+	if m.cursor != 1 {
+		t.Errorf("expected the match among filtered lines, got %d", m.cursor)
+	}
+}
+
+func TestSearchNotFound(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 3
+	searchFor(m, "nope")
+	if m.cursor != 3 {
+		t.Errorf("expected the cursor to stay put, got %d", m.cursor)
+	}
+	if !strings.Contains(m.statusMsg, "Pattern not found") {
+		t.Errorf("expected not found status, got %q", m.statusMsg)
+	}
+}
+
+func TestSearchEscRestoresCursor(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if m.cursor != 3 {
+		t.Fatalf("expected incremental search to move to 3, got %d", m.cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.searchMode || m.cursor != 0 {
+		t.Errorf("expected Esc to cancel and restore the cursor, got mode %v cursor %d", m.searchMode, m.cursor)
+	}
+}
+
+func TestEscClearsSearch(t *testing.T) {
+	m := testModelWithLines()
+	searchFor(m, "foo")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.searchQuery != "" || cmd != nil {
+		t.Errorf("expected Esc to clear the search before quitting, got %q", m.searchQuery)
+	}
+}
@@ -0,0 +1,24 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ExitAborted is the exit code when quitting without accepting a selection
+// under PrintSelection, as in fzf
+const ExitAborted = 130
+
+// actionAcceptSelection quits, keeping the marked lines, or the selected one,
+// to print once the UI is gone.
+func (m *model) actionAcceptSelection() (tea.Model, tea.Cmd) {
+	lines := m.markedLines(true)
+	if len(lines) == 0 {
+		line, ok := m.selectedPlain()
+		if !ok {
+			return m, nil
+		}
+		lines = []string{m.yanked(line)}
+	}
+	m.selection = lines
+	return m.actionQuit()
+}
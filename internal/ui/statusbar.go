@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// StatusBar lists the modules shown in the status bar (the prompt line), in
+// order, on its left and its right.
+type StatusBar struct {
+	Left, Right []string
+}
+
+// StatusModules are the modules a status bar can show
+var StatusModules = []string{
+	"prompt",    // the prompt, or the input being typed
+	"filter",    // the active filter, pinned filters and search
+	"activity",  // running / streaming spinner and how long the run has taken so far
+	"lines",     // lines received so far while streaming
+	"status",    // temporary status messages
+	"count",     // cursor position and matching lines
+	"help",      // the "h for help" hint
+	"exit-code", // exit code of the last run
+	"duration",  // how long the last run took, when it took a second or more
+	"countdown", // time until the next auto-refresh run
+	"clock",     // the current time
+}
+
+// DefaultStatusBar is the layout the status bar has unless configured
+var DefaultStatusBar = StatusBar{
+	Left:  []string{"prompt", "filter", "activity", "status"},
+	Right: []string{"duration", "countdown", "count", "help"},
+}
+
+// ParseStatusBar parses a status bar layout: comma-separated modules, those
+// after a | on the right (e.g. "prompt,filter,status | count,clock"). Empty
+// means the default layout.
+func ParseStatusBar(spec string) (StatusBar, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultStatusBar, nil
+	}
+	leftSpec, rightSpec, _ := strings.Cut(spec, "|")
+	parse := func(side string) ([]string, error) {
+		modules := []string{}
+		for name := range strings.SplitSeq(side, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(StatusModules, name) {
+				return nil, fmt.Errorf("unknown status bar module %q (expected %s)", name, strings.Join(StatusModules, ", "))
+			}
+			modules = append(modules, name)
+		}
+		return modules, nil
+	}
+	left, err := parse(leftSpec)
+	if err != nil {
+		return StatusBar{}, err
+	}
+	right, err := parse(rightSpec)
+	if err != nil {
+		return StatusBar{}, err
+	}
+	return StatusBar{Left: left, Right: right}, nil
+}
+
+// has reports whether the status bar shows module name.
+func (b StatusBar) has(name string) bool {
+	return slices.Contains(b.Left, name) || slices.Contains(b.Right, name)
+}
+
+// formatStopwatch formats d as mm:ss, or h:mm:ss from an hour.
+func formatStopwatch(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// clockTickMsg redraws the status bar's clock and relative timestamps
+type clockTickMsg struct{}
+
+func clockTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return clockTickMsg{}
+	})
+}
+
+// renderPromptLine renders the status bar: its left modules, then its right
+// modules aligned to the right edge. Input being typed is always shown, in
+// place of the prompt module.
+func (m model) renderPromptLine() string {
+	bar := m.config.StatusBar
+	var left []string
+	if input := m.renderInput(); input != "" && !bar.has("prompt") {
+		left = append(left, input)
+	}
+	for _, name := range bar.Left {
+		if s := m.renderStatusModule(name); s != "" {
+			left = append(left, s)
+		}
+	}
+	promptLine := strings.Join(left, " ")
+
+	// Keys typed so far come first: a register name and a count
+	mutedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	var right []string
+	if m.registerPending || m.register != "" {
+		right = append(right, mutedStyle.Render(`"`+m.register))
+	}
+	if m.pendingCount > 0 {
+		right = append(right, mutedStyle.Render(fmt.Sprint(m.pendingCount)))
+	}
+	for _, name := range bar.Right {
+		if s := m.renderStatusModule(name); s != "" {
+			right = append(right, s)
+		}
+	}
+	hint := strings.Join(right, "  ")
+	if gap := m.width - lipgloss.Width(promptLine) - lipgloss.Width(hint); gap > 0 && len(right) > 0 {
+		promptLine += strings.Repeat(" ", gap) + hint
+	}
+	return promptLine
+}
+
+// renderInput renders the note, search, pipe or filter input being typed, if
+// any.
+func (m model) renderInput() string {
+	filterStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Filter)
+	filterRegexStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Accent)
+	filterErrStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Error)
+	kind := m.filterKind()
+
+	// Pinned filters are shown as tags before the filter being typed
+	var pinned string
+	for _, p := range m.pinnedFilters {
+		pinned += filterStyle.Render("["+p.label()+"]") + " "
+	}
+
+	switch {
+	case m.noteMode:
+		before, block, after := m.noteInput.render()
+		return filterStyle.Render(noteMarker+" "+i18n.T("input.note")+before) + block + filterStyle.Render(after)
+	case m.searchMode:
+		before, block, after := m.searchInput.render()
+		return filterStyle.Render("?"+before) + block + filterStyle.Render(after)
+	case m.pipeMode:
+		label := "| "
+		if m.pipeAll {
+			label = i18n.T("input.pipe_all")
+		} else if len(m.marked) > 0 {
+			label = i18n.T("input.pipe_marked")
+		}
+		before, block, after := m.pipeInput.render()
+		return filterStyle.Render(label+before) + block + filterStyle.Render(after)
+	case m.previewSearchMode:
+		before, block, after := m.previewSearchInput.render()
+		return filterStyle.Render(i18n.T("input.preview_search")+before) + block + filterStyle.Render(after)
+	case m.filterMode && kind != filter.Substring:
+		label := filterRegexStyle.Render(string(kind) + "/")
+		before, block, after := m.filterInput.render()
+		input := pinned + label + filterStyle.Render(before) + block + filterStyle.Render(after)
+		if m.filterRegexErr != nil {
+			input += " " + filterErrStyle.Render(i18n.T("input.invalid", kind))
+		}
+		return input
+	case m.filterMode:
+		before, block, after := m.filterInput.render()
+		return pinned + filterStyle.Render("/"+before) + block + filterStyle.Render(after)
+	}
+	return ""
+}
+
+// typing reports whether an input is open in the status bar.
+func (m model) typing() bool {
+	return m.noteMode || m.searchMode || m.pipeMode || m.previewSearchMode || m.filterMode
+}
+
+// renderStatusModule renders one status bar module, or "" when it has
+// nothing to show.
+func (m model) renderStatusModule(name string) string {
+	promptStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Prompt)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+
+	switch name {
+	case "prompt":
+		if m.typing() {
+			return m.renderInput()
+		}
+		return promptStyle.Render(m.expandPrompt())
+
+	case "filter":
+		var parts []string
+		if !m.typing() {
+			kind := m.filterKind()
+			switch {
+			case len(m.pinnedFilters) > 0:
+				filters := m.pinnedLabels()
+				if m.filterInput.Text != "" {
+					current := pinnedFilter{text: m.filterInput.Text, kind: kind}
+					filters += " + " + current.label()
+				}
+				parts = append(parts, promptStyle.Render(i18n.T("bar.filters", filters)))
+			case m.filterInput.Text != "" && kind != filter.Substring:
+				parts = append(parts, promptStyle.Render(i18n.T("bar.filter_kind", kind, m.filterInput.Text)))
+			case m.filterInput.Text != "":
+				parts = append(parts, promptStyle.Render(i18n.T("bar.filter", m.filterInput.Text)))
+			}
+		}
+		if !m.searchMode && m.searchQuery != "" {
+			parts = append(parts, lipgloss.NewStyle().Foreground(m.config.Theme.Filter).Render("?"+m.searchQuery))
+		}
+		return strings.Join(parts, " ")
+
+	case "activity":
+		// The stopwatch only makes sense once a run has started
+		elapsed := ""
+		if !m.runStartTime.IsZero() {
+			elapsed = " " + mutedStyle.Render(formatStopwatch(time.Since(m.runStartTime)))
+		}
+		switch {
+		case m.streaming:
+			return spinnerFrames[m.spinnerFrame] + " " + i18n.T("bar.streaming") + elapsed
+		case m.loading:
+			return spinnerFrames[m.spinnerFrame] + " " + i18n.T("bar.running") + elapsed
+		case m.idle:
+			return mutedStyle.Render(i18n.T("bar.not_run"))
+		}
+
+	case "status":
+		if m.statusMsg != "" {
+			return lipgloss.NewStyle().Foreground(m.config.Theme.Status).Render(m.statusMsg)
+		}
+
+	case "lines":
+		if m.streaming && m.streamResult != nil {
+			return mutedStyle.Render(i18n.T("bar.lines", m.streamResult.GetCurrentLineCount()))
+		}
+
+	case "count":
+		return mutedStyle.Render(m.countLabel())
+
+	case "help":
+		if !m.config.HideHints {
+			return mutedStyle.Render(i18n.T("bar.help"))
+		}
+
+	case "exit-code":
+		if m.finishedRuns > 0 {
+			style := lipgloss.NewStyle().Foreground(m.config.Theme.Status)
+			if m.lastExitCode != 0 {
+				style = style.Foreground(m.config.Theme.Error)
+			}
+			return style.Render(i18n.T("bar.exit_code", m.lastExitCode))
+		}
+
+	case "duration":
+		if m.finishedRuns > 0 && m.lastRunDuration >= time.Second && !m.streaming {
+			return mutedStyle.Render(i18n.T("bar.duration", formatStopwatch(m.lastRunDuration)))
+		}
+
+	case "countdown":
+		if remaining, ok := m.untilRefresh(); ok {
+			return mutedStyle.Render(i18n.T("bar.countdown", int(math.Ceil(remaining.Seconds()))))
+		}
+
+	case "clock":
+		return mutedStyle.Render(m.config.TimeFormat.format(time.Now(), time.TimeOnly))
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func TestParseStatusBar(t *testing.T) {
+	bar, err := ParseStatusBar("")
+	if err != nil || !slices.Equal(bar.Left, DefaultStatusBar.Left) || !slices.Equal(bar.Right, DefaultStatusBar.Right) {
+		t.Errorf("expected the default layout, got %v, %v", bar, err)
+	}
+
+	bar, err = ParseStatusBar("prompt, status | exit-code,clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(bar.Left, []string{"prompt", "status"}) || !slices.Equal(bar.Right, []string{"exit-code", "clock"}) {
+		t.Errorf("unexpected layout %v", bar)
+	}
+
+	if bar, err := ParseStatusBar("count"); err != nil || len(bar.Left) != 1 || len(bar.Right) != 0 {
+		t.Errorf("expected a left-only layout, got %v, %v", bar, err)
+	}
+	if _, err := ParseStatusBar("prompt | weather"); err == nil {
+		t.Error("expected an error for an unknown module")
+	}
+}
+
+func TestStatusBarModules(t *testing.T) {
+	m := testModelWithLines()
+	m.config.StatusBar = StatusBar{Left: []string{"prompt", "exit-code"}, Right: []string{"duration"}}
+	m.config.Prompt = "> "
+	if got := stripANSI(m.renderPromptLine()); strings.TrimSpace(got) != ">" {
+		t.Errorf("expected only the prompt before the first run, got %q", got)
+	}
+
+	m.finishedRuns = 1
+	m.lastExitCode = 3
+	m.lastRunDuration = 83 * time.Second
+	got := stripANSI(m.renderPromptLine())
+	if !strings.HasPrefix(got, ">  exit 3") || !strings.HasSuffix(got, "took 01:23") {
+		t.Errorf("expected the exit code and duration, got %q", got)
+	}
+}
+
+func TestStatusBarShowsInputWithoutPrompt(t *testing.T) {
+	m := testModelWithLines()
+	m.config.StatusBar = StatusBar{Right: []string{"count"}}
+	m.filterMode = true
+	m.filterInput.Text = "foo"
+	if got := stripANSI(m.renderPromptLine()); !strings.HasPrefix(got, "/foo") {
+		t.Errorf("expected the filter input to be shown, got %q", got)
+	}
+}
+
+func TestFormatStopwatch(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                       "00:00",
+		1500 * time.Millisecond: "00:01",
+		83 * time.Second:        "01:23",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+	}
+	for d, want := range tests {
+		if got := formatStopwatch(d); got != want {
+			t.Errorf("formatStopwatch(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestStatusBarCountdown(t *testing.T) {
+	m := testModelWithLines()
+	m.config.RefreshInterval = 10 * time.Second
+	m.refreshStartTime = time.Now().Add(-2500 * time.Millisecond)
+	if got := stripANSI(m.renderStatusModule("countdown")); got != "next run in 8s" {
+		t.Errorf("expected the time until the next run, got %q", got)
+	}
+	if !strings.Contains(stripANSI(m.renderPromptLine()), "next run in 8s") {
+		t.Error("expected the countdown in the default status bar")
+	}
+
+	m.streaming = true
+	if got := m.renderStatusModule("countdown"); got != "" {
+		t.Errorf("expected no countdown while a run is in progress, got %q", got)
+	}
+}
+
+func TestStatusBarProgress(t *testing.T) {
+	m := testModelWithLines()
+	m.streaming = true
+	m.loading = true
+	if got := stripANSI(m.renderStatusModule("activity")); !strings.HasSuffix(got, "Streaming…") {
+		t.Errorf("expected no stopwatch before the run starts, got %q", got)
+	}
+
+	m.runStartTime = time.Now().Add(-3 * time.Second)
+	if got := stripANSI(m.renderStatusModule("activity")); !strings.HasSuffix(got, "Streaming… 00:03") {
+		t.Errorf("expected the elapsed time of the run, got %q", got)
+	}
+
+	m.streamResult = &runner.StreamingResult{CurrentLineCount: 42}
+	if got := stripANSI(m.renderStatusModule("lines")); got != "42 lines" {
+		t.Errorf("expected the streamed line count, got %q", got)
+	}
+}
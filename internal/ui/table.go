@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// tableGap separates columns in table mode
+const tableGap = "  "
+
+func (m *model) actionToggleTable() (tea.Model, tea.Cmd) {
+	m.tableMode = !m.tableMode
+	m.tableCol = 0
+	m.updateFiltered()
+	m.adjustOffset()
+	if m.tableMode {
+		m.statusMsg = i18n.T("status.table_on")
+	} else {
+		m.statusMsg = i18n.T("status.table_off")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+// actionScrollColumns scrolls table mode's columns by n, keeping at least
+// the last column in view. The first column shown is the selected one.
+func (m *model) actionScrollColumns(n int) (tea.Model, tea.Cmd) {
+	if !m.tableMode {
+		return m, nil
+	}
+	m.tableCol = max(min(m.tableCol+n, len(m.tableShown)-1), 0)
+	return m, nil
+}
+
+// tableCells splits a line into table mode's columns. Lines with more fields
+// than the header have the rest joined into the last column, as in the
+// COMMAND column of ps aux.
+func (m model) tableCells(content string) []string {
+	fields := splitFields(stripANSI(m.shown(content)), m.config.Delimiter)
+	n := len(m.tableWidths)
+	cells := make([]string, 0, len(fields))
+	for i, f := range fields {
+		if n > 0 && i == n-1 && len(fields) > n {
+			var rest strings.Builder
+			for j, f := range fields[i:] {
+				rest.WriteString(f.text)
+				if j < len(fields)-i-1 {
+					rest.WriteString(f.delim)
+				}
+			}
+			return append(cells, rest.String())
+		}
+		cells = append(cells, f.text)
+	}
+	return cells
+}
+
+// updateTableWidths sizes table mode's columns to their widest cell across
+// the whole output, so they don't shift as the filter changes. The column
+// names, the last header line, decide how many columns there are.
+func (m *model) updateTableWidths() {
+	m.tableWidths, m.tableShown, m.tableSizes = nil, nil, nil
+	if !m.tableMode || len(m.lines) == 0 {
+		return
+	}
+	names := m.headerLines() - 1
+	m.tableWidths = make([]int, len(splitFields(stripANSI(m.shown(m.lines[names].Content)), m.config.Delimiter)))
+	m.applyColumnLayout()
+
+	// The last column isn't padded, so it needs no width unless it's moved
+	sized := len(m.tableWidths) - 1
+	if len(m.tableShown) > 0 && m.tableShown[len(m.tableShown)-1] != sized {
+		sized++
+	}
+	for _, line := range m.lines[names:] {
+		for i, cell := range m.tableCells(line.Content) {
+			if i < sized {
+				m.tableWidths[i] = max(m.tableWidths[i], lipgloss.Width(cell))
+			}
+		}
+	}
+}
+
+// tableRow renders a line as aligned columns, starting from the column
+// scrolled to.
+func (m model) tableRow(content string) string {
+	cells := m.tableCells(content)
+	shown := m.tableShown[min(m.tableCol, len(m.tableShown)):]
+	// A line ends at the last column it has a cell in
+	last := -1
+	for n, i := range shown {
+		if i < len(cells) {
+			last = n
+		}
+	}
+	var b strings.Builder
+	for n, i := range shown[:last+1] {
+		if n > 0 {
+			b.WriteString(tableGap)
+		}
+		cell := m.tableCell(cells, i)
+		b.WriteString(cell)
+		if n < last {
+			b.WriteString(strings.Repeat(" ", max(m.columnWidth(i)-lipgloss.Width(cell), 0)))
+		}
+	}
+	return b.String()
+}
+
+// tableCell returns a line's cell in column i, cut to the column's width if
+// it was set in the column chooser.
+func (m model) tableCell(cells []string, i int) string {
+	if i >= len(cells) {
+		return ""
+	}
+	if width, ok := m.tableSizes[i]; ok {
+		return truncateToWidth(cells[i], width)
+	}
+	return cells[i]
+}
+
+// columnWidth returns the width of table mode's column i: the width set in
+// the column chooser, or else its widest cell's.
+func (m model) columnWidth(i int) int {
+	if width, ok := m.tableSizes[i]; ok {
+		return width
+	}
+	if i < len(m.tableWidths) {
+		return m.tableWidths[i]
+	}
+	return 0
+}
+
+// selectedColumn returns the column selected in table mode, the first one
+// shown, or -1 if there is none.
+func (m model) selectedColumn() int {
+	if m.tableCol < len(m.tableShown) {
+		return m.tableShown[m.tableCol]
+	}
+	return -1
+}
+
+// displayed returns a line's content as drawn in the list: aligned columns
+// in table mode, otherwise the displayed fields.
+func (m model) displayed(content string) string {
+	if m.tableMode {
+		return m.tableRow(content)
+	}
+	return m.shown(content)
+}
+
+// selectedFields returns the selected column's cell of the marked lines, or
+// of the selected line, with the column's name.
+func (m model) selectedFields() (name string, values []string) {
+	col := m.selectedColumn()
+	cell := func(content string) string {
+		if cells := m.tableCells(content); col >= 0 && col < len(cells) {
+			return cells[col]
+		}
+		return ""
+	}
+	if n := m.headerLines(); n > 0 {
+		name = cell(m.lines[n-1].Content)
+	}
+	if len(m.marked) > 0 {
+		for _, line := range m.lines {
+			if m.marked[line.Number] {
+				values = append(values, cell(line.Content))
+			}
+		}
+		return name, values
+	}
+	if idx := m.selectedIndex(); idx >= 0 && idx < len(m.lines) {
+		values = []string{cell(m.lines[idx].Content)}
+	}
+	return name, values
+}
+
+// actionYankField copies only the selected column (the first one shown) of
+// the selected or marked lines, e.g. just a pod's name or a PID.
+func (m *model) actionYankField() (tea.Model, tea.Cmd) {
+	if !m.tableMode {
+		m.statusMsg = i18n.T("status.field_needs_table")
+		return m, m.statusTimeoutCmd()
+	}
+	name, values := m.selectedFields()
+	if len(values) == 0 {
+		return m, nil
+	}
+	if err := m.yank(strings.Join(values, "\n")); err != nil {
+		m.statusMsg = i18n.T("error.copy_failed")
+	} else if len(values) == 1 {
+		m.statusMsg = i18n.T("status.copied_value", values[0]) + m.registerNote()
+	} else {
+		m.statusMsg = i18n.T("status.copied_values", len(values), name) + m.registerNote()
+	}
+	return m, m.statusTimeoutCmd()
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func tableModel() *model {
+	m := testModelWithLines()
+	m.lines = []runner.Line{
+		{Number: 1, Content: "USER PID COMMAND"},
+		{Number: 2, Content: "root 1 /sbin/init splash"},
+		{Number: 3, Content: "postgres 4242 postgres -D /var/lib/pg"},
+	}
+	pressKeys(m, "t")
+	return m
+}
+
+func TestTableRows(t *testing.T) {
+	m := tableModel()
+	if !slices.Equal(m.tableWidths, []int{8, 4, 0}) {
+		t.Errorf("unexpected column widths %v", m.tableWidths)
+	}
+	if !slices.Equal(m.filtered, []int{1, 2}) {
+		t.Errorf("expected the header to be left out of the list, got %v", m.filtered)
+	}
+	if got := m.tableRow(m.lines[0].Content); got != "USER      PID   COMMAND" {
+		t.Errorf("unexpected header %q", got)
+	}
+	if got := m.tableRow(m.lines[2].Content); got != "postgres  4242  postgres -D /var/lib/pg" {
+		t.Errorf("expected extra fields in the last column, got %q", got)
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "root      1     /sbin/init splash") {
+		t.Errorf("expected the list drawn as aligned columns, got %q", view)
+	}
+
+	pressKeys(m, "L")
+	if got := m.tableRow(m.lines[2].Content); got != "4242  postgres -D /var/lib/pg" {
+		t.Errorf("expected the first column scrolled out, got %q", got)
+	}
+	m.actionScrollColumns(10)
+	if m.tableCol != 2 {
+		t.Errorf("expected scrolling to stop at the last column, got %d", m.tableCol)
+	}
+
+	pressKeys(m, "t")
+	if m.tableWidths != nil || m.displayed(m.lines[1].Content) != "root 1 /sbin/init splash" {
+		t.Errorf("expected table mode off to show lines as they are, got %q", m.displayed(m.lines[1].Content))
+	}
+	if len(m.filtered) != 3 {
+		t.Errorf("expected table mode off to list every line, got %v", m.filtered)
+	}
+}
+
+func TestTableHeaderView(t *testing.T) {
+	m := tableModel()
+	m.config.PreviewPosition = PreviewBottom
+	rows := strings.Split(stripANSI(m.View()), "\n")
+	if !strings.Contains(rows[3], "USER      PID   COMMAND") {
+		t.Errorf("expected the header above the list, got %q", rows[3])
+	}
+	if !strings.Contains(rows[4], "root      1     /sbin/init splash") {
+		t.Errorf("expected the first row under the header, got %q", rows[4])
+	}
+
+	if region, row := m.regionAt(5, 4); region != regionList || row != 0 {
+		t.Errorf("expected the row under the header to be the first list row, got %v %d", region, row)
+	}
+	if region, _ := m.regionAt(5, 3); region != regionNone {
+		t.Errorf("expected the header not to be a list row, got %v", region)
+	}
+}
+
+func TestTableDelimiter(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Delimiter = regexp.MustCompile(`,`)
+	m.lines = []runner.Line{
+		{Number: 1, Content: "name,age"},
+		{Number: 2, Content: "alice,30"},
+		{Number: 3, Content: "bob,4,extra"},
+	}
+	m.actionToggleTable()
+	if got := m.tableRow(m.lines[2].Content); got != "bob    4,extra" {
+		t.Errorf("expected extra fields in the last column, got %q", got)
+	}
+}
+
+func TestPinnedHeader(t *testing.T) {
+	m := testModelWithLines()
+	m.config.HeaderLines = 2
+	m.config.PreviewPosition = PreviewBottom
+	pressKeys(m, "T")
+	if !m.pinHeader || m.headerLines() != 2 {
+		t.Fatalf("expected two pinned header lines, got %d", m.headerLines())
+	}
+
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	if !slices.Equal(m.filtered, []int{2}) {
+		t.Errorf("expected the header lines to be left out of filtering, got %v", m.filtered)
+	}
+	rows := strings.Split(stripANSI(m.View()), "\n")
+	if !strings.Contains(rows[3], "hello world") || !strings.Contains(rows[4], "foo bar") || !strings.Contains(rows[5], "hello foo") {
+		t.Errorf("expected the header lines above the filtered list, got %q", rows[3:6])
+	}
+
+	pressKeys(m, "T")
+	if m.headerLines() != 0 || !slices.Equal(m.filtered, []int{0, 2}) {
+		t.Errorf("expected unpinning to filter every line, got %v", m.filtered)
+	}
+}
+
+func TestYankField(t *testing.T) {
+	m := tableModel()
+	m.cursor = 1
+	m.actionScrollColumns(1)
+	if name, values := m.selectedFields(); name != "PID" || !slices.Equal(values, []string{"4242"}) {
+		t.Errorf("expected the selected line's PID, got %s %v", name, values)
+	}
+
+	m.marked = map[int]bool{2: true, 3: true}
+	if _, values := m.selectedFields(); !slices.Equal(values, []string{"1", "4242"}) {
+		t.Errorf("expected the marked lines' PIDs, got %v", values)
+	}
+
+	m.actionYankField()
+	if m.statusMsg != "Failed to copy" && m.statusMsg != "Copied 2 PID values to clipboard" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+
+	m.actionToggleTable()
+	m.actionYankField()
+	if m.statusMsg != "Field yank needs table mode (t)" {
+		t.Errorf("expected field yank to need table mode, got %q", m.statusMsg)
+	}
+}
@@ -5,38 +5,107 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
+// ellipsis marks truncated text unless Config.Ellipsis replaces it
 const ellipsis = "…"
 
+// segment is a piece of a string as drawn: an ANSI escape sequence, which
+// takes up no columns, or a grapheme cluster and the columns it takes up
+type segment struct {
+	text   string
+	width  int
+	escape bool
+}
+
+// segments splits s into its ANSI escape sequences and grapheme clusters.
+// Clusters are measured as a whole, so wide (CJK) characters take 2 columns,
+// combining marks none, and emoji joined by zero-width joiners count once.
+func segments(s string) []segment {
+	var segs []segment
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			n := escapeLen(s)
+			segs = append(segs, segment{text: s[:n], escape: true})
+			s = s[n:]
+			continue
+		}
+		text := s
+		if i := strings.IndexByte(s, '\x1b'); i >= 0 {
+			text = s[:i]
+		}
+		g := uniseg.NewGraphemes(text)
+		for g.Next() {
+			segs = append(segs, segment{text: g.Str(), width: runewidth.StringWidth(g.Str())})
+		}
+		s = s[len(text):]
+	}
+	return segs
+}
+
+// escapeLen returns the length of the escape sequence s starts with: a CSI
+// sequence (ESC [) up to its final byte, or up to the first letter for others.
+func escapeLen(s string) int {
+	if len(s) > 1 && s[1] == '[' {
+		for i := 2; i < len(s); i++ {
+			// Final byte of CSI sequence is in range 0x40-0x7E
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+	}
+	for i := 1; i < len(s); i++ {
+		if isAnsiTerminator(rune(s[i])) {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// textWidth returns the columns s takes up, ignoring ANSI escape sequences.
+func textWidth(s string) int {
+	width := 0
+	for _, seg := range segments(s) {
+		width += seg.width
+	}
+	return width
+}
+
 // truncateToWidth truncates a string to fit within the given visual width,
 // adding an ellipsis if truncation occurs. Uses visual width, not byte count.
 func truncateToWidth(s string, maxWidth int) string {
+	return truncateWith(s, maxWidth, ellipsis)
+}
+
+// truncateWith is truncateToWidth ending truncated text with tail instead of
+// the ellipsis.
+func truncateWith(s string, maxWidth int, tail string) string {
 	if maxWidth <= 0 {
 		return ""
 	}
-	sw := lipgloss.Width(s)
-	if sw <= maxWidth {
+	if textWidth(s) <= maxWidth {
 		return s
 	}
-	// Need to truncate - leave room for ellipsis (1 char wide)
-	targetWidth := maxWidth - 1
+	// Need to truncate - leave room for the tail
+	targetWidth := maxWidth - textWidth(tail)
 	if targetWidth <= 0 {
-		return ellipsis
+		return truncateWith(tail, maxWidth, "")
 	}
 
-	// Truncate rune by rune until we fit
+	// Truncate cluster by cluster until we fit
 	var result strings.Builder
 	currentWidth := 0
-	for _, r := range s {
-		runeWidth := lipgloss.Width(string(r))
-		if currentWidth+runeWidth > targetWidth {
+	for _, seg := range segments(s) {
+		if currentWidth+seg.width > targetWidth {
 			break
 		}
-		result.WriteRune(r)
-		currentWidth += runeWidth
+		result.WriteString(seg.text)
+		currentWidth += seg.width
 	}
-	return result.String() + ellipsis
+	return result.String() + tail
 }
 
 // wrapText wraps text to fit within the given width, returning multiple lines.
@@ -44,7 +113,13 @@ func truncateToWidth(s string, maxWidth int) string {
 // toward the visible width. When a line wraps, any active ANSI state is
 // carried over so colours continue on the next line.
 func wrapText(s string, width int) []string {
-	if width <= 0 {
+	return wrapTextIndent(s, width, width)
+}
+
+// wrapTextIndent is wrapText with the lines after the first wrapped to
+// restWidth, leaving room for a continuation marker.
+func wrapTextIndent(s string, width, restWidth int) []string {
+	if width <= 0 || restWidth <= 0 {
 		return nil
 	}
 	if s == "" {
@@ -57,40 +132,23 @@ func wrapText(s string, width int) []string {
 	// Track the last seen ANSI escape so we can re-apply it after a wrap
 	var activeANSI string
 
-	i := 0
-	runes := []rune(s)
-	for i < len(runes) {
-		// Check for ANSI escape sequence: ESC [ ... final_byte
-		if runes[i] == '\033' && i+1 < len(runes) && runes[i+1] == '[' {
-			// Consume entire escape sequence
-			var seq strings.Builder
-			seq.WriteRune(runes[i]) // ESC
-			i++
-			seq.WriteRune(runes[i]) // [
-			i++
-			for i < len(runes) {
-				seq.WriteRune(runes[i])
-				// Final byte of CSI sequence is in range 0x40-0x7E
-				if runes[i] >= 0x40 && runes[i] <= 0x7E {
-					i++
-					break
-				}
-				i++
+	for _, seg := range segments(s) {
+		if seg.escape {
+			currentLine.WriteString(seg.text)
+			if !strings.HasPrefix(seg.text, "\033[") {
+				continue
 			}
-			seqStr := seq.String()
-			currentLine.WriteString(seqStr)
 			// Track reset vs color sequences
-			if seqStr == "\033[0m" || seqStr == "\033[m" {
+			if seg.text == "\033[0m" || seg.text == "\033[m" {
 				activeANSI = ""
 			} else {
-				activeANSI = seqStr
+				activeANSI = seg.text
 			}
 			continue
 		}
 
-		r := runes[i]
-		runeWidth := lipgloss.Width(string(r))
-		if currentWidth+runeWidth > width {
+		if currentWidth+seg.width > width && currentWidth > 0 {
+			width = restWidth
 			// Close any active ANSI on this line before wrapping
 			if activeANSI != "" {
 				currentLine.WriteString("\033[0m")
@@ -103,9 +161,8 @@ func wrapText(s string, width int) []string {
 				currentLine.WriteString(activeANSI)
 			}
 		}
-		currentLine.WriteRune(r)
-		currentWidth += runeWidth
-		i++
+		currentLine.WriteString(seg.text)
+		currentWidth += seg.width
 	}
 	// Don't forget the last line
 	if currentLine.Len() > 0 {
@@ -135,67 +192,29 @@ func wrapPreviewContent(s string, width int) []string {
 func splitAtVisualWidth(s string, targetWidth int) (string, string) {
 	var left, right strings.Builder
 	visualWidth := 0
-	inEscape := false
-	runes := []rune(s)
+	segs := segments(s)
 
 	i := 0
 	// Build left part up to targetWidth
-	for i < len(runes) && visualWidth < targetWidth {
-		r := runes[i]
-
-		if r == '\x1b' {
-			// Start of ANSI escape sequence - include it in left part
-			left.WriteRune(r)
-			i++
-			for i < len(runes) && !isAnsiTerminator(runes[i]) {
-				left.WriteRune(runes[i])
-				i++
-			}
-			if i < len(runes) {
-				left.WriteRune(runes[i]) // terminator
-				i++
-			}
-			continue
-		}
-
-		runeWidth := lipgloss.Width(string(r))
-		if visualWidth+runeWidth <= targetWidth {
-			left.WriteRune(r)
-			visualWidth += runeWidth
-			i++
-		} else {
+	for ; i < len(segs) && visualWidth < targetWidth; i++ {
+		if !segs[i].escape && visualWidth+segs[i].width > targetWidth {
 			break
 		}
+		// ANSI escape sequences are included in the left part
+		left.WriteString(segs[i].text)
+		visualWidth += segs[i].width
 	}
 
-	// Pad left if needed
+	// Pad left if needed, e.g. when a wide character straddles the split
 	for visualWidth < targetWidth {
 		left.WriteRune(' ')
 		visualWidth++
 	}
 
-	// Skip runes in the "overlay zone" - we don't need them for right part calculation
-	// The caller will handle inserting the overlay content
-
 	// Build right part from remaining
-	for ; i < len(runes); i++ {
-		r := runes[i]
-		if r == '\x1b' {
-			right.WriteRune(r)
-			i++
-			for i < len(runes) && !isAnsiTerminator(runes[i]) {
-				right.WriteRune(runes[i])
-				i++
-			}
-			if i < len(runes) {
-				right.WriteRune(runes[i])
-			}
-			continue
-		}
-		right.WriteRune(r)
+	for ; i < len(segs); i++ {
+		right.WriteString(segs[i].text)
 	}
-
-	_ = inEscape // unused but kept for clarity
 	return left.String(), right.String()
 }
 
@@ -205,41 +224,25 @@ func skipVisualWidth(s string, skipWidth int) string {
 	var result strings.Builder
 	var ansiState strings.Builder // collect ANSI codes while skipping
 	visualWidth := 0
-	runes := []rune(s)
+	segs := segments(s)
 
 	i := 0
 	// Skip until we've passed skipWidth, but collect ANSI codes
-	for i < len(runes) && visualWidth < skipWidth {
-		r := runes[i]
-
-		if r == '\x1b' {
-			// ANSI escape - collect it (don't count visual width)
-			ansiState.WriteRune(r)
-			i++
-			for i < len(runes) && !isAnsiTerminator(runes[i]) {
-				ansiState.WriteRune(runes[i])
-				i++
-			}
-			if i < len(runes) {
-				ansiState.WriteRune(runes[i]) // terminator
-				i++
-			}
+	for ; i < len(segs) && visualWidth < skipWidth; i++ {
+		if segs[i].escape {
+			ansiState.WriteString(segs[i].text)
 			continue
 		}
-
-		runeWidth := lipgloss.Width(string(r))
-		visualWidth += runeWidth
-		i++
+		visualWidth += segs[i].width
 	}
 
 	// Prepend collected ANSI state to restore styling
 	result.WriteString(ansiState.String())
 
 	// Output the rest
-	for ; i < len(runes); i++ {
-		result.WriteRune(runes[i])
+	for ; i < len(segs); i++ {
+		result.WriteString(segs[i].text)
 	}
-
 	return result.String()
 }
 
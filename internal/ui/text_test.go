@@ -248,3 +248,35 @@ func TestOverlayBox(t *testing.T) {
 		t.Errorf("expected overlay in line 2, got %q", lines[2])
 	}
 }
+
+func TestWideCharacterWidths(t *testing.T) {
+	const family = "👨‍👩‍👧" // three emoji joined by zero-width joiners
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"hello", 5},
+		{"日本語", 6},
+		{family, 2},
+		{"é", 1}, // e with a combining acute accent
+		{"\x1b[31m日本\x1b[0m", 4},
+	}
+	for _, tt := range tests {
+		if got := textWidth(tt.input); got != tt.want {
+			t.Errorf("textWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	if got := truncateToWidth("日本語テキスト", 6); got != "日本…" {
+		t.Errorf("expected wide characters to be truncated whole, got %q", got)
+	}
+	if got := truncateToWidth(family+family+"abc", 4); got != family+"…" {
+		t.Errorf("expected the joined emoji to be kept whole, got %q", got)
+	}
+	if got := wrapText("日本語", 4); len(got) != 2 || got[0] != "日本" || got[1] != "語" {
+		t.Errorf("expected wide characters to wrap whole, got %q", got)
+	}
+	if left, right := splitAtVisualWidth("日本語", 3); left != "日 " || right != "本語" {
+		t.Errorf("expected the split to pad around a wide character, got %q, %q", left, right)
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors the UI is drawn with. Colors are ANSI color numbers
+// ("12") or hex codes ("#5f87ff").
+type Theme struct {
+	Border        lipgloss.Color // box borders
+	Header        lipgloss.Color // title, pinned header lines and overlay borders
+	Selection     lipgloss.Color // background of the selected line
+	SelectionText lipgloss.Color // text of the selected line
+	LineNumber    lipgloss.Color // line number gutter
+	Filter        lipgloss.Color // filter and search input, notes
+	Accent        lipgloss.Color // filter mode labels, replay and binary markers
+	Prompt        lipgloss.Color // prompt, streaming indicator, selected line in previews
+	Error         lipgloss.Color // failed runs and errors
+	Status        lipgloss.Color // status messages and successful runs
+	Warning       lipgloss.Color // anomaly markers
+	Text          lipgloss.Color // text in overlays
+	Muted         lipgloss.Color // hints, countdowns and other secondary text
+}
+
+// Themes are the built-in themes, by name
+var Themes = map[string]Theme{
+	"default": {
+		Border: "240", Header: "12", Selection: "15", SelectionText: "#000000", LineNumber: "241",
+		Filter: "11", Accent: "13", Prompt: "14", Error: "9", Status: "10", Warning: "208",
+		Text: "252", Muted: "241",
+	},
+	// For terminals with a light background
+	"light": {
+		Border: "250", Header: "25", Selection: "24", SelectionText: "#ffffff", LineNumber: "245",
+		Filter: "130", Accent: "90", Prompt: "30", Error: "160", Status: "28", Warning: "166",
+		Text: "236", Muted: "244",
+	},
+	"high-contrast": {
+		Border: "15", Header: "14", Selection: "11", SelectionText: "#000000", LineNumber: "250",
+		Filter: "11", Accent: "13", Prompt: "14", Error: "9", Status: "10", Warning: "208",
+		Text: "15", Muted: "250",
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// themeColors maps the config names of theme colors to their fields
+func (t *Theme) themeColors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"border":         &t.Border,
+		"header":         &t.Header,
+		"selection":      &t.Selection,
+		"selection-text": &t.SelectionText,
+		"line-number":    &t.LineNumber,
+		"filter":         &t.Filter,
+		"accent":         &t.Accent,
+		"prompt":         &t.Prompt,
+		"error":          &t.Error,
+		"status":         &t.Status,
+		"warning":        &t.Warning,
+		"text":           &t.Text,
+		"muted":          &t.Muted,
+	}
+}
+
+// LoadTheme returns the named built-in theme with colors overridden by name
+// (e.g. "border": "63").
+func LoadTheme(name string, overrides map[string]string) (Theme, error) {
+	if name == "" {
+		name = "default"
+	}
+	t, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (expected %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	colors := t.themeColors()
+	for key, color := range overrides {
+		field, ok := colors[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme color %q", key)
+		}
+		*field = lipgloss.Color(color)
+	}
+	return t, nil
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme("", nil)
+	if err != nil || theme != Themes["default"] {
+		t.Errorf("expected the default theme, got %v, %v", theme, err)
+	}
+
+	theme, err = LoadTheme("light", map[string]string{"border": "63", "selection-text": "#ffffff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Border != "63" || theme.SelectionText != "#ffffff" {
+		t.Errorf("expected overridden colors, got %v", theme)
+	}
+	if theme.Header != Themes["light"].Header {
+		t.Errorf("expected the light theme's other colors to be kept, got %v", theme.Header)
+	}
+
+	if _, err := LoadTheme("neon", nil); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+	if _, err := LoadTheme("default", map[string]string{"borders": "63"}); err == nil {
+		t.Error("expected an error for an unknown color")
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	if got := ThemeNames(); !slices.Equal(got, []string{"default", "high-contrast", "light"}) {
+		t.Errorf("ThemeNames() = %v", got)
+	}
+}
+
+func TestThemeDefaultsWhenUnset(t *testing.T) {
+	m := initialModel(Config{Command: "true"})
+	if m.config.Theme != Themes["default"] {
+		t.Errorf("expected the default theme, got %v", m.config.Theme)
+	}
+}
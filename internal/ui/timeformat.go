@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// TimeFormat controls how timestamps are shown: the run times in the header,
+// replay label and append-mode separators, the clock, and yanked lines.
+type TimeFormat struct {
+	Layout   string // Go time layout; empty keeps each place's default
+	Strftime string // strftime format, used instead of Layout when set
+	Relative bool   // show past times as their age, e.g. "12s ago"
+}
+
+// strftime maps strftime directives to the Go layouts they format as
+var strftime = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2", 'j': "002",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM",
+	'b': "Jan", 'h': "Jan", 'B': "January", 'a': "Mon", 'A': "Monday",
+	'Z': "MST", 'z': "-0700",
+	'F': "2006-01-02", 'T': "15:04:05", 'R': "15:04", 'D': "01/02/06",
+}
+
+// ParseTimeFormat parses a timestamp format: "relative", a strftime format
+// (anything with a %, e.g. %H:%M:%S) or a Go time layout (e.g. 15:04:05).
+// An empty spec keeps the defaults. Text around strftime directives is kept
+// as is, even where a Go layout would read it as part of the time.
+func ParseTimeFormat(spec string) (TimeFormat, error) {
+	switch {
+	case spec == "":
+		return TimeFormat{}, nil
+	case spec == "relative":
+		return TimeFormat{Relative: true}, nil
+	case !strings.Contains(spec, "%"):
+		return TimeFormat{Layout: spec}, nil
+	}
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			continue
+		}
+		if i++; i == len(spec) {
+			return TimeFormat{}, fmt.Errorf("time format %q ends in %%", spec)
+		}
+		if _, ok := strftime[spec[i]]; !ok && spec[i] != '%' {
+			return TimeFormat{}, fmt.Errorf("unsupported directive %%%c in time format %q", spec[i], spec)
+		}
+	}
+	return TimeFormat{Strftime: spec}, nil
+}
+
+// format formats t as configured, or with the layout def when no format is
+// set.
+func (f TimeFormat) format(t time.Time, def string) string {
+	switch {
+	case f.Strftime != "":
+		return formatStrftime(t, f.Strftime)
+	case f.Layout != "":
+		return t.Format(f.Layout)
+	}
+	return t.Format(def)
+}
+
+// formatStrftime formats t with a strftime format ParseTimeFormat accepted,
+// formatting each directive on its own so the text around it is copied
+// verbatim.
+func formatStrftime(t time.Time, spec string) string {
+	var b strings.Builder
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' || i+1 == len(spec) {
+			b.WriteByte(spec[i])
+			continue
+		}
+		i++
+		if layout, ok := strftime[spec[i]]; ok {
+			b.WriteString(t.Format(layout))
+		} else {
+			b.WriteByte(spec[i]) // %%
+		}
+	}
+	return b.String()
+}
+
+// since formats a past time t as configured: its age in relative mode,
+// otherwise like format.
+func (f TimeFormat) since(t time.Time, def string) string {
+	if !f.Relative {
+		return f.format(t, def)
+	}
+	return i18n.T("time.ago", formatAge(time.Since(t)))
+}
+
+// formatAge formats d in its largest whole unit: 12s, 5m, 3h or 2d.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(max(d, 0).Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeFormat(t *testing.T) {
+	tests := []struct {
+		spec string
+		want TimeFormat
+	}{
+		{"", TimeFormat{}},
+		{"relative", TimeFormat{Relative: true}},
+		{"15:04", TimeFormat{Layout: "15:04"}},
+		{"%H:%M UTC+2", TimeFormat{Strftime: "%H:%M UTC+2"}},
+	}
+	for _, tt := range tests {
+		if got, err := ParseTimeFormat(tt.spec); err != nil || got != tt.want {
+			t.Errorf("ParseTimeFormat(%q) = %+v, %v; want %+v", tt.spec, got, err, tt.want)
+		}
+	}
+	for _, spec := range []string{"%Q", "%H:%"} {
+		if _, err := ParseTimeFormat(spec); err == nil {
+			t.Errorf("expected %q to fail", spec)
+		}
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	if got := (TimeFormat{}).format(at, time.TimeOnly); got != "15:04:05" {
+		t.Errorf("expected the default layout, got %q", got)
+	}
+	if got := (TimeFormat{Layout: "Jan 2 15:04"}).since(at, time.TimeOnly); got != "Jan 2 15:04" {
+		t.Errorf("expected the configured layout, got %q", got)
+	}
+	strftime := map[string]string{
+		"%Y-%m-%d %H:%M:%S":    "2026-01-02 15:04:05",
+		"%H:%M UTC+2":          "15:04 UTC+2",
+		"%T %p (100%%)":        "15:04:05 PM (100%)",
+		"Mon %a, Jan %e":       "Mon Fri, Jan  2",
+		"%I:%M %p on %d/%m/%y": "03:04 PM on 02/01/26",
+	}
+	for spec, want := range strftime {
+		f, err := ParseTimeFormat(spec)
+		if err != nil {
+			t.Fatalf("ParseTimeFormat(%q): %v", spec, err)
+		}
+		if got := f.format(at, time.TimeOnly); got != want {
+			t.Errorf("format with %q = %q, want %q", spec, got, want)
+		}
+	}
+	relative := TimeFormat{Relative: true}
+	if got := relative.since(time.Now().Add(-90*time.Second), time.TimeOnly); got != "1m ago" {
+		t.Errorf("expected a relative time, got %q", got)
+	}
+	if got := relative.format(at, time.TimeOnly); got != "15:04:05" {
+		t.Errorf("expected format to ignore relative mode, got %q", got)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		-time.Second:                    "0s",
+		12 * time.Second:                "12s",
+		5 * time.Minute:                 "5m",
+		59*time.Minute + 59*time.Second: "59m",
+		3*time.Hour + 59*time.Minute:    "3h",
+		50 * time.Hour:                  "2d",
+	}
+	for d, want := range tests {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTimeFormatHeader(t *testing.T) {
+	m := testModel(Config{HeaderTemplate: "{last_run}", TimeFormat: TimeFormat{Relative: true}})
+	m.finishedRuns = 1
+	m.lastRunEnd = time.Now().Add(-12 * time.Second)
+	if got := m.expandHeaderTemplate("ls"); got != "12s ago" {
+		t.Errorf("expected the last run as its age, got %q", got)
+	}
+}
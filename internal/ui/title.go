@@ -0,0 +1,20 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// actionToggleTitle hides or shows the title line with the command, as
+// watch -t does, e.g. to keep its arguments off a shared screen.
+func (m *model) actionToggleTitle() (tea.Model, tea.Cmd) {
+	m.hideTitle = !m.hideTitle
+	m.adjustOffset()
+	if m.hideTitle {
+		m.statusMsg = i18n.T("status.title_hidden")
+	} else {
+		m.statusMsg = i18n.T("status.title_shown")
+	}
+	return m, m.statusTimeoutCmd()
+}
@@ -2,9 +2,12 @@ package ui
 
 import (
 	"context"
+	"math/rand/v2"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
 	"github.com/chenasraf/watchr/internal/runner"
 )
 
@@ -17,27 +20,104 @@ func initialModel(cfg Config) model {
 	} else {
 		r = runner.NewRunner(cfg.Shell, cfg.Command)
 	}
+	r.KeepCRFrames = cfg.KeepProgressFrames
+	r.ControlChars = cfg.ControlChars
+	r.TabWidth = cfg.TabWidth
+	r.SSH = cfg.SSH
+	r.Docker = cfg.Docker
+	r.KubectlPod = cfg.KubectlPod
+	r.RemoteShell = cfg.RemoteShell
+	r.PreRun = cfg.PreRun
+	r.PostRun = cfg.PostRun
+
+	var filterInput textInput
+	filterInput.Text = cfg.Filter
+	filterInput.Cursor = len(cfg.Filter)
+	filterKind := cfg.FilterKind
+	if filterKind == "" {
+		filterKind = cfg.FilterMode
+	}
+	filterCase := cfg.FilterCase
+	if filterCase == "" {
+		filterCase = filter.IgnoreCase
+	}
+	if cfg.Theme == (Theme{}) {
+		cfg.Theme = Themes["default"]
+	}
+	if cfg.Border == (Border{}) {
+		cfg.Border = Borders["rounded"]
+	}
+	if cfg.Ellipsis == "" {
+		cfg.Ellipsis = ellipsis
+	}
+	if cfg.StatusBar.Left == nil && cfg.StatusBar.Right == nil {
+		cfg.StatusBar = DefaultStatusBar
+	}
+
+	m := model{
+		config:               cfg,
+		lines:                []runner.Line{},
+		filtered:             []int{},
+		cursor:               0,
+		offset:               0,
+		filterInput:          filterInput,
+		notes:                cfg.Notes,
+		filterRegex:          filterKind == filter.Regex,
+		filterGlob:           filterKind == filter.Glob,
+		filterFuzzy:          filterKind == filter.Fuzzy,
+		filterCase:           filterCase,
+		pinnedFilters:        restoreFilters(cfg.PinnedFilters, filterCase),
+		filterMode:           false,
+		showPreview:          cfg.PreviewOpen,
+		pendingAnchor:        cfg.Anchor,
+		tableLayout:          cfg.Columns,
+		wrapLines:            cfg.Wrap,
+		tableMode:            cfg.Table,
+		pinHeader:            cfg.HeaderLines > 0,
+		hideTitle:            cfg.HideTitle,
+		relativeNums:         cfg.RelativeLineNums,
+		autoPreviewDismissed: -1,
+		resumeBaseline:       cfg.Baseline,
+		pendingMarks:         cfg.Marks,
+		runner:               r,
+		ctx:                  ctx,
+		cancel:               cancel,
+		loading:              !cfg.NoInitialRun,
+		idle:                 cfg.NoInitialRun,
+		startTime:            time.Now(),
+	}
 
-	return model{
-		config:      cfg,
-		lines:       []runner.Line{},
-		filtered:    []int{},
-		cursor:      0,
-		offset:      0,
-		filterMode:  false,
-		showPreview: false,
-		runner:      r,
-		ctx:         ctx,
-		cancel:      cancel,
-		loading:     true,
+	// Replays open on the most recent run
+	if m.replaying() {
+		m.showReplayRun(len(cfg.Replay) - 1)
 	}
+	return m
 }
 
 func (m *model) Init() tea.Cmd {
-	// Send a message to start streaming (handled in Update with pointer receiver)
-	return func() tea.Msg {
-		return startStreamMsg{}
+	var cmds []tea.Cmd
+	if m.config.JournalPath != "" {
+		m.saveJournal()
+		cmds = append(cmds, m.journalTickCmd())
+	}
+	if m.config.StatusBar.has("clock") || m.promptHasClock() || m.config.TimeFormat.Relative {
+		cmds = append(cmds, clockTickCmd())
+	}
+	if len(m.config.DimAge) > 0 {
+		cmds = append(cmds, ageTickCmd())
 	}
+
+	switch {
+	case !m.config.NoInitialRun:
+		// Send a message to start streaming (handled in Update with pointer receiver)
+		cmds = append(cmds, func() tea.Msg {
+			return startStreamMsg{}
+		})
+	case m.config.RefreshInterval > 0:
+		// Wait for a manual reload, or start the refresh timer so its first tick runs the command
+		cmds = append(cmds, m.scheduleRefresh())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) spinnerTickCmd() tea.Cmd {
@@ -47,29 +127,46 @@ func (m model) spinnerTickCmd() tea.Cmd {
 }
 
 func (m model) streamTickCmd() tea.Cmd {
+	gen := m.refreshGeneration
 	return tea.Tick(50*time.Millisecond, func(t time.Time) tea.Msg {
-		return streamTickMsg(t)
+		return streamTickMsg{generation: gen}
 	})
 }
 
+// countdownTickInterval is how often the refresh countdown is redrawn, often
+// enough that it never lags a whole second behind
+const countdownTickInterval = 250 * time.Millisecond
+
 func (m model) countdownTickCmd() tea.Cmd {
 	gen := m.refreshGeneration
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(countdownTickInterval, func(t time.Time) tea.Msg {
 		return countdownTickMsg{generation: gen}
 	})
 }
 
 func (m *model) startStreaming() tea.Cmd {
-	// Cancel any existing context and create a new one
+	// Cancel any in-flight run and create a new context for this one
 	if m.cancel != nil {
 		m.cancel()
 	}
 	m.ctx, m.cancel = context.WithCancel(context.Background())
 
-	// Pass previous lines for in-place updates
-	m.streamResult = m.runner.RunStreaming(m.ctx, m.lines)
+	// Bump the generation so ticks and results from the cancelled run are dropped
+	m.refreshGeneration++
+	m.runCount++
+	m.runStartTime = time.Now()
+
+	// Pass previous lines for in-place updates, unless the run is appended
+	// after them
+	m.startAppending()
+	prevLines := m.lines
+	if m.config.Append {
+		prevLines = nil
+	}
+	m.streamResult = m.runner.RunStreaming(m.ctx, prevLines)
 	m.streaming = true
 	m.loading = true
+	m.idle = false
 	m.lastLineCount = len(m.lines)
 	m.exitCode = -1
 	m.errorMsg = ""
@@ -78,60 +175,93 @@ func (m *model) startStreaming() tea.Cmd {
 	cmds := []tea.Cmd{m.streamTickCmd()}
 
 	// Start refresh timer from command start if configured
-	if m.config.RefreshFromStart && m.config.RefreshInterval > 0 {
-		m.refreshStartTime = time.Now()
-		cmds = append(cmds, m.tickCmd())
-		if m.config.RefreshInterval > time.Second {
-			cmds = append(cmds, m.countdownTickCmd())
-		}
+	if m.config.RefreshFromStart && m.config.RefreshInterval > 0 && !m.refreshLimitReached() {
+		cmds = append(cmds, m.scheduleRefresh())
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// requestRun starts a run, or defers it until MinInterval has passed since the
+// last run started. Triggers that arrive while a run is deferred are coalesced
+// into that single run.
+func (m *model) requestRun() tea.Cmd {
+	if m.replaying() {
+		m.statusMsg = i18n.T("status.replaying")
+		return m.statusTimeoutCmd()
+	}
+	if m.config.MinInterval > 0 && !m.runStartTime.IsZero() {
+		if wait := m.config.MinInterval - time.Since(m.runStartTime); wait > 0 {
+			if m.runDeferred {
+				return nil
+			}
+			m.runDeferred = true
+			m.statusMsg = i18n.T("status.rate_limited", wait.Round(100*time.Millisecond))
+			return tea.Batch(
+				tea.Tick(wait, func(t time.Time) tea.Msg { return deferredRunMsg{} }),
+				m.statusTimeoutCmd(),
+			)
+		}
+	}
+	m.runDeferred = false
+	cmd := m.startStreaming()
+	return tea.Batch(cmd, m.spinnerTickCmd())
+}
+
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	result, cmd := m.update(msg)
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.rememberAnchor()
+	}
+	m.syncAutoPreview()
+	return result, tea.Batch(cmd, m.syncPreviewCommand())
+}
+
+func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.adjustOffset()
 		return m, nil
 
 	case startStreamMsg:
 		cmd := m.startStreaming()
 		return m, tea.Batch(cmd, m.spinnerTickCmd())
 
-	case resultMsg:
-		m.lines = msg.lines
-		m.exitCode = msg.exitCode
-		m.loading = false
-		m.streaming = false
-		m.updateFiltered()
-		return m, nil
-
 	case streamTickMsg:
-		if m.streamResult == nil {
+		// Ignore ticks polling a run that has since been replaced
+		if msg.generation != m.refreshGeneration || m.streamResult == nil {
 			return m, nil
 		}
 
 		// Check for new lines
-		newLines := m.streamResult.GetLines()
+		newLines := m.withAppended(m.streamResult.GetLines())
 		newCount := len(newLines)
 
+		m.binary = m.streamResult.IsBinary()
 		if newCount != m.lastLineCount {
 			m.lines = newLines
 			m.lastLineCount = newCount
 			m.updateFiltered()
 
-			// Auto-scroll to bottom if user hasn't manually scrolled
+			// Auto-scroll to bottom if user hasn't manually scrolled,
+			// otherwise keep the cursor on the line being inspected
 			if !m.userScrolled {
-				visible := m.visibleLines()
-				if visible > 0 {
+				if m.visibleLines() > 0 {
 					m.cursor = max(len(m.filtered)-1, 0)
-					m.offset = max(len(m.filtered)-visible, 0)
+					m.offset = m.maxOffset()
+					m.rememberAnchor()
 				}
+			} else {
+				m.reanchorCursor()
 			}
 		}
 
@@ -140,51 +270,113 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.streaming = false
 			m.loading = false
 			m.exitCode = m.streamResult.ExitCode
+			m.lastRunEnd = time.Now()
+			m.lastRunDuration = m.lastRunEnd.Sub(m.runStartTime)
+			notify := m.recordExitCode(m.exitCode)
 			if m.streamResult.Error != nil {
 				m.errorMsg = m.streamResult.Error.Error()
 			}
 
-			// Trim excess lines from previous run
+			// Take the final output, trimming excess lines from the previous
+			// run, and follow the selected line to wherever it moved
 			currentCount := m.streamResult.GetCurrentLineCount()
-			if currentCount < len(m.lines) {
-				m.lines = m.lines[:currentCount]
-				m.updateFiltered()
+			runLines := m.streamResult.GetLines()
+			if currentCount < len(runLines) {
+				runLines = runLines[:currentCount]
+			}
+			m.lines = m.withAppended(runLines)
+			m.markChanges()
+			m.outputDoc = documentRows(m.lines)
+			m.updateFiltered()
+			m.reanchorCursor()
+			m.restoreAnchor()
+			m.restoreSession()
+			m.logRun()
+			alert := tea.Batch(notify, m.checkAnomaly(currentCount), m.webhookCmd(), m.markNewLines())
+
+			// Stop auto-refreshing once --max-runs or --for is exhausted
+			if m.config.RefreshInterval > 0 && m.refreshLimitReached() {
+				mm, cmd := m.stopRefresh()
+				return mm, tea.Batch(cmd, alert)
 			}
 
 			// If auto-refresh is enabled and timer starts from end, schedule the next run
 			if m.config.RefreshInterval > 0 && !m.config.RefreshFromStart {
-				m.refreshStartTime = time.Now()
-				cmds := []tea.Cmd{m.tickCmd()}
-				// Start countdown display updates if interval > 1s
-				if m.config.RefreshInterval > time.Second {
-					cmds = append(cmds, m.countdownTickCmd())
-				}
-				return m, tea.Batch(cmds...)
+				return m, tea.Batch(m.scheduleRefresh(), alert)
 			}
-			return m, nil
+			return m, alert
 		}
 
 		// Continue streaming
 		return m, m.streamTickCmd()
 
 	case tickMsg:
-		// Ignore ticks from before a manual refresh
+		// Ignore ticks scheduled by an earlier run
 		if msg.generation != m.refreshGeneration {
 			return m, nil
 		}
 		if m.config.RefreshInterval > 0 && !m.streaming {
+			if m.refreshLimitReached() {
+				return m.stopRefresh()
+			}
 			// Restart streaming for refresh
-			cmd := m.startStreaming()
-			return m, tea.Batch(cmd, m.spinnerTickCmd())
+			return m, m.requestRun()
 		}
 		return m, nil
 
+	case deferredRunMsg:
+		if !m.runDeferred {
+			return m, nil
+		}
+		m.runDeferred = false
+		cmd := m.startStreaming()
+		return m, tea.Batch(cmd, m.spinnerTickCmd())
+
 	case errMsg:
 		m.errorMsg = msg.Error()
 		m.loading = false
 		m.streaming = false
 		return m, nil
 
+	case pipeOutputMsg:
+		return m.showPipeOutput(msg)
+
+	case bindDoneMsg:
+		return m.handleBindDone(msg)
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = i18n.T("error.editor_failed", msg.err)
+			return m, m.statusTimeoutCmd()
+		}
+		return m, nil
+
+	case editorFilterMsg:
+		if msg.err != nil {
+			m.statusMsg = i18n.T("error.editor_failed", msg.err)
+			return m, m.statusTimeoutCmd()
+		}
+		m.filterMode = false
+		m.filterInput.Text = msg.text
+		m.filterInput.Cursor = len(msg.text)
+		m.updateFiltered()
+		return m, nil
+
+	case previewDueMsg:
+		return m, m.runPreviewCmd(msg.line)
+
+	case previewOutputMsg:
+		m.storePreview(msg)
+		return m, nil
+
+	case hookFailedMsg:
+		m.statusMsg = msg.err.Error()
+		return m, m.statusTimeoutCmd()
+
+	case journalTickMsg:
+		m.saveJournal()
+		return m, m.journalTickCmd()
+
 	case clearStatusMsg:
 		m.statusMsg = ""
 		return m, nil
@@ -196,17 +388,24 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case newLinesFadedMsg:
+		// Nothing to update: the markers fade when the list is redrawn
+		return m, nil
+
+	case clockTickMsg:
+		return m, clockTickCmd()
+
+	case ageTickMsg:
+		return m, ageTickCmd()
+
 	case countdownTickMsg:
-		// Ignore ticks from before a manual refresh
+		// Ignore ticks scheduled by an earlier run
 		if msg.generation != m.refreshGeneration {
 			return m, nil
 		}
 		// Continue ticking if waiting for auto-refresh
-		if m.config.RefreshInterval > time.Second && !m.streaming && !m.refreshStartTime.IsZero() {
-			elapsed := time.Since(m.refreshStartTime)
-			if elapsed < m.config.RefreshInterval {
-				return m, m.countdownTickCmd()
-			}
+		if _, ok := m.untilRefresh(); ok {
+			return m, m.countdownTickCmd()
 		}
 		return m, nil
 	}
@@ -214,9 +413,70 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) tickCmd() tea.Cmd {
+// refreshLimitReached reports whether the MaxRuns or RunFor limit has been hit.
+func (m model) refreshLimitReached() bool {
+	if m.config.MaxRuns > 0 && m.runCount >= m.config.MaxRuns {
+		return true
+	}
+	if m.config.RunFor > 0 && time.Since(m.startTime) >= m.config.RunFor {
+		return true
+	}
+	return false
+}
+
+// stopRefresh stops auto-refreshing after a limit is reached, either freezing
+// the current output or quitting when ExitOnLimit is set.
+func (m *model) stopRefresh() (tea.Model, tea.Cmd) {
+	m.refreshStopped = true
+	m.refreshStartTime = time.Time{}
+	if m.config.ExitOnLimit {
+		return m.actionQuit()
+	}
+	return m, nil
+}
+
+// untilRefresh returns how long until the next auto-refresh run, while
+// waiting for one with an interval long enough to count down.
+func (m model) untilRefresh() (time.Duration, bool) {
+	if m.config.RefreshInterval <= time.Second || m.refreshStopped || m.streaming || m.refreshStartTime.IsZero() {
+		return 0, false
+	}
+	remaining := m.config.RefreshInterval - time.Since(m.refreshStartTime)
+	return remaining, remaining > 0
+}
+
+// scheduleRefresh starts the refresh timer for the next run, along with the
+// countdown display updates when the interval is long enough to show them.
+func (m *model) scheduleRefresh() tea.Cmd {
+	delay := m.nextRefreshDelay(time.Now())
+	// Back-date the start so the countdown reflects the actual delay
+	m.refreshStartTime = time.Now().Add(delay - m.config.RefreshInterval)
+	cmds := []tea.Cmd{m.tickCmd(delay)}
+	if m.config.RefreshInterval > time.Second {
+		cmds = append(cmds, m.countdownTickCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// nextRefreshDelay returns how long to wait before the next run. In precise
+// mode runs are aligned to wall-clock multiples of the interval (like
+// `watch -p`); otherwise the full interval is used. A random amount up to
+// RefreshJitter is added so multiple instances don't fire in lockstep.
+func (m model) nextRefreshDelay(now time.Time) time.Duration {
+	interval := m.config.RefreshInterval
+	delay := interval
+	if m.config.Precise {
+		delay = now.Truncate(interval).Add(interval).Sub(now)
+	}
+	if m.config.RefreshJitter > 0 {
+		delay += rand.N(m.config.RefreshJitter)
+	}
+	return delay
+}
+
+func (m model) tickCmd(delay time.Duration) tea.Cmd {
 	gen := m.refreshGeneration
-	return tea.Tick(m.config.RefreshInterval, func(t time.Time) tea.Msg {
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
 		return tickMsg{generation: gen}
 	})
 }
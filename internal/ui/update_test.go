@@ -2,9 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chenasraf/watchr/internal/runner"
 )
 
 func TestUpdateWindowSize(t *testing.T) {
@@ -85,3 +88,304 @@ func TestUpdateErrMsg(t *testing.T) {
 		t.Error("expected streaming false after error")
 	}
 }
+
+func TestUpdateStaleStreamTickIgnored(t *testing.T) {
+	m := testModelWithCancel()
+	m.startStreaming()
+	gen := m.refreshGeneration
+
+	// A second run replaces the first; ticks from the first must not keep polling
+	m.startStreaming()
+	if m.refreshGeneration != gen+1 {
+		t.Fatalf("expected generation %d after restart, got %d", gen+1, m.refreshGeneration)
+	}
+
+	_, cmd := m.Update(streamTickMsg{generation: gen})
+	if cmd != nil {
+		t.Error("expected no command for a stale stream tick")
+	}
+	m.cancel()
+}
+
+func TestInitNoInitialRun(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", NoInitialRun: true})
+
+	if m.loading {
+		t.Error("expected loading false when initial run is skipped")
+	}
+	if !m.idle {
+		t.Error("expected idle true when initial run is skipped")
+	}
+	if cmd := m.Init(); cmd != nil {
+		t.Error("expected no command without a refresh interval")
+	}
+}
+
+func TestInitNoInitialRunWithRefresh(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", NoInitialRun: true, RefreshInterval: 50 * time.Millisecond})
+
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatal("expected refresh timer command")
+	}
+	if m.refreshStartTime.IsZero() {
+		t.Error("expected refresh timer to be started")
+	}
+	if m.streamResult != nil {
+		t.Error("expected command not to run before the first tick")
+	}
+}
+
+func TestRefreshLimitReached(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", MaxRuns: 2})
+	m.runCount = 1
+	if m.refreshLimitReached() {
+		t.Error("expected limit not reached after 1 of 2 runs")
+	}
+	m.runCount = 2
+	if !m.refreshLimitReached() {
+		t.Error("expected limit reached after 2 of 2 runs")
+	}
+
+	m = testModel(Config{Command: "echo test", Shell: "sh", RunFor: time.Minute})
+	if m.refreshLimitReached() {
+		t.Error("expected duration limit not reached right after start")
+	}
+	m.startTime = time.Now().Add(-2 * time.Minute)
+	if !m.refreshLimitReached() {
+		t.Error("expected duration limit reached after it elapsed")
+	}
+}
+
+func TestStreamDoneStopsRefreshAtLimit(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", RefreshInterval: time.Second, MaxRuns: 1})
+	m.runCount = 1
+	m.streaming = true
+	m.streamResult = &runner.StreamingResult{Lines: &[]runner.Line{}, Done: true}
+
+	_, cmd := m.Update(streamTickMsg{generation: m.refreshGeneration})
+	if cmd != nil {
+		t.Error("expected no refresh tick once the run limit is reached")
+	}
+	if !m.refreshStopped {
+		t.Error("expected refreshStopped true")
+	}
+}
+
+func TestTickExitsAtLimit(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", RefreshInterval: time.Second, RunFor: time.Second, ExitOnLimit: true})
+	m.startTime = time.Now().Add(-time.Minute)
+
+	_, cmd := m.Update(tickMsg{generation: m.refreshGeneration})
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected tea.QuitMsg when exiting on limit")
+	}
+}
+
+func TestNextRefreshDelay(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 10, 0, time.UTC)
+
+	m := testModel(Config{Command: "echo test", Shell: "sh", RefreshInterval: 30 * time.Second})
+	if got := m.nextRefreshDelay(now); got != 30*time.Second {
+		t.Errorf("expected full interval without precise mode, got %v", got)
+	}
+
+	m.config.Precise = true
+	if got := m.nextRefreshDelay(now); got != 20*time.Second {
+		t.Errorf("expected 20s until the :30 boundary, got %v", got)
+	}
+
+	now = now.Add(20 * time.Second) // exactly on a boundary
+	if got := m.nextRefreshDelay(now); got != 30*time.Second {
+		t.Errorf("expected a full interval when on a boundary, got %v", got)
+	}
+}
+
+func TestScheduleRefreshPreciseCountdown(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", RefreshInterval: time.Hour, Precise: true})
+	if cmd := m.scheduleRefresh(); cmd == nil {
+		t.Fatal("expected refresh commands")
+	}
+	// The countdown should end on the next hour boundary, not an hour from now
+	end := m.refreshStartTime.Add(time.Hour)
+	if end.Sub(end.Truncate(time.Hour)) > time.Second {
+		t.Errorf("expected countdown to end on an hour boundary, got %v", end)
+	}
+}
+
+func TestNextRefreshDelayJitter(t *testing.T) {
+	m := testModel(Config{Command: "echo test", Shell: "sh", RefreshInterval: 10 * time.Second, RefreshJitter: time.Second})
+	now := time.Now()
+	for range 50 {
+		got := m.nextRefreshDelay(now)
+		if got < 10*time.Second || got >= 11*time.Second {
+			t.Fatalf("expected delay in [10s, 11s), got %v", got)
+		}
+	}
+}
+
+func TestAutoPreview(t *testing.T) {
+	m := testModelWithLines()
+	m.config.AutoPreview = true
+	m.width = 30
+	m.lines = append(m.lines, runner.Line{Number: 5, Content: strings.Repeat("x", 40)})
+	m.updateFiltered()
+
+	// Short line: preview stays closed
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	if m.showPreview {
+		t.Error("expected preview closed for a line that fits")
+	}
+
+	// Long line: preview opens automatically
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if !m.showPreview {
+		t.Error("expected preview to open for a truncated line")
+	}
+
+	// Back to a short line: preview closes again
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if m.showPreview {
+		t.Error("expected auto-opened preview to close for a line that fits")
+	}
+}
+
+func TestAutoPreviewRespectsManualClose(t *testing.T) {
+	m := testModelWithLines()
+	m.config.AutoPreview = true
+	m.width = 30
+	m.lines = append(m.lines, runner.Line{Number: 5, Content: strings.Repeat("x", 40)})
+	m.updateFiltered()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if m.showPreview {
+		t.Error("expected preview closed after pressing p")
+	}
+
+	// Further updates on the same line must not reopen it
+	m.Update(clearStatusMsg{})
+	if m.showPreview {
+		t.Error("expected manually closed preview to stay closed on the same line")
+	}
+}
+
+func TestMinIntervalDefersAndCoalescesRuns(t *testing.T) {
+	m := testModelWithCancel()
+	m.config.MinInterval = time.Hour
+
+	m.actionReload()
+	if m.runCount != 1 {
+		t.Fatalf("expected first reload to run, got %d runs", m.runCount)
+	}
+
+	_, cmd := m.actionReload()
+	if m.runCount != 1 || !m.runDeferred {
+		t.Errorf("expected second reload to be deferred, got %d runs (deferred %v)", m.runCount, m.runDeferred)
+	}
+	if cmd == nil {
+		t.Error("expected a command scheduling the deferred run")
+	}
+	if !strings.Contains(m.statusMsg, "Rate limited") {
+		t.Errorf("expected rate limit status, got %q", m.statusMsg)
+	}
+
+	if _, cmd := m.actionReload(); cmd != nil || m.runCount != 1 {
+		t.Error("expected third reload to be coalesced into the deferred run")
+	}
+
+	m.Update(deferredRunMsg{})
+	if m.runCount != 2 || m.runDeferred {
+		t.Errorf("expected deferred run to start, got %d runs (deferred %v)", m.runCount, m.runDeferred)
+	}
+
+	m.Update(deferredRunMsg{})
+	if m.runCount != 2 {
+		t.Errorf("expected stale deferred message to be ignored, got %d runs", m.runCount)
+	}
+	m.cancel()
+}
+
+func TestMinIntervalAllowsRunAfterFloor(t *testing.T) {
+	m := testModelWithCancel()
+	m.config.MinInterval = time.Millisecond
+	m.actionReload()
+	time.Sleep(5 * time.Millisecond)
+	m.actionReload()
+	if m.runCount != 2 || m.runDeferred {
+		t.Errorf("expected reload after the floor to run, got %d runs (deferred %v)", m.runCount, m.runDeferred)
+	}
+	m.cancel()
+}
+
+func TestRefreshKeepsCursorOnSameLine(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.anchor != "hello foo" {
+		t.Fatalf("expected the selected line to be remembered, got %q", m.anchor)
+	}
+
+	// Two new lines at the top push the inspected line down
+	lines := []runner.Line{
+		{Number: 1, Content: "new one"},
+		{Number: 2, Content: "new two"},
+		{Number: 3, Content: "hello world"},
+		{Number: 4, Content: "foo bar"},
+		{Number: 5, Content: "hello foo"},
+		{Number: 6, Content: "baz qux"},
+	}
+	m.streaming = true
+	m.streamResult = &runner.StreamingResult{Lines: &lines, Done: true, CurrentLineCount: len(lines)}
+	m.Update(streamTickMsg{generation: m.refreshGeneration})
+
+	if m.cursor != 4 {
+		t.Errorf("expected the cursor to follow %q to index 4, got %d", m.anchor, m.cursor)
+	}
+}
+
+func TestRefreshKeepsIndexWhenLineGone(t *testing.T) {
+	m := testModelWithLines()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+
+	lines := []runner.Line{
+		{Number: 1, Content: "a"},
+		{Number: 2, Content: "b"},
+		{Number: 3, Content: "c"},
+	}
+	m.streaming = true
+	m.streamResult = &runner.StreamingResult{Lines: &lines, Done: true, CurrentLineCount: len(lines)}
+	m.Update(streamTickMsg{generation: m.refreshGeneration})
+	if m.cursor != 1 {
+		t.Errorf("expected the cursor to stay put when its line is gone, got %d", m.cursor)
+	}
+}
+
+func TestInitialFilter(t *testing.T) {
+	m := testModel(Config{Command: "journalctl", Shell: "sh", Filter: "ERROR"})
+	m.width, m.height = 80, 30
+
+	lines := []runner.Line{
+		{Number: 1, Content: "INFO started"},
+		{Number: 2, Content: "ERROR failed"},
+		{Number: 3, Content: "ERROR again"},
+	}
+	m.streaming = true
+	m.streamResult = &runner.StreamingResult{Lines: &lines, Done: true, CurrentLineCount: len(lines)}
+	m.Update(streamTickMsg{generation: m.refreshGeneration})
+	if len(m.filtered) != 2 || m.filterInput.Text != "ERROR" {
+		t.Errorf("expected the initial filter to match 2 lines, got %v with %q", m.filtered, m.filterInput.Text)
+	}
+}
+
+func TestPreviewOpen(t *testing.T) {
+	if m := testModel(Config{Command: "ls", Shell: "sh"}); m.showPreview {
+		t.Error("expected the preview to start closed")
+	}
+	if m := testModel(Config{Command: "ls", Shell: "sh", PreviewOpen: true}); !m.showPreview {
+		t.Error("expected PreviewOpen to start with the preview open")
+	}
+}
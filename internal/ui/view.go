@@ -2,34 +2,37 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"runtime"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
 )
 
 // renderCmdPaletteOverlay creates the command palette overlay box
 func (m model) renderCmdPaletteOverlay() (box string, boxWidth, boxHeight int) {
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")) // dim
+		Foreground(m.config.Theme.Muted) // dim
 
 	nameStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(m.config.Theme.Text)
 
 	selectedNameStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("15")).
-		Foreground(lipgloss.Color("#000000")).
+		Background(m.config.Theme.Selection).
+		Foreground(m.config.Theme.SelectionText).
 		Bold(true)
 
 	selectedKeyStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("15")).
-		Foreground(lipgloss.Color("241"))
+		Background(m.config.Theme.Selection).
+		Foreground(m.config.Theme.Muted)
 
 	filterStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("11"))
+		Foreground(m.config.Theme.Filter)
 
-	borderColor := lipgloss.Color("12")
+	borderColor := m.config.Theme.Header
 
 	allCommands := commands()
 	filtered := m.filteredCommands()
@@ -49,7 +52,7 @@ func (m model) renderCmdPaletteOverlay() (box string, boxWidth, boxHeight int) {
 		filterLine += strings.Repeat(" ", paletteWidth-filterVisual)
 	}
 	content.WriteString(filterLine + "\n")
-	content.WriteString(lipgloss.NewStyle().Foreground(borderColor).Render(strings.Repeat("─", paletteWidth)) + "\n")
+	content.WriteString(lipgloss.NewStyle().Foreground(borderColor).Render(strings.Repeat(m.config.Border.Horizontal, paletteWidth)) + "\n")
 
 	// Command list (fixed number of rows)
 	for i := range totalSlots {
@@ -68,7 +71,7 @@ func (m model) renderCmdPaletteOverlay() (box string, boxWidth, boxHeight int) {
 	}
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(m.config.Border.lipgloss()).
 		BorderForeground(borderColor)
 
 	box = boxStyle.Render(content.String())
@@ -82,48 +85,79 @@ func (m model) renderCmdPaletteOverlay() (box string, boxWidth, boxHeight int) {
 func (m model) renderHelpOverlay() (box string, boxWidth, boxHeight int) {
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("10")) // green
+		Foreground(m.config.Theme.Status) // green
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")) // light gray
+		Foreground(m.config.Theme.Text) // light gray
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("12")) // blue
+		Foreground(m.config.Theme.Header) // blue
 
 	// Define keybindings
 	bindings := []struct {
 		key  string
-		desc string
+		desc string // message key
 	}{
-		{"j / k", "Move down / up"},
-		{"g / G", "Go to first / last line"},
-		{"Ctrl+d / Ctrl+u", "Half page down / up"},
-		{"PgDn / PgUp", "Full page down / up"},
-		{"Ctrl+f / Ctrl+b", "Full page down / up"},
+		{"j / k", "help.move"},
+		{"15j, 3G", "help.count"},
+		{"g / G", "help.first_last"},
+		{"Ctrl+d / Ctrl+u", "help.half_page"},
+		{"PgDn / PgUp", "help.full_page"},
+		{"Ctrl+f / Ctrl+b", "help.full_page"},
 		{"", ""},
-		{"p", "Toggle preview pane"},
-		{"+/-", "Resize preview pane"},
-		{"J / K", "Scroll preview down / up"},
-		{"/", "Enter filter mode"},
-		{"//", "Toggle regex filter mode"},
-		{"Esc", "Exit filter / clear"},
+		{"p", "help.preview"},
+		{"x", "help.context_preview"},
+		{"w", "help.wrap"},
+		{"#", "help.line_numbers"},
+		{"+/- or > / <", "help.preview_size"},
+		{"W", "help.preview_wrap"},
+		{"J / K", "help.preview_scroll"},
+		{"/", "help.filter"},
+		{"//", "help.regex_filter"},
+		{"Tab", "help.filter_kind"},
+		{"Ctrl+x", "help.edit_filter"},
+		{"|", "help.pipe"},
+		{"e / Enter", "help.open_file"},
+		{"Ctrl+p", "help.pin_filter"},
+		{"P", "help.pop_filter"},
+		{"Esc", "help.exit_filter"},
+		{"?", "help.search"},
+		{"n / N", "help.search_next"},
+		{"C / Alt+c", "help.case"},
 		{"", ""},
-		{"r / Ctrl+r", "Reload command"},
-		{"R", "Reload & clear lines"},
-		{"d / Del", "Delete selected line"},
-		{"D", "Clear all lines"},
-		{"c", "Stop running command"},
-		{"y", "Copy line to clipboard"},
-		{"Y", "Copy line (plain text)"},
-		{":", "Open command palette"},
-		{"q / Esc", "Quit"},
-		{"?", "Toggle this help"},
+		{"r / Ctrl+r", "help.reload"},
+		{"R", "help.reload_clear"},
+		{"d / Del", "help.delete_line"},
+		{"D", "help.clear_lines"},
+		{"c", "help.stop"},
+		{"Tab / S-Tab", "help.mark"},
+		{"y", "help.copy"},
+		{"Y", "help.copy_plain"},
+		{"M", "help.copy_markdown"},
+		{"V", "help.copy_rendered"},
+		{"u", "help.dedup"},
+		{"t", "help.table"},
+		{"T", "help.header"},
+		{"Ctrl+t", "help.title_line"},
+		{"H / L", "help.scroll_columns"},
+		{"O", "help.columns"},
+		{"f", "help.yank_field"},
+		{"Ctrl+y / Alt+y", "help.copy_output"},
+		{"\"ay / \"ap", "help.registers"},
+		{"\"\"", "help.show_registers"},
+		{"Ctrl+s, :w <path>", "help.write"},
+		{"[ / ]", "help.replay"},
+		{"a", "help.annotate"},
+		{"A", "help.next_note"},
+		{":", "help.palette"},
+		{"q / Esc", "help.quit"},
+		{"h / F1", "help.help"},
 	}
 
 	// Build content
 	var content strings.Builder
-	content.WriteString(titleStyle.Render("Keybindings"))
+	content.WriteString(titleStyle.Render(i18n.T("help.title")))
 	content.WriteString("\n\n")
 
 	for _, b := range bindings {
@@ -132,17 +166,17 @@ func (m model) renderHelpOverlay() (box string, boxWidth, boxHeight int) {
 			continue
 		}
 		key := keyStyle.Render(fmt.Sprintf("%-18s", b.key))
-		desc := descStyle.Render(b.desc)
+		desc := descStyle.Render(i18n.T(b.desc))
 		fmt.Fprintf(&content, "  %s  %s\n", key, desc)
 	}
 
 	content.WriteString("\n")
-	content.WriteString(descStyle.Render("Press any key to close"))
+	content.WriteString(descStyle.Render(i18n.T("help.close")))
 
 	// Create box style
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("12")).
+		Border(m.config.Border.lipgloss()).
+		BorderForeground(m.config.Theme.Header).
 		Padding(1, 2)
 
 	box = boxStyle.Render(content.String())
@@ -155,11 +189,11 @@ func (m model) renderHelpOverlay() (box string, boxWidth, boxHeight int) {
 // renderConfirmOverlay creates a confirmation dialog overlay
 func (m model) renderConfirmOverlay() (box string, boxWidth, boxHeight int) {
 	msgStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(m.config.Theme.Text)
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("11")).
+		Border(m.config.Border.lipgloss()).
+		BorderForeground(m.config.Theme.Filter).
 		Padding(1, 2)
 
 	content := msgStyle.Render(m.confirmMessage)
@@ -172,7 +206,14 @@ func (m model) renderConfirmOverlay() (box string, boxWidth, boxHeight int) {
 
 func (m *model) View() string {
 	if m.width == 0 || m.height == 0 {
-		return spinnerFrames[m.spinnerFrame] + " Running command…"
+		if m.idle {
+			return i18n.T("view.waiting")
+		}
+		return spinnerFrames[m.spinnerFrame] + " " + i18n.T("bar.running")
+	}
+
+	if m.tooSmall() {
+		return m.renderTooSmall()
 	}
 
 	// Render the main UI
@@ -184,6 +225,16 @@ func (m *model) View() string {
 		return overlayBox(mainView, box, boxWidth, boxHeight, m.width, m.height)
 	}
 
+	if m.showRegisters {
+		box, boxWidth, boxHeight := m.renderRegistersOverlay()
+		return overlayBox(mainView, box, boxWidth, boxHeight, m.width, m.height)
+	}
+
+	if m.columnsMode {
+		box, boxWidth, boxHeight := m.renderColumnsOverlay()
+		return overlayBox(mainView, box, boxWidth, boxHeight, m.width, m.height)
+	}
+
 	// Overlay command palette if active
 	if m.cmdPaletteMode {
 		box, boxWidth, boxHeight := m.renderCmdPaletteOverlay()
@@ -199,31 +250,22 @@ func (m *model) View() string {
 	return mainView
 }
 
-// Box drawing characters (rounded)
-const (
-	boxTopLeft     = "╭"
-	boxTopRight    = "╮"
-	boxBottomLeft  = "╰"
-	boxBottomRight = "╯"
-	boxHorizontal  = "─"
-	boxVertical    = "│"
-	boxLeftT       = "├"
-	boxRightT      = "┤"
-	boxTopT        = "┬"
-	boxBottomT     = "┴"
-)
-
 // viewContext holds shared rendering state for a single View() call.
 type viewContext struct {
 	innerWidth  int
+	border      Border
 	borderStyle lipgloss.Style
+	compact     bool // no box around the view, only separators inside it
 }
 
 func (vc viewContext) hLine(left, right string, splitPos int, junction string) string {
+	if vc.compact {
+		left, right = "", ""
+	}
 	if splitPos > 0 && splitPos < vc.innerWidth {
-		return vc.borderStyle.Render(left + strings.Repeat(boxHorizontal, splitPos) + junction + strings.Repeat(boxHorizontal, vc.innerWidth-splitPos-1) + right)
+		return vc.borderStyle.Render(left + strings.Repeat(vc.border.Horizontal, splitPos) + junction + strings.Repeat(vc.border.Horizontal, vc.innerWidth-splitPos-1) + right)
 	}
-	return vc.borderStyle.Render(left + strings.Repeat(boxHorizontal, vc.innerWidth) + right)
+	return vc.borderStyle.Render(left + strings.Repeat(vc.border.Horizontal, vc.innerWidth) + right)
 }
 
 func (vc viewContext) padLine(content string) string {
@@ -233,33 +275,44 @@ func (vc viewContext) padLine(content string) string {
 	} else if contentWidth > vc.innerWidth {
 		content = lipgloss.NewStyle().MaxWidth(vc.innerWidth-1).Render(content) + ellipsis
 	}
-	return vc.borderStyle.Render(boxVertical) + content + vc.borderStyle.Render(boxVertical)
+	return vc.side() + content + vc.side()
+}
+
+// side returns the box's left or right border, which compact mode leaves
+// out.
+func (vc viewContext) side() string {
+	if vc.compact {
+		return ""
+	}
+	return vc.borderStyle.Render(vc.border.Vertical)
 }
 
 func (m model) renderMainView() string {
-	borderColor := lipgloss.Color("240")
+	borderColor := m.config.Theme.Border
 	vc := viewContext{
-		innerWidth:  m.width - 2,
+		innerWidth:  m.innerWidth(),
+		border:      m.config.Border,
 		borderStyle: lipgloss.NewStyle().Foreground(borderColor),
+		compact:     m.config.Compact,
 	}
 
 	commandLine := m.renderHeaderLine(vc.innerWidth)
 	promptLine := m.renderPromptLine()
 	listHeight, listWidth := m.listDimensions(vc.innerWidth)
-	listLines := m.renderListLines(listHeight, listWidth)
+	listLines := m.withScrollbar(m.renderListLines(listHeight, listWidth), listWidth)
+	if header := m.renderHeaderRows(listWidth); len(header) > 0 {
+		listLines = append(header, listLines...)
+		listHeight += len(header)
+	}
 
-	// Preview content
 	var previewContent string
-	if m.showPreview && len(m.filtered) > 0 && m.cursor >= 0 && m.cursor < len(m.filtered) {
-		idx := m.filtered[m.cursor]
-		if idx < len(m.lines) {
-			previewContent = highlightJSON(m.lines[idx].Content)
-		}
+	if m.showPreview {
+		previewContent = m.previewContent()
 	}
 
 	// Error message
 	if m.errorMsg != "" {
-		listLines = append(listLines, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("Error: "+m.errorMsg))
+		listLines = append(listLines, lipgloss.NewStyle().Foreground(m.config.Theme.Error).Render(i18n.T("error.prefix", m.errorMsg)))
 	}
 
 	// Vertical split position for left/right preview
@@ -275,9 +328,17 @@ func (m model) renderMainView() string {
 
 	// Build the unified box
 	var lines []string
-	lines = append(lines, vc.hLine(boxTopLeft, boxTopRight, 0, boxTopT))
-	lines = append(lines, vc.padLine(commandLine))
-	lines = append(lines, vc.hLine(boxLeftT, boxRightT, vSplitPos, boxTopT))
+	switch {
+	case m.hideTitle && !vc.compact:
+		lines = append(lines, vc.hLine(vc.border.TopLeft, vc.border.TopRight, vSplitPos, vc.border.TopT))
+	case m.hideTitle:
+	case vc.compact:
+		lines = append(lines, vc.padLine(commandLine))
+	default:
+		lines = append(lines, vc.hLine(vc.border.TopLeft, vc.border.TopRight, 0, vc.border.TopT))
+		lines = append(lines, vc.padLine(commandLine))
+		lines = append(lines, vc.hLine(vc.border.LeftT, vc.border.RightT, vSplitPos, vc.border.TopT))
+	}
 
 	// Content area
 	if !m.showPreview {
@@ -286,94 +347,77 @@ func (m model) renderMainView() string {
 		lines = append(lines, m.renderContentWithPreview(vc, listLines, listHeight, previewContent)...)
 	}
 
-	lines = append(lines, vc.hLine(boxBottomLeft, boxBottomRight, vSplitPos, boxBottomT))
+	if !vc.compact {
+		lines = append(lines, vc.hLine(vc.border.BottomLeft, vc.border.BottomRight, vSplitPos, vc.border.BottomT))
+	}
 
 	return strings.Join(lines, "\n") + "\n" + promptLine
 }
 
 func (m model) renderHeaderLine(innerWidth int) string {
-	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Header).Bold(true)
 	prefix := titleStyle.Render("watchr") + " • "
 
+	command := m.config.Command
+	if target := m.runner.Target(); target != "" {
+		command = target + ": " + command
+	}
+
 	var commandLine string
 	switch {
+	case m.config.HeaderTemplate != "":
+		commandLine = m.expandHeaderTemplate(command)
 	case m.streaming:
-		streamStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
-		commandLine = prefix + streamStyle.Render("◉ "+m.config.Command)
-	case m.loading:
-		commandLine = prefix + m.config.Command
+		streamStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Prompt)
+		commandLine = prefix + streamStyle.Render("◉ "+command)
+	case m.loading || m.idle:
+		commandLine = prefix + command
 	case m.exitCode == 0:
-		successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-		commandLine = prefix + successStyle.Render("✓ "+m.config.Command)
+		successStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Status)
+		commandLine = prefix + successStyle.Render("✓ "+command)
 	default:
-		failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
-		commandLine = prefix + failStyle.Render(fmt.Sprintf("✗ [%d] %s", m.exitCode, m.config.Command))
-	}
-
-	if m.config.RefreshInterval > time.Second && !m.streaming && !m.refreshStartTime.IsZero() {
-		elapsed := time.Since(m.refreshStartTime)
-		remaining := m.config.RefreshInterval - elapsed
-		if remaining > 0 {
-			countdownStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-			countdown := countdownStyle.Render(fmt.Sprintf("(%ds)", int(remaining.Seconds())+1))
-			cmdWidth := lipgloss.Width(commandLine)
-			countdownWidth := lipgloss.Width(countdown)
-			gap := innerWidth - cmdWidth - countdownWidth
-			if gap > 0 {
-				commandLine += strings.Repeat(" ", gap) + countdown
-			}
-		}
+		failStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Error)
+		commandLine = prefix + failStyle.Render(fmt.Sprintf("✗ [%d] %s", m.exitCode, command))
 	}
 
-	return commandLine
-}
-
-func (m model) renderPromptLine() string {
-	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
-	filterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
-	filterRegexStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
-	filterErrStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
-
-	var promptLine string
-	switch {
-	case m.filterMode && m.filterRegex:
-		label := filterRegexStyle.Render("regex/")
-		before, block, after := m.filterInput.render()
-		input := filterStyle.Render(before) + block + filterStyle.Render(after)
-		promptLine = label + input
-		if m.filterRegexErr != nil {
-			promptLine += " " + filterErrStyle.Render("(invalid regex)")
-		}
-	case m.filterMode:
-		before, block, after := m.filterInput.render()
-		promptLine = filterStyle.Render("/"+before) + block + filterStyle.Render(after)
-	case m.filterInput.Text != "" && m.filterRegex:
-		promptLine = promptStyle.Render(fmt.Sprintf("%s (regex: %s)", m.config.Prompt, m.filterInput.Text))
-	case m.filterInput.Text != "":
-		promptLine = promptStyle.Render(fmt.Sprintf("%s (filter: %s)", m.config.Prompt, m.filterInput.Text))
-	default:
-		promptLine = promptStyle.Render(m.config.Prompt)
+	if m.replaying() {
+		replayStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Accent)
+		commandLine += " " + replayStyle.Render(m.replayLabel())
 	}
 
-	if m.streaming {
-		promptLine += " " + spinnerFrames[m.spinnerFrame] + " Streaming…"
-	} else if m.loading {
-		promptLine += " " + spinnerFrames[m.spinnerFrame] + " Running command…"
+	if m.binary {
+		binaryStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Accent)
+		commandLine += " " + binaryStyle.Render(i18n.T("view.binary"))
 	}
-	if m.statusMsg != "" {
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-		promptLine += " " + statusStyle.Render(m.statusMsg)
+
+	if m.anomaly != "" && !m.streaming {
+		anomalyStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Warning).Bold(true)
+		commandLine += " " + anomalyStyle.Render(i18n.T("view.anomaly", m.anomaly))
 	}
 
-	helpHint := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("? for help")
-	promptWidth := lipgloss.Width(promptLine)
-	hintWidth := lipgloss.Width(helpHint)
-	gap := m.width - promptWidth - hintWidth
-	if gap > 0 {
-		promptLine += strings.Repeat(" ", gap) + helpHint
+	if m.refreshStopped && !m.streaming {
+		stoppedStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+		stopped := stoppedStyle.Render(i18n.T("view.refresh_stopped"))
+		gap := innerWidth - lipgloss.Width(commandLine) - lipgloss.Width(stopped)
+		if gap > 0 {
+			commandLine += strings.Repeat(" ", gap) + stopped
+		}
 	}
 
-	return promptLine
+	return commandLine
+}
+
+// countLabel returns the cursor position among the visible lines, and how
+// many lines the filter kept out of the whole output when it hides any.
+func (m model) countLabel() string {
+	pos := 0
+	if len(m.filtered) > 0 {
+		pos = m.cursor + 1
+	}
+	if len(m.filtered) != len(m.lines) {
+		return i18n.T("view.count_total", pos, len(m.filtered), len(m.lines))
+	}
+	return fmt.Sprintf("%d/%d", pos, len(m.lines))
 }
 
 func (m model) listDimensions(innerWidth int) (height, width int) {
@@ -387,16 +431,19 @@ func (m model) listDimensions(innerWidth int) (height, width int) {
 
 func (m model) renderListLines(listHeight, listWidth int) []string {
 	selectedStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("15")).
-		Foreground(lipgloss.Color("#000000")).
+		Background(m.config.Theme.Selection).
+		Foreground(m.config.Theme.SelectionText).
 		Bold(true)
-	lineNumStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if m.wrapLines {
+		return m.renderWrappedListLines(listHeight, listWidth)
+	}
+	locators := m.matchLocators()
 
 	var listLines []string
 	for i := range listHeight {
 		lineIdx := m.offset + i
 		if lineIdx >= len(m.filtered) {
-			listLines = append(listLines, "")
+			listLines = append(listLines, m.fillerRow())
 			continue
 		}
 
@@ -408,41 +455,50 @@ func (m model) renderListLines(listHeight, listWidth int) []string {
 		line := m.lines[idx]
 		isSelected := lineIdx == m.cursor
 		fullWidth := listWidth + 1
+		var ranges [][]int
+		if len(locators) > 0 {
+			ranges = locateMatches(stripANSI(m.displayed(line.Content)), locators)
+		}
 
 		var lineText string
 		if m.config.ShowLineNums {
-			lineNumStr := fmt.Sprintf("%*d  ", m.config.LineNumWidth, line.Number)
-			lineNumWidth := len(lineNumStr)
+			lineNumStr := m.lineGutter(line)
+			lineNumWidth := lipgloss.Width(lineNumStr)
 			contentWidth := listWidth - lineNumWidth
-			content := truncateToWidth(line.Content, contentWidth)
+			content := truncateWith(m.displayed(line.Content), contentWidth, m.config.Ellipsis)
+			content = m.highlightChange(line, content)
 
 			if isSelected {
 				plainContent := stripANSI(content)
 				selectedLineNumStyle := lipgloss.NewStyle().
-					Background(lipgloss.Color("15")).
-					Foreground(lipgloss.Color("241"))
+					Background(m.config.Theme.Selection).
+					Foreground(m.config.Theme.LineNumber)
 				selectedContentStyle := lipgloss.NewStyle().
-					Background(lipgloss.Color("15")).
-					Foreground(lipgloss.Color("#000000")).
+					Background(m.config.Theme.Selection).
+					Foreground(m.config.Theme.SelectionText).
 					Bold(true)
 				contentPadded := plainContent
-				padding := fullWidth - lineNumWidth - len(plainContent)
+				padding := fullWidth - lineNumWidth - textWidth(plainContent)
 				if padding > 0 {
 					contentPadded = plainContent + strings.Repeat(" ", padding)
 				}
-				lineText = selectedLineNumStyle.Render(lineNumStr) + selectedContentStyle.Render(contentPadded)
+				lineText = selectedLineNumStyle.Render(lineNumStr) + selectedContentStyle.Render(highlightRanges(contentPadded, ranges, 0))
 			} else {
-				lineText = lineNumStyle.Render(lineNumStr) + content
+				lineText = m.gutterStyle(line.Number).Render(lineNumStr) + highlightRanges(m.dimByAge(line, m.levelColored(line, content)), ranges, 0)
 			}
 		} else {
-			lineText = truncateToWidth(line.Content, listWidth)
+			gutter := m.markerGutter(line)
+			lineText = truncateWith(gutter+m.displayed(line.Content), listWidth, m.config.Ellipsis)
+			lineText = m.highlightChange(line, lineText)
 			if isSelected {
 				lineText = stripANSI(lineText)
-				padding := fullWidth - len(lineText)
+				padding := fullWidth - textWidth(lineText)
 				if padding > 0 {
 					lineText += strings.Repeat(" ", padding)
 				}
-				lineText = selectedStyle.Render(lineText)
+				lineText = selectedStyle.Render(highlightRanges(lineText, ranges, -len(gutter)))
+			} else {
+				lineText = highlightRanges(m.dimByAge(line, m.levelColored(line, lineText)), ranges, -len(gutter))
 			}
 		}
 
@@ -451,6 +507,62 @@ func (m model) renderListLines(listHeight, listWidth int) []string {
 	return listLines
 }
 
+// renderedRows returns a line's rows exactly as they appear in the list
+// (line number gutter and truncation included), without ANSI styling.
+func (m model) renderedRows(line runner.Line) []string {
+	_, listWidth := m.listDimensions(m.innerWidth())
+	if m.wrapLines {
+		gutter := m.listGutter(line)
+		indent := strings.Repeat(" ", lipgloss.Width(gutter)) + m.config.WrapMarker
+		rows := m.wrappedRows(line, listWidth)
+		for i, row := range rows {
+			if i == 0 {
+				rows[i] = stripANSI(gutter + row)
+			} else {
+				rows[i] = stripANSI(indent + row)
+			}
+		}
+		return rows
+	}
+	if !m.config.ShowLineNums {
+		return []string{stripANSI(truncateWith(m.markerGutter(line)+m.displayed(line.Content), listWidth, m.config.Ellipsis))}
+	}
+	lineNumStr := m.lineGutter(line)
+	content := truncateWith(m.displayed(line.Content), listWidth-lipgloss.Width(lineNumStr), m.config.Ellipsis)
+	return []string{stripANSI(lineNumStr + content)}
+}
+
+// lineGutter returns the line number column for line, with note and mark
+// markers in the spacing after the number when the line is annotated or
+// marked.
+func (m model) lineGutter(line runner.Line) string {
+	note, mark := " ", " "
+	if m.noteFor(line.Content) != "" {
+		note = noteMarker
+	}
+	if m.isMarked(line.Number) {
+		mark = markMarker
+	} else if m.isNew(line.Number) {
+		mark = newMarker
+	}
+	return m.lineNumbers(line) + note + mark + m.dupGutter(line)
+}
+
+// markerGutter returns the mark and note markers and duplicate count shown
+// before lines when line numbers are hidden.
+func (m model) markerGutter(line runner.Line) string {
+	var gutter string
+	if m.isMarked(line.Number) {
+		gutter += markMarker + " "
+	} else if m.isNew(line.Number) {
+		gutter += newMarker + " "
+	}
+	if m.noteFor(line.Content) != "" {
+		gutter += noteMarker + " "
+	}
+	return gutter + m.dupGutter(line)
+}
+
 func (m model) renderContentNoPreview(vc viewContext, listLines []string, listHeight int) []string {
 	var lines []string
 	for i := range listHeight {
@@ -478,9 +590,9 @@ func (m *model) renderVerticalPreview(vc viewContext, listLines []string, listHe
 
 	var previewLines []string
 	if previewContent != "" {
-		previewLines = wrapPreviewContent(previewContent, vc.innerWidth)
+		previewLines = m.previewRows(previewContent, vc.innerWidth)
 	}
-	previewLines = m.applyPreviewOffset(previewLines, previewH)
+	previewLines = m.applyPreviewOffset(previewLines, previewH, vc.innerWidth)
 	for len(previewLines) < previewH {
 		previewLines = append(previewLines, "")
 	}
@@ -491,7 +603,9 @@ func (m *model) renderVerticalPreview(vc viewContext, listLines []string, listHe
 		paddedPreview = append(paddedPreview, vc.padLine(line))
 	}
 
-	separator := vc.hLine(boxLeftT, boxRightT, 0, boxTopT)
+	sepContext := vc
+	sepContext.borderStyle = m.dividerStyle(vc)
+	separator := sepContext.hLine(vc.border.LeftT, vc.border.RightT, 0, vc.border.TopT)
 
 	if m.config.PreviewPosition == PreviewTop {
 		result := paddedPreview
@@ -506,6 +620,15 @@ func (m *model) renderVerticalPreview(vc viewContext, listLines []string, listHe
 	return result
 }
 
+// dividerStyle returns the style of the border between the list and the
+// preview, which is highlighted while the preview has focus.
+func (m model) dividerStyle(vc viewContext) lipgloss.Style {
+	if m.previewFocused {
+		return lipgloss.NewStyle().Foreground(m.config.Theme.Prompt)
+	}
+	return vc.borderStyle
+}
+
 func (m *model) renderHorizontalPreview(vc viewContext, listLines []string, listHeight int, previewContent string) []string {
 	var leftW, rightW int
 	if m.config.PreviewPosition == PreviewLeft {
@@ -516,15 +639,15 @@ func (m *model) renderHorizontalPreview(vc viewContext, listLines []string, list
 		leftW = vc.innerWidth - rightW - 1
 	}
 
+	previewW := leftW
+	if m.config.PreviewPosition == PreviewRight {
+		previewW = rightW
+	}
 	var previewLines []string
 	if previewContent != "" {
-		previewW := leftW
-		if m.config.PreviewPosition == PreviewRight {
-			previewW = rightW
-		}
-		previewLines = wrapPreviewContent(previewContent, previewW)
+		previewLines = m.previewRows(previewContent, previewW)
 	}
-	previewLines = m.applyPreviewOffset(previewLines, listHeight)
+	previewLines = m.applyPreviewOffset(previewLines, listHeight, previewW)
 	for len(previewLines) < listHeight {
 		previewLines = append(previewLines, "")
 	}
@@ -532,12 +655,13 @@ func (m *model) renderHorizontalPreview(vc viewContext, listLines []string, list
 	fitToWidth := func(s string, w int, isPreview bool) string {
 		sw := lipgloss.Width(s)
 		if sw > w {
-			if isPreview {
-				return s + strings.Repeat(" ", w-sw)
+			if !isPreview {
+				return lipgloss.NewStyle().MaxWidth(w-1).Render(s) + ellipsis
 			}
-			return lipgloss.NewStyle().MaxWidth(w-1).Render(s) + ellipsis
+			s = truncateWith(s, w, "")
+			sw = lipgloss.Width(s)
 		}
-		return s + strings.Repeat(" ", w-sw)
+		return s + strings.Repeat(" ", max(w-sw, 0))
 	}
 
 	var lines []string
@@ -562,21 +686,53 @@ func (m *model) renderHorizontalPreview(vc viewContext, listLines []string, list
 		leftContent = fitToWidth(leftContent, leftW, leftIsPreview)
 		rightContent = fitToWidth(rightContent, rightW, rightIsPreview)
 
-		line := vc.borderStyle.Render(boxVertical) + leftContent + vc.borderStyle.Render(boxVertical) + rightContent + vc.borderStyle.Render(boxVertical)
+		line := vc.side() + leftContent + m.dividerStyle(vc).Render(vc.border.Vertical) + rightContent + vc.side()
 		lines = append(lines, line)
 	}
 	return lines
 }
 
-// Run starts the UI
-func Run(cfg Config) error {
+// terminalPath returns the path of the controlling terminal's output.
+func terminalPath() string {
+	if runtime.GOOS == "windows" {
+		return "CONOUT$"
+	}
+	return "/dev/tty"
+}
+
+// Run starts the UI and returns the exit status watchr should exit with,
+// according to cfg.ExitCode.
+func Run(cfg Config) (int, error) {
 	if cfg.PreviewPosition == "" {
 		cfg.PreviewPosition = PreviewBottom
 	}
 
 	m := initialModel(cfg)
-	p := tea.NewProgram(&m, tea.WithAltScreen())
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	if cfg.PrintSelection {
+		// stdout is likely captured, e.g. by $(...), so draw on the terminal
+		tty, err := os.OpenFile(terminalPath(), os.O_WRONLY, 0)
+		if err != nil {
+			return 1, fmt.Errorf("--print-selection needs a terminal: %w", err)
+		}
+		defer func() { _ = tty.Close() }()
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(tty))
+		m.out = tty
+		opts = append(opts, tea.WithOutput(tty), tea.WithInputTTY())
+	}
+	p := tea.NewProgram(&m, opts...)
 
-	_, err := p.Run()
-	return err
+	if _, err := p.Run(); err != nil {
+		return 1, err
+	}
+	if cfg.PrintSelection {
+		if len(m.selection) == 0 {
+			return ExitAborted, nil
+		}
+		fmt.Println(strings.Join(m.selection, "\n"))
+	}
+	return m.quitExitCode(), nil
 }
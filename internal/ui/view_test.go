@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRenderHelpOverlay(t *testing.T) {
@@ -102,3 +103,78 @@ func TestViewWithCmdPalette(t *testing.T) {
 		t.Error("expected command palette in view")
 	}
 }
+
+func TestViewShowsSSHHost(t *testing.T) {
+	m := testModel(Config{Command: "uptime", Shell: "sh", SSH: "admin@server"})
+	m.width = 80
+	m.height = 20
+	if view := m.View(); !strings.Contains(view, "admin@server: uptime") {
+		t.Errorf("expected remote host in header, got %q", view)
+	}
+}
+
+func TestViewShowsContainer(t *testing.T) {
+	m := testModel(Config{Command: "ps", Shell: "sh", Docker: "web"})
+	m.width = 80
+	m.height = 20
+	if view := m.View(); !strings.Contains(view, "docker:web: ps") {
+		t.Errorf("expected container in header, got %q", view)
+	}
+}
+
+func TestPromptCountLabel(t *testing.T) {
+	m := testModelWithLines()
+	m.cursor = 1
+	if got := stripANSI(m.renderPromptLine()); !strings.Contains(got, "2/4  h for help") {
+		t.Errorf("expected the cursor position and line count, got %q", got)
+	}
+
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	if got := m.countLabel(); got != "2/2 (4 total)" {
+		t.Errorf("expected the match count out of the total, got %q", got)
+	}
+
+	m.filterInput.Text = "nothing"
+	m.updateFiltered()
+	if got := m.countLabel(); got != "0/0 (4 total)" {
+		t.Errorf("expected no position without matches, got %q", got)
+	}
+}
+
+func TestHeaderTemplate(t *testing.T) {
+	m := testModelWithLines()
+	m.config.HeaderTemplate = "{command} • exit {exit_code} in {duration}"
+	if got := stripANSI(m.renderHeaderLine(78)); got != "echo test • exit  in " {
+		t.Errorf("expected empty run placeholders before the first run, got %q", got)
+	}
+
+	m.finishedRuns = 1
+	m.lastExitCode = 2
+	m.lastRunDuration = 1500 * time.Millisecond
+	if got := stripANSI(m.renderHeaderLine(78)); got != "echo test • exit 2 in 1.5s" {
+		t.Errorf("expected the template expanded, got %q", got)
+	}
+}
+
+func TestPromptPlaceholders(t *testing.T) {
+	m := testModelWithLines()
+	m.config.Prompt = "[{exit_code}|{matches}|{interval}] > "
+	if got := stripANSI(m.renderPromptLine()); !strings.Contains(got, "[|4|] > ") {
+		t.Errorf("expected empty placeholders before the first run, got %q", got)
+	}
+
+	m.finishedRuns = 1
+	m.lastExitCode = 1
+	m.config.RefreshInterval = 2 * time.Second
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+	if got := stripANSI(m.renderPromptLine()); !strings.Contains(got, "[1|2|2s] > ") {
+		t.Errorf("expected the placeholders expanded, got %q", got)
+	}
+
+	m.config.Prompt = "{time} "
+	if !m.promptHasClock() || strings.Contains(stripANSI(m.renderPromptLine()), "{time}") {
+		t.Error("expected {time} to be expanded and redrawn every second")
+	}
+}
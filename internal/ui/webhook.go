@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/webhook"
+)
+
+// webhookCmd compares the finished run with the previous one and posts a
+// summary to WebhookURL when the output changed (with WebhookOnChange) or new
+// lines match WebhookMatch. The first run has no baseline, so it only posts
+// for matches.
+func (m *model) webhookCmd() tea.Cmd {
+	if m.config.WebhookURL == "" {
+		return nil
+	}
+	run := m.runLines()
+	curr := make([]string, len(run))
+	for i, line := range run {
+		curr[i] = stripANSI(line.Content)
+	}
+	prev, primed := m.webhookLines, m.webhookPrimed
+	m.webhookLines, m.webhookPrimed = curr, true
+
+	payload := webhook.Payload{
+		Command:   m.config.Command,
+		ExitCode:  m.exitCode,
+		Timestamp: time.Now(),
+	}
+	for i, isNew := range diffNewLines(prev, curr) {
+		if !isNew {
+			continue
+		}
+		payload.Added++
+		if m.config.WebhookMatch != nil && m.config.WebhookMatch.Match(curr[i]) {
+			payload.Matches = append(payload.Matches, curr[i])
+		}
+	}
+	for _, gone := range diffNewLines(curr, prev) {
+		if gone {
+			payload.Removed++
+		}
+	}
+	payload.Changed = primed && (payload.Added > 0 || payload.Removed > 0)
+
+	if !(payload.Changed && m.config.WebhookOnChange) && len(payload.Matches) == 0 {
+		return nil
+	}
+	url := m.config.WebhookURL
+	return func() tea.Msg {
+		if err := webhook.Post(context.Background(), url, payload); err != nil {
+			return hookFailedMsg{err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/webhook"
+)
+
+func TestWebhookCmd(t *testing.T) {
+	var posted []webhook.Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhook.Payload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		posted = append(posted, p)
+	}))
+	defer srv.Close()
+
+	match, _ := filter.New(filter.Regex, "error")
+	m := testModel(Config{Command: "check", Shell: "sh", WebhookURL: srv.URL, WebhookOnChange: true, WebhookMatch: match})
+	finish := func(lines ...string) {
+		m.lines = nil
+		for i, l := range lines {
+			m.lines = append(m.lines, runner.Line{Number: i + 1, Content: l})
+		}
+		if cmd := m.webhookCmd(); cmd != nil {
+			if msg := cmd(); msg != nil {
+				t.Fatalf("unexpected message: %v", msg)
+			}
+		}
+	}
+
+	finish("ok", "all good")
+	if len(posted) != 0 {
+		t.Fatalf("expected no post for the first run, got %d", len(posted))
+	}
+
+	finish("ok", "all good")
+	if len(posted) != 0 {
+		t.Fatalf("expected no post for unchanged output, got %d", len(posted))
+	}
+
+	finish("ok", "\x1b[31merror: disk full\x1b[0m")
+	if len(posted) != 1 {
+		t.Fatalf("expected one post, got %d", len(posted))
+	}
+	p := posted[0]
+	if !p.Changed || p.Added != 1 || p.Removed != 1 || p.Command != "check" {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+	if len(p.Matches) != 1 || p.Matches[0] != "error: disk full" {
+		t.Errorf("expected stripped match, got %v", p.Matches)
+	}
+}
+
+func TestWebhookCmdMatchOnly(t *testing.T) {
+	match, _ := filter.New(filter.Regex, "error")
+	m := testModel(Config{Command: "check", Shell: "sh", WebhookURL: "http://127.0.0.1:0", WebhookMatch: match})
+
+	m.lines = []runner.Line{{Number: 1, Content: "ok"}}
+	m.webhookCmd()
+	m.lines = []runner.Line{{Number: 1, Content: "still ok"}}
+	if cmd := m.webhookCmd(); cmd != nil {
+		t.Error("expected no post for a change without on-change")
+	}
+	m.lines = []runner.Line{{Number: 1, Content: "error"}}
+	if cmd := m.webhookCmd(); cmd == nil {
+		t.Error("expected a post for a matching line")
+	}
+}
+
+func TestWebhookCmdDisabled(t *testing.T) {
+	m := testModelWithLines()
+	if cmd := m.webhookCmd(); cmd != nil {
+		t.Error("expected no webhook without a URL")
+	}
+}
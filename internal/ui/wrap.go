@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+func (m *model) actionToggleWrap() (tea.Model, tea.Cmd) {
+	m.wrapLines = !m.wrapLines
+	m.adjustOffset()
+	if m.wrapLines {
+		m.statusMsg = i18n.T("status.wrap_on")
+	} else {
+		m.statusMsg = i18n.T("status.wrap_off")
+	}
+	return m, m.statusTimeoutCmd()
+}
+
+// listGutter returns what precedes a line's content in the list: the line
+// number column, or the note marker when line numbers are hidden.
+func (m model) listGutter(line runner.Line) string {
+	if m.config.ShowLineNums {
+		return m.lineGutter(line)
+	}
+	return m.markerGutter(line)
+}
+
+// wrappedRows soft-wraps a line's content to fit the list beside its gutter.
+// The rows don't include the gutter; continuation rows are shown indented
+// under it, after the wrap marker.
+func (m model) wrappedRows(line runner.Line, listWidth int) []string {
+	width := listWidth - lipgloss.Width(m.listGutter(line))
+	rows := wrapTextIndent(m.displayed(line.Content), width, width-textWidth(m.config.WrapMarker))
+	if len(rows) == 0 {
+		return []string{""}
+	}
+	return rows
+}
+
+// lineHeight returns how many list rows the filtered line at i takes up.
+func (m model) lineHeight(i int) int {
+	if !m.wrapLines || i < 0 || i >= len(m.filtered) || m.filtered[i] >= len(m.lines) {
+		return 1
+	}
+	_, listWidth := m.listDimensions(m.innerWidth())
+	return len(m.wrappedRows(m.lines[m.filtered[i]], listWidth))
+}
+
+// maxOffset returns the largest list offset that still fills the list, so
+// the last line sits at the bottom rather than scrolling out of view.
+func (m model) maxOffset() int {
+	visible := m.visibleLines()
+	if !m.wrapLines {
+		return max(len(m.filtered)-visible, 0)
+	}
+	offset, rows := len(m.filtered), 0
+	for offset > 0 {
+		h := m.lineHeight(offset - 1)
+		if rows+h > visible {
+			break
+		}
+		rows += h
+		offset--
+	}
+	// A last line taller than the list still has to be shown from its start
+	return min(offset, max(len(m.filtered)-1, 0))
+}
+
+// lineAtRow returns the filtered line index shown at a list row.
+func (m model) lineAtRow(row int) (int, bool) {
+	for i := m.offset; i < len(m.filtered); i++ {
+		h := m.lineHeight(i)
+		if row < h {
+			return i, true
+		}
+		row -= h
+	}
+	return 0, false
+}
+
+// renderWrappedListLines renders the list with lines soft-wrapped, starting
+// from the line at offset.
+func (m model) renderWrappedListLines(listHeight, listWidth int) []string {
+	selectedGutterStyle := lipgloss.NewStyle().
+		Background(m.config.Theme.Selection).
+		Foreground(m.config.Theme.LineNumber)
+	selectedStyle := lipgloss.NewStyle().
+		Background(m.config.Theme.Selection).
+		Foreground(m.config.Theme.SelectionText).
+		Bold(true)
+	markerStyle := lipgloss.NewStyle().Foreground(m.config.Theme.Muted)
+	fullWidth := listWidth + 1
+	locators := m.matchLocators()
+
+	var listLines []string
+	for lineIdx := m.offset; lineIdx < len(m.filtered) && len(listLines) < listHeight; lineIdx++ {
+		idx := m.filtered[lineIdx]
+		if idx >= len(m.lines) {
+			continue
+		}
+		line := m.lines[idx]
+		gutter := m.listGutter(line)
+		indent := strings.Repeat(" ", lipgloss.Width(gutter))
+		isSelected := lineIdx == m.cursor
+		var ranges [][]int
+		if len(locators) > 0 {
+			ranges = locateMatches(stripANSI(m.displayed(line.Content)), locators)
+		}
+
+		base := 0 // offset of the row in the plain line, for highlighting
+		for j, row := range m.wrappedRows(line, listWidth) {
+			if len(listLines) == listHeight {
+				break
+			}
+			prefix, marker := gutter, ""
+			if j > 0 {
+				prefix, marker = indent, m.config.WrapMarker
+			}
+			if isSelected {
+				content := stripANSI(row)
+				if padding := fullWidth - lipgloss.Width(prefix+marker) - lipgloss.Width(content); padding > 0 {
+					content += strings.Repeat(" ", padding)
+				}
+				listLines = append(listLines, selectedGutterStyle.Render(prefix+marker)+selectedStyle.Render(highlightRanges(content, ranges, base)))
+			} else {
+				listLines = append(listLines, m.gutterStyle(line.Number).Render(prefix)+markerStyle.Render(marker)+highlightRanges(m.dimByAge(line, m.levelColored(line, m.highlightChange(line, row))), ranges, base))
+			}
+			base += len(stripANSI(row))
+		}
+	}
+	for len(listLines) < listHeight {
+		listLines = append(listLines, m.fillerRow())
+	}
+	return listLines
+}
+
+// fillerRow returns what the list rows past the end of the output show.
+func (m model) fillerRow() string {
+	if m.config.Filler == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.config.Theme.Muted).Render(m.config.Filler)
+}
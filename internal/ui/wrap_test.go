@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// wrapTestModel returns a 40-column model whose second line wraps onto
+// several rows
+func wrapTestModel() *model {
+	m := testModelWithLines()
+	m.width = 40
+	m.config.ShowLineNums = true
+	m.config.LineNumWidth = 3
+	m.lines[1].Content = strings.Repeat("abcdefghij", 8)
+	m.wrapLines = true
+	m.updateFiltered()
+	return m
+}
+
+func TestWrapRendersContinuationRows(t *testing.T) {
+	m := wrapTestModel()
+	_, listWidth := m.listDimensions(m.innerWidth())
+	rows := m.renderListLines(m.visibleLines(), listWidth)
+
+	if got := stripANSI(rows[1]); !strings.HasPrefix(got, "  2  abcdefghij") {
+		t.Errorf("expected first row with gutter, got %q", got)
+	}
+	if got := stripANSI(rows[2]); !strings.HasPrefix(got, "     ") || strings.TrimSpace(got) == "" {
+		t.Errorf("expected continuation row indented under the gutter, got %q", got)
+	}
+	if got := stripANSI(rows[4]); !strings.Contains(got, "hello foo") {
+		t.Errorf("expected the next line after three wrapped rows, got %q", got)
+	}
+	if h := m.lineHeight(1); h != 3 {
+		t.Errorf("expected the long line to take 3 rows, got %d", h)
+	}
+}
+
+func TestWrapNavigatesLogicalLines(t *testing.T) {
+	m := wrapTestModel()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.cursor != 2 {
+		t.Errorf("expected j to move by logical lines, got cursor %d", m.cursor)
+	}
+}
+
+func TestWrapToggle(t *testing.T) {
+	m := wrapTestModel()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if m.wrapLines {
+		t.Fatal("expected w to turn soft-wrap off")
+	}
+	if h := m.lineHeight(1); h != 1 {
+		t.Errorf("expected one row per line without wrap, got %d", h)
+	}
+}
+
+func TestWrapKeepsCursorVisible(t *testing.T) {
+	m := wrapTestModel()
+	m.lines = nil
+	for i := range 40 {
+		m.lines = append(m.lines, runner.Line{Number: i + 1, Content: fmt.Sprintf("line %d %s", i+1, strings.Repeat("x", 50))})
+	}
+	m.updateFiltered()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "line 40 ") {
+		t.Error("expected the last line to be visible")
+	}
+	if m.offset != m.maxOffset() || m.offset >= len(m.filtered)-1 {
+		t.Errorf("expected offset to fill the list, got %d", m.offset)
+	}
+}
+
+func TestWrapMouseClickSelectsWrappedLine(t *testing.T) {
+	m := wrapTestModel()
+	// Rows 1-3 belong to the wrapped line, row 4 is "hello foo"
+	press(m, 5, m.contentTop()+3)
+	if m.cursor != 1 {
+		t.Errorf("expected clicking a continuation row to select its line, got %d", m.cursor)
+	}
+	press(m, 5, m.contentTop()+4)
+	if m.cursor != 2 {
+		t.Errorf("expected cursor 2, got %d", m.cursor)
+	}
+}
+
+func TestWrapRenderedRows(t *testing.T) {
+	m := wrapTestModel()
+	rows := m.renderedRows(m.lines[1])
+	if len(rows) != 3 || !strings.HasPrefix(rows[0], "  2  ") || !strings.HasPrefix(rows[1], "     c") {
+		t.Errorf("expected wrapped rendered rows, got %q", rows)
+	}
+}
+
+func TestIndicators(t *testing.T) {
+	m := wrapTestModel()
+	m.config.WrapMarker = "+ "
+	m.config.Filler = "~"
+	_, listWidth := m.listDimensions(m.innerWidth())
+	rows := m.renderListLines(m.visibleLines(), listWidth)
+	if got := stripANSI(rows[2]); !strings.HasPrefix(got, "     + ") {
+		t.Errorf("expected the wrap marker after the gutter, got %q", got)
+	}
+	if h := m.lineHeight(1); h != 3 {
+		t.Errorf("expected the marker to narrow continuation rows, got %d rows", h)
+	}
+	if got := stripANSI(rows[len(rows)-1]); got != "~" {
+		t.Errorf("expected filler past the end of the output, got %q", got)
+	}
+
+	m.wrapLines = false
+	m.config.Ellipsis = ">"
+	rows = m.renderListLines(m.visibleLines(), listWidth)
+	if got := strings.TrimRight(stripANSI(rows[1]), " "); !strings.HasSuffix(got, ">") {
+		t.Errorf("expected the custom ellipsis, got %q", got)
+	}
+	if got := m.renderedRows(m.lines[1])[0]; !strings.HasSuffix(got, ">") || strings.Contains(got, "…") {
+		t.Errorf("expected the custom ellipsis, got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+)
+
+// writeCommand is a parsed ":w" palette command
+type writeCommand struct {
+	path    string
+	all     bool // write the whole output rather than the filtered lines
+	numbers bool // prefix each line with its line number
+}
+
+// parseWriteCommand parses palette input of the form "w[a][n] <path>", as in
+// vim's :w. "a" writes the whole output instead of the filtered lines and "n"
+// adds line numbers. Reports false when the input isn't a write command.
+func parseWriteCommand(input string) (writeCommand, bool) {
+	name, path, ok := strings.Cut(strings.TrimSpace(input), " ")
+	path = strings.TrimSpace(path)
+	if !ok || path == "" {
+		return writeCommand{}, false
+	}
+	var wc writeCommand
+	switch name {
+	case "w":
+	case "wa":
+		wc.all = true
+	case "wn":
+		wc.numbers = true
+	case "wan", "wna":
+		wc.all, wc.numbers = true, true
+	default:
+		return writeCommand{}, false
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	wc.path = path
+	return wc, true
+}
+
+// actionEnterWrite opens the command palette with a write command started.
+func (m *model) actionEnterWrite() (tea.Model, tea.Cmd) {
+	m.actionOpenPalette()
+	m.cmdPaletteInput.insert("w ")
+	return m, nil
+}
+
+// writeOutput saves the filtered (or whole) output to a file as plain text.
+func (m *model) writeOutput(wc writeCommand) (tea.Model, tea.Cmd) {
+	var b strings.Builder
+	n := 0
+	write := func(idx int) {
+		if wc.numbers {
+			fmt.Fprintf(&b, "%*d ", m.config.LineNumWidth, m.lines[idx].Number)
+		}
+		b.WriteString(stripANSI(m.lines[idx].Content))
+		b.WriteByte('\n')
+		n++
+	}
+	if wc.all {
+		for i := range m.lines {
+			write(i)
+		}
+	} else {
+		for _, idx := range m.filtered {
+			if idx < len(m.lines) {
+				write(idx)
+			}
+		}
+	}
+
+	if err := os.WriteFile(wc.path, []byte(b.String()), 0o644); err != nil {
+		m.statusMsg = i18n.T("error.write_failed", err)
+	} else {
+		m.statusMsg = i18n.T("status.wrote", n, wc.path)
+	}
+	return m, m.statusTimeoutCmd()
+}
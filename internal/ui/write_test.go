@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseWriteCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  writeCommand
+		ok    bool
+	}{
+		{"w out.txt", writeCommand{path: "out.txt"}, true},
+		{"  wa  out.txt ", writeCommand{path: "out.txt", all: true}, true},
+		{"wn out.txt", writeCommand{path: "out.txt", numbers: true}, true},
+		{"wan out.txt", writeCommand{path: "out.txt", all: true, numbers: true}, true},
+		{"w", writeCommand{}, false},
+		{"w ", writeCommand{}, false},
+		{"write out.txt", writeCommand{}, false},
+		{"reload", writeCommand{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseWriteCommand(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseWriteCommand(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir := t.TempDir()
+	m := testModelWithLines()
+	m.lines[0].Content = "\x1b[31mhello\x1b[0m world"
+	m.filterInput.Text = "hello"
+	m.updateFiltered()
+
+	path := filepath.Join(dir, "filtered.txt")
+	m.writeOutput(writeCommand{path: path})
+	if got, _ := os.ReadFile(path); string(got) != "hello world\nhello foo\n" {
+		t.Errorf("expected the filtered lines as plain text, got %q", got)
+	}
+	if m.statusMsg != "Wrote 2 lines to "+path {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+
+	path = filepath.Join(dir, "all.txt")
+	m.writeOutput(writeCommand{path: path, all: true, numbers: true})
+	if got, _ := os.ReadFile(path); string(got) != "1 hello world\n2 foo bar\n3 hello foo\n4 baz qux\n" {
+		t.Errorf("expected the whole output with line numbers, got %q", got)
+	}
+
+	m.writeOutput(writeCommand{path: filepath.Join(dir, "missing", "out.txt")})
+	if !strings.HasPrefix(m.statusMsg, "Failed to write") {
+		t.Errorf("expected a failure status, got %q", m.statusMsg)
+	}
+}
+
+func TestWriteFromPalette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	m := testModelWithLines()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.cmdPaletteMode || m.cmdPaletteInput.Text != "w " {
+		t.Fatalf("expected Ctrl+s to start a write command, got %q", m.cmdPaletteInput.Text)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(path)})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.cmdPaletteMode {
+		t.Error("expected the palette to close")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "hello world\nfoo bar\nhello foo\nbaz qux\n" {
+		t.Errorf("unexpected file contents %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runner"
+)
+
+// YankFormat controls how yanked lines are formatted
+type YankFormat struct {
+	Numbers  bool // prefix each line with its line number
+	Time     bool // prefix each line with the time its run started
+	Markdown bool // wrap the lines in a fenced code block under the command
+}
+
+// ParseYankFormat parses a comma-separated list of yank format options:
+// numbers, time and markdown. "plain" or an empty string leaves lines as is.
+func ParseYankFormat(spec string) (YankFormat, error) {
+	var f YankFormat
+	for opt := range strings.SplitSeq(spec, ",") {
+		switch strings.TrimSpace(opt) {
+		case "", "plain":
+		case "numbers":
+			f.Numbers = true
+		case "time":
+			f.Time = true
+		case "markdown":
+			f.Markdown = true
+		default:
+			return YankFormat{}, fmt.Errorf("unknown yank format %q (expected numbers, time, markdown or plain)", opt)
+		}
+	}
+	return f, nil
+}
+
+// formatYank joins lines for yanking, formatted as configured.
+func (m model) formatYank(lines []runner.Line, plain bool, f YankFormat) string {
+	rows := make([]string, len(lines))
+	for i, line := range lines {
+		content := m.yanked(line.Content)
+		if plain || f.Markdown {
+			content = stripANSI(content)
+		}
+		if f.Numbers {
+			content = fmt.Sprintf("%*d  %s", m.config.LineNumWidth, line.Number, content)
+		}
+		if f.Time && !m.runStartTime.IsZero() {
+			content = m.config.TimeFormat.format(m.runStartTime, time.DateTime) + "  " + content
+		}
+		rows[i] = content
+	}
+	text := strings.Join(rows, "\n")
+	if f.Markdown {
+		text = "```console\n$ " + m.config.Command + "\n" + text + "\n```"
+	}
+	return text
+}
+
+// actionCopyMarkdown copies the selected (or marked) lines as a fenced
+// Markdown code block under the command, for pasting into issues and chat.
+func (m *model) actionCopyMarkdown() (tea.Model, tea.Cmd) {
+	f := m.config.YankFormat
+	f.Markdown = true
+	lines := m.markedOutput()
+	if len(lines) == 0 {
+		idx := m.selectedIndex()
+		if idx < 0 || idx >= len(m.lines) {
+			return m, nil
+		}
+		lines = []runner.Line{m.lines[idx]}
+	}
+	if err := m.yank(m.formatYank(lines, true, f)); err != nil {
+		m.statusMsg = i18n.T("error.copy_failed")
+	} else {
+		m.statusMsg = i18n.T("status.copied_markdown", len(lines)) + m.registerNote()
+	}
+	return m, m.statusTimeoutCmd()
+}
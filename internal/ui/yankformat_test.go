@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseYankFormat(t *testing.T) {
+	f, err := ParseYankFormat("numbers, markdown")
+	if err != nil || f != (YankFormat{Numbers: true, Markdown: true}) {
+		t.Errorf("unexpected format %+v, %v", f, err)
+	}
+	if f, err := ParseYankFormat("plain"); err != nil || f != (YankFormat{}) {
+		t.Errorf("expected plain to leave lines as is, got %+v, %v", f, err)
+	}
+	if _, err := ParseYankFormat("html"); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func TestFormatYank(t *testing.T) {
+	m := testModelWithLines()
+	m.config.LineNumWidth = 3
+	m.config.Command = "make test"
+	m.runStartTime = time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	m.lines[1].Content = "\x1b[31mfoo\x1b[0m bar"
+	lines := m.lines[1:3]
+
+	if got := m.formatYank(lines, false, YankFormat{}); got != "\x1b[31mfoo\x1b[0m bar\nhello foo" {
+		t.Errorf("expected lines as is, got %q", got)
+	}
+	if got := m.formatYank(lines, true, YankFormat{Numbers: true}); got != "  2  foo bar\n  3  hello foo" {
+		t.Errorf("expected numbered lines, got %q", got)
+	}
+	if got := m.formatYank(lines[:1], true, YankFormat{Time: true}); got != "2026-01-02 15:04:05  foo bar" {
+		t.Errorf("expected a timestamped line, got %q", got)
+	}
+	want := "```console\n$ make test\nfoo bar\nhello foo\n```"
+	if got := m.formatYank(lines, false, YankFormat{Markdown: true}); got != want {
+		t.Errorf("expected a plain Markdown block, got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Package webhook posts a JSON summary of a run to an HTTP endpoint, so watchr
+// can feed chat and incident tooling.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Timeout bounds each request so a slow endpoint can't pile up requests
+const Timeout = 10 * time.Second
+
+// Payload is the JSON body posted for a run
+type Payload struct {
+	Command   string    `json:"command"`
+	ExitCode  int       `json:"exit_code"`
+	Timestamp time.Time `json:"timestamp"`
+	Changed   bool      `json:"changed"`           // output differs from the previous run
+	Added     int       `json:"added"`             // lines not in the previous run
+	Removed   int       `json:"removed"`           // lines of the previous run that are gone
+	Matches   []string  `json:"matches,omitempty"` // new lines matching the webhook rule
+}
+
+// Post sends p to url as JSON. Any non-2xx response is an error.
+func Post(ctx context.Context, url string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "watchr")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s returned %s", url, resp.Status)
+	}
+	return nil
+}
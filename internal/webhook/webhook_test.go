@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPost(t *testing.T) {
+	var got Payload
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	want := Payload{
+		Command:   "make test",
+		ExitCode:  2,
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Changed:   true,
+		Added:     3,
+		Removed:   1,
+		Matches:   []string{"FAIL: TestFoo"},
+	}
+	if err := Post(context.Background(), srv.URL, want); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("expected JSON content type, got %q", contentType)
+	}
+	if got.Command != want.Command || got.ExitCode != want.ExitCode || !got.Timestamp.Equal(want.Timestamp) ||
+		got.Added != 3 || got.Removed != 1 || len(got.Matches) != 1 {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+func TestPostErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	err := Post(context.Background(), srv.URL, Payload{})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected a 500 error, got %v", err)
+	}
+}
@@ -1,13 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/chenasraf/watchr/internal/config"
+	"github.com/chenasraf/watchr/internal/daemon"
+	"github.com/chenasraf/watchr/internal/filter"
+	"github.com/chenasraf/watchr/internal/i18n"
+	"github.com/chenasraf/watchr/internal/runlog"
+	"github.com/chenasraf/watchr/internal/runner"
+	"github.com/chenasraf/watchr/internal/state"
 	"github.com/chenasraf/watchr/internal/ui"
 	flag "github.com/spf13/pflag"
 )
@@ -20,6 +35,10 @@ func main() {
 		showVersion bool
 		showHelp    bool
 		showConfig  bool
+		dryRun      bool
+		resume      bool
+		detach      bool
+		printSelect bool
 		configFile  string
 	)
 
@@ -27,20 +46,94 @@ func main() {
 	flag.BoolVarP(&showVersion, "version", "v", false, "Show version")
 	flag.BoolVarP(&showHelp, "help", "h", false, "Show help")
 	flag.BoolVarP(&showConfig, "show-config", "C", false, "Show loaded configuration and exit")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print how the command would be executed and exit")
+	flag.BoolVar(&resume, "resume", false, "Restore the last session, or the given command's (e.g., after a crash)")
+	flag.BoolVar(&detach, "detach", false, "Start the daemon in the background, detached from the terminal")
+	flag.BoolVar(&printSelect, "print-selection", false, "Enter quits and prints the selected (or marked) lines to stdout, for use in pipelines")
 	flag.StringVarP(&configFile, "config", "c", "", "Load config from specified path")
+	flag.String("preset", "", "Run a named preset from the config file's presets section (its command, unless one is given, and options)")
 	flag.StringP("preview-size", "P", "40%", "Preview size: number for lines/cols, or number% for percentage (e.g., 10 or 40%)")
 	flag.StringP("preview-position", "o", "bottom", "Preview position: bottom, top, left, right")
-	flag.BoolP("no-line-numbers", "n", false, "Disable line numbers")
+	flag.String("preview", "", "Command whose output is previewed for the selected line, which replaces {} (e.g., 'cat {}')")
+	flag.Int("preview-context", 5, "Lines of output shown above and below the selected line in context preview (x)")
+	flag.Bool("no-file-preview", false, "Don't preview the contents of files named by the selected line (e.g., grep -n or find output)")
+	flag.Bool("auto-preview", false, "Open the preview automatically while the selected line is truncated")
+	flag.Bool("preview-open", false, "Start with the preview open, at --preview-position and --preview-size")
+	flag.BoolP("no-line-numbers", "n", false, "Disable line numbers (# toggles)")
 	flag.IntP("line-width", "w", 6, "Line number width")
-	flag.StringP("prompt", "p", "watchr> ", "Prompt string")
-	flag.StringP("shell", "s", "sh", "Shell to use for executing commands")
-	flag.StringP("refresh", "r", "0", "Auto-refresh interval (e.g., 1, 1.5, 500ms, 2s, 5m, 1h; default unit: seconds, 0 = disabled)")
+	flag.BoolP("differences", "d", false, "Highlight lines that changed since the previous run, like watch -d")
+	flag.Bool("dual-line-numbers", false, "While filtering, show each line's position among the matches after its line number (e.g., 42│3)")
+	flag.Bool("relative-line-numbers", false, "Number lines by their distance from the selected line, as vim's relativenumber")
+	flag.StringP("prompt", "p", "watchr> ", "Prompt string, with {exit_code}, {time}, {matches} and {interval} placeholders")
+	flag.StringP("shell", "s", config.DefaultShell(), "Shell to use for executing commands (-c is passed to it, /C to cmd and -Command to PowerShell)")
+	flag.StringP("refresh", "r", "0", "Auto-refresh interval (e.g., 1, 1.5, 500ms, 2s, 5m, 1h30m; default unit: seconds, 0 = disabled)")
 	flag.Bool("refresh-from-start", false, "Start refresh timer when command starts (default: when command ends)")
+	flag.String("refresh-jitter", "0", "Add a random delay up to this duration to each refresh (e.g., 500ms, 2s; 0 = disabled)")
+	flag.String("min-interval", "0", "Never start runs closer together than this, coalescing extra reloads and refreshes (e.g., 10s; 0 = disabled)")
+	flag.Bool("precise", false, "Align refreshes to wall-clock multiples of the interval (e.g., every :00 and :30 for 30s)")
+	flag.String("pre-run", "", "Command to run (locally) before each run; the run is skipped if it fails")
+	flag.String("post-run", "", "Command to run (locally) after each run, with $WATCHR_EXIT_CODE set")
+	flag.Bool("transition-bell", false, "Ring the bell when the command starts failing or passes again")
+	flag.String("on-transition", "", "Command to run when the command starts failing or passes again ($WATCHR_TRANSITION is fail or pass)")
+	flag.String("webhook", "", "POST a JSON summary to this URL when the output changes")
+	flag.String("webhook-match", "", "Also POST to the webhook when new lines match this regex")
+	flag.String("ssh", "", "Run the command on a remote host over ssh (e.g., user@host; requires key-based auth)")
+	flag.String("docker", "", "Run the command inside a running container with docker exec")
+	flag.String("kubectl-pod", "", "Run the command inside a pod with kubectl exec (e.g., mypod or deploy/api)")
 	flag.BoolP("interactive", "i", false, "Run shell in interactive mode (sources ~/.bashrc, ~/.zshrc, etc.)")
+	flag.Int("max-runs", 0, "Stop auto-refreshing after this many runs (0 = unlimited)")
+	flag.String("for", "0", "Stop auto-refreshing after this duration (e.g., 90s, 10m; 0 = unlimited)")
+	flag.String("exit-code", "", "On quit, exit with the last run's exit code (last) or the first failing run's (any)")
+	flag.Lookup("exit-code").NoOptDefVal = string(ui.ExitCodeLast)
+	flag.Bool("exit-on-limit", false, "Exit instead of freezing the UI when --max-runs or --for is reached")
+	flag.String("control-chars", "strip", "How to show control characters in output: strip, caret (visible, e.g. ^G), raw")
+	flag.Int("tab-width", runner.DefaultTabWidth, "Columns between tab stops when expanding tabs in output")
+	flag.Bool("keep-progress-frames", false, "Keep every frame of lines redrawn with \\r (progress bars) instead of only the final state")
+	flag.String("log-output", "", "Save each run's output (with timestamp, exit code and duration) to a file in this directory")
+	flag.String("record", "", "Record the session (every run, as JSON Lines) to this file for sharing or replay")
+	flag.Bool("anomaly-alert", false, "Ring the bell when a run's line count deviates sharply from recent runs")
+	flag.Bool("no-initial-run", false, "Start with an empty list and wait for a manual reload or the first refresh tick")
+	flag.String("filter", "", "Start with this filter applied (e.g., ERROR), matched as --filter-mode")
+	flag.String("filter-mode", "substring", "Default filter matching: substring, regex, glob, fuzzy")
+	flag.String("filter-case", "smart", "Filter and search case matching: ignore, smart, sensitive")
+	flag.Int("scrolloff", -1, "Lines kept above/below the cursor when scrolling (0 scrolls only at the edges, -1 keeps it centered)")
+	flag.Bool("wrap", false, "Soft-wrap long lines in the list instead of truncating them")
+	flag.String("ellipsis", "…", "Marks the end of lines cut off at the edge of the list (e.g., > or ...)")
+	flag.String("wrap-marker", "", "Starts each continuation row of a soft-wrapped line (e.g., '+ ' or '↪ ')")
+	flag.String("filler", "", "Fills the list rows past the end of the output, as vim's ~ (default blank)")
+	flag.Bool("append", false, "Append each run's output after the previous runs' (under a separator with its time) instead of replacing it")
+	flag.Int("append-runs", 50, "Runs kept with --append, the oldest dropped first (0 = all)")
+	flag.Bool("no-mouse", false, "Don't capture the mouse (keeps the terminal's own text selection)")
+	flag.Bool("no-scrollbar", false, "Don't draw a scrollbar beside the list")
+	flag.String("delimiter", "", "Regex that splits lines into fields for --with-nth (default: whitespace)")
+	flag.String("with-nth", "", "Only display and match these fields of each line, fzf-style (e.g., 1,3.. or -1)")
+	flag.Int("header-lines", 0, "Pin this many leading output lines above the list, leaving them out of filtering (T toggles)")
+	flag.Bool("table", false, "Show lines as aligned columns split on --delimiter, under the first line as a header")
+	flag.String("yank-format", "plain", "Format of yanked lines: plain, or any of numbers, time, markdown (e.g., numbers,markdown)")
+	flag.String("time-format", "", "Format of shown timestamps: a Go layout (15:04:05), a strftime format (%H:%M) or relative")
+	flag.String("border", "rounded", "Box border style: "+strings.Join(ui.BorderNames(), ", "))
+	flag.String("header-template", "", "Header text with {command}, {cwd}, {exit_code}, {duration}, {last_run} and {next_run} placeholders")
+	flag.String("highlight-new", "0", "Mark lines that weren't in the previous run for this long (e.g., 5s; 0 = disabled)")
+	flag.String("dim-age", "", "Dim lines a step further past each of these ages (e.g., 10s,1m,5m)")
+	flag.String("status-bar", "", "Status bar modules, those after | on the right (default: prompt,filter,activity,status | duration,countdown,count,help)")
+	flag.BoolP("no-title", "t", false, "Hide the title line with the command, as watch -t does (Ctrl-t toggles)")
+	flag.Bool("no-hints", false, "Leave the help hint out of the prompt line")
+	flag.Bool("no-remember", false, "Don't restore the filter, preview and selected line from the last time the command was watched")
+	flag.Bool("no-log-levels", false, "Don't color lines by the log level they mention (ERROR, WARN, INFO, DEBUG, ...)")
+	flag.Bool("compact", false, "Leave out the box around the view so more rows show output (for small panes)")
+	flag.String("theme", "default", "Color theme: "+strings.Join(ui.ThemeNames(), ", "))
+	flag.Bool("yank-displayed", false, "Yank and print the fields shown by --with-nth instead of whole lines")
+	flag.String("lang", i18n.Default, "Interface language: "+strings.Join(i18n.Langs(), ", "))
+	flag.String("keymap", "vim", "Key profile: "+strings.Join(ui.KeymapNames(), ", ")+" (--bind keys override it)")
+	flag.StringArray("bind", nil, "Bind a key to a command for the selected line, fzf-style (e.g., 'ctrl-o:execute(kubectl describe pod {1})'); repeatable")
 
 	printUsage := func(w *os.File) {
-		_, _ = fmt.Fprintf(w, "Usage: watchr [options] <command to run>\n\n")
+		_, _ = fmt.Fprintf(w, "Usage: watchr [options] [run] <command to run>\n")
+		_, _ = fmt.Fprintf(w, "       watchr [options] <subcommand> [args]\n\n")
 		_, _ = fmt.Fprintf(w, "A terminal UI for running and watching command output.\n\n")
+		_, _ = fmt.Fprintf(w, "Subcommands:\n")
+		printCommands(w)
+		_, _ = fmt.Fprintf(w, "\n")
 		_, _ = fmt.Fprintf(w, "Options:\n")
 		flag.CommandLine.SetOutput(w)
 		flag.PrintDefaults()
@@ -58,11 +151,27 @@ func main() {
 		_, _ = fmt.Fprintf(w, "  Ctrl-d/u       Half page down/up\n")
 		_, _ = fmt.Fprintf(w, "  PgDn/Up, ^f/b  Full page down/up\n")
 		_, _ = fmt.Fprintf(w, "  p              Toggle preview\n")
+		_, _ = fmt.Fprintf(w, "  x              Toggle context preview (surrounding output)\n")
 		_, _ = fmt.Fprintf(w, "  /              Enter filter mode\n")
 		_, _ = fmt.Fprintf(w, "  Esc            Exit filter mode / clear filter\n")
 		_, _ = fmt.Fprintf(w, "  y              Yank (copy) selected line\n")
 		_, _ = fmt.Fprintf(w, "  Y              Yank selected line (plain text)\n")
-		_, _ = fmt.Fprintf(w, "  ?              Show help overlay\n")
+		_, _ = fmt.Fprintf(w, "  f              Yank the selected column (table mode)\n")
+		_, _ = fmt.Fprintf(w, "  M              Yank selected lines as a Markdown code block\n")
+		_, _ = fmt.Fprintf(w, "  V              Yank selected line as rendered on screen\n")
+		_, _ = fmt.Fprintf(w, "  t              Toggle table mode (H/L scroll columns)\n")
+		_, _ = fmt.Fprintf(w, "  O              Hide, reorder and resize table columns\n")
+		_, _ = fmt.Fprintf(w, "  T              Pin / unpin header lines\n")
+		_, _ = fmt.Fprintf(w, "  \"a, \"\"         Yank into / put from register a, show registers\n")
+		_, _ = fmt.Fprintf(w, "  e, Enter       Open the selected file:line in $EDITOR\n")
+		_, _ = fmt.Fprintf(w, "  |              Pipe selected lines to a command\n")
+		_, _ = fmt.Fprintf(w, "  Ctrl-s, :w     Write output to a file\n")
+		_, _ = fmt.Fprintf(w, "  [, ]           Previous/next run (replay)\n")
+		_, _ = fmt.Fprintf(w, "  a              Annotate selected line\n")
+		_, _ = fmt.Fprintf(w, "  A              Jump to next annotated line\n")
+		_, _ = fmt.Fprintf(w, "  ?              Search (jump between matches)\n")
+		_, _ = fmt.Fprintf(w, "  n, N           Next/previous search match\n")
+		_, _ = fmt.Fprintf(w, "  h, F1          Show help overlay\n")
 	}
 
 	flag.Usage = func() {
@@ -77,8 +186,9 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error loading config file: %v\n", err)
 			os.Exit(1)
 		}
-	} else {
-		config.Init()
+	} else if err := config.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config file: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Bind flags to config (CLI flags override config file values)
@@ -94,13 +204,67 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands other than run are handled here; anything that isn't a
+	// subcommand is the command to run
+	sub, args := subcommand(flag.Args())
+	switch sub {
+	case "completion":
+		os.Exit(completionCommand(args))
+	case "config":
+		os.Exit(configCommand(args))
+	case "doctor":
+		os.Exit(doctorCommand())
+	}
+
+	// A preset supplies the command and options, which flags still override
+	presetName := config.GetString(config.KeyPreset)
+	if sub == "preset" {
+		switch {
+		case len(args) == 1 && args[0] == "list":
+			for _, name := range config.PresetNames() {
+				fmt.Println(name)
+			}
+			os.Exit(0)
+		case len(args) == 2 && args[0] == "run":
+			sub, presetName, args = "run", args[1], nil
+		default:
+			fmt.Fprintln(os.Stderr, "Error: Usage: watchr preset run <name> | watchr preset list")
+			os.Exit(1)
+		}
+	}
+	if presetName != "" {
+		command, err := config.ApplyPreset(presetName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(args) == 0 && command != "" {
+			args = []string{command}
+		}
+	}
+
 	if showConfig {
 		config.PrintConfig()
 		os.Exit(0)
 	}
 
-	args := flag.Args()
-	if len(args) == 0 {
+	var session *state.Session
+	if resume {
+		var s state.Session
+		var err error
+		if len(args) > 0 {
+			s, err = state.Load(state.SessionPath(strings.Join(args, " ")))
+		} else {
+			s, err = state.Latest(state.SessionsDir())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: No session to resume: %v\n", err)
+			os.Exit(1)
+		}
+		session = &s
+	}
+
+	if (sub == "run" || sub == "daemon") && len(args) == 0 && session == nil {
 		fmt.Fprintln(os.Stderr, "Error: No command provided")
 		flag.Usage()
 		os.Exit(1)
@@ -111,13 +275,300 @@ func main() {
 	// Get config values (merged from: defaults < config file < CLI flags)
 	previewSize := config.GetString(config.KeyPreviewSize)
 	previewPosition := config.GetString(config.KeyPreviewPosition)
+	autoPreview := config.GetBool(config.KeyAutoPreview)
+	previewCommand := config.GetString(config.KeyPreviewCommand)
 	shell := config.GetString(config.KeyShell)
 	lineNumWidth := config.GetInt(config.KeyLineWidth)
 	prompt := config.GetString(config.KeyPrompt)
 	refreshInterval := config.GetDuration(config.KeyRefresh)
 	refreshFromStart := config.GetBool(config.KeyRefreshFromStart)
+	precise := config.GetBool(config.KeyPrecise)
+	refreshJitter := config.GetDuration(config.KeyRefreshJitter)
+	minInterval := config.GetDuration(config.KeyMinInterval)
 	showLineNums := config.ShowLineNumbers()
 	interactive := config.GetBool(config.KeyInteractive)
+	sshHost := config.GetString(config.KeySSH)
+	dockerContainer := config.GetString(config.KeyDocker)
+	kubectlPod := config.GetString(config.KeyKubectlPod)
+	remoteShell := config.RemoteShell(flag.CommandLine)
+	preRun := config.GetString(config.KeyPreRun)
+	postRun := config.GetString(config.KeyPostRun)
+	transitionBell := config.GetBool(config.KeyTransitionBell)
+	onTransition := config.GetString(config.KeyOnTransition)
+	webhookURL := config.GetString(config.KeyWebhookURL)
+	webhookOnChange := config.GetBool(config.KeyWebhookOnChange)
+	var webhookMatch filter.Matcher
+	if pattern := config.GetString(config.KeyWebhookMatch); pattern != "" {
+		var err error
+		if webhookMatch, err = filter.New(filter.Regex, pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid webhook-match: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if countSet(sshHost, dockerContainer, kubectlPod) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: Only one of --ssh, --docker and --kubectl-pod can be used")
+		os.Exit(1)
+	}
+	keepProgressFrames := config.GetBool(config.KeyProgressFrames)
+	controlChars := runner.ControlPolicy(config.GetString(config.KeyControlChars))
+	if !slices.Contains(runner.ControlPolicies, controlChars) {
+		fmt.Fprintf(os.Stderr, "Error: Invalid control-chars: %s (expected strip, caret or raw)\n", controlChars)
+		os.Exit(1)
+	}
+	tabWidth := config.GetInt(config.KeyTabWidth)
+	if tabWidth < 1 {
+		fmt.Fprintf(os.Stderr, "Error: Invalid tab-width: %d (must be at least 1)\n", tabWidth)
+		os.Exit(1)
+	}
+	initialRun := config.InitialRun()
+	mouse := config.Mouse()
+	wrap := config.GetBool(config.KeyWrap)
+	maxRuns := config.GetInt(config.KeyMaxRuns)
+	runFor := config.GetDuration(config.KeyFor)
+	exitOnLimit := config.GetBool(config.KeyExitOnLimit)
+	anomalyAlert := config.GetBool(config.KeyAnomalyAlert)
+	exitCodePolicy := ui.ExitCodePolicy(config.GetString(config.KeyExitCode))
+	switch exitCodePolicy {
+	case ui.ExitCodeNone, ui.ExitCodeLast, ui.ExitCodeAny:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Invalid exit-code: %s (expected last or any)\n", exitCodePolicy)
+		os.Exit(1)
+	}
+	filterMode := filter.Kind(config.GetString(config.KeyFilterMode))
+	if !slices.Contains(filter.Kinds, filterMode) {
+		fmt.Fprintf(os.Stderr, "Error: Invalid filter-mode: %s (expected substring, regex, glob or fuzzy)\n", filterMode)
+		os.Exit(1)
+	}
+	filterCase := filter.Case(config.GetString(config.KeyFilterCase))
+	if !slices.Contains(filter.Cases, filterCase) {
+		fmt.Fprintf(os.Stderr, "Error: Invalid filter-case: %s (expected ignore, smart or sensitive)\n", filterCase)
+		os.Exit(1)
+	}
+	bindFlags, _ := flag.CommandLine.GetStringArray("bind")
+	bindSpecs, err := config.Bindings(bindFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	bindings, err := ui.ParseBindings(bindSpecs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var delimiter *regexp.Regexp
+	if pattern := config.GetString(config.KeyDelimiter); pattern != "" {
+		if delimiter, err = regexp.Compile(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid delimiter: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var withNth []ui.FieldRange
+	if spec := config.GetString(config.KeyWithNth); spec != "" {
+		if withNth, err = ui.ParseNth(spec); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid with-nth: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	yankFormat, err := ui.ParseYankFormat(config.GetString(config.KeyYankFormat))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid yank-format: %v\n", err)
+		os.Exit(1)
+	}
+	timeFormat, err := ui.ParseTimeFormat(config.GetString(config.KeyTimeFormat))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid time-format: %v\n", err)
+		os.Exit(1)
+	}
+	theme, err := ui.LoadTheme(config.GetString(config.KeyTheme), config.ThemeColors())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid theme: %v\n", err)
+		os.Exit(1)
+	}
+	border, err := ui.ParseBorder(config.GetString(config.KeyBorder))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid border: %v\n", err)
+		os.Exit(1)
+	}
+	if err := i18n.SetLang(config.GetString(config.KeyLang)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid lang: %v\n", err)
+		os.Exit(1)
+	}
+	keymap, err := ui.ParseKeymap(config.GetString(config.KeyKeymap))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid keymap: %v\n", err)
+		os.Exit(1)
+	}
+	statusBar, err := ui.ParseStatusBar(config.GetString(config.KeyStatusBar))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid status-bar: %v\n", err)
+		os.Exit(1)
+	}
+	dimAge, err := config.ParseDurations(config.GetString(config.KeyDimAge))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid dim-age: %v\n", err)
+		os.Exit(1)
+	}
+	logOutput := config.GetString(config.KeyLogOutput)
+	record := config.GetString(config.KeyRecord)
+	journal := config.GetBool(config.KeyJournal)
+
+	// A resumed session supplies the command and filter unless they were given explicitly
+	filterText := config.GetString(config.KeyFilter)
+	var filterKind filter.Kind
+	var pinnedFilters []state.Filter
+	var notes map[string]string
+	var baseline string
+	var marks []string
+	if session != nil {
+		if len(args) == 0 {
+			cmdStr = session.Command
+			if session.Shell != "" {
+				shell = session.Shell
+			}
+			interactive = session.Interactive
+		}
+		if filterText == "" {
+			filterText = session.Filter
+			filterKind = filter.Kind(session.FilterKind)
+		}
+		pinnedFilters = session.Pinned
+		notes = session.Notes
+		baseline = session.Baseline
+		marks = session.Marks
+	}
+
+	// The command's remembered filter, preview and selected line fill in
+	// what wasn't given
+	previewOpen := config.GetBool(config.KeyPreviewOpen)
+	var statePath, anchor string
+	if sub == "run" && config.Remember() {
+		statePath = state.CommandsPath()
+		if remembered, ok := state.LoadCommand(statePath, cmdStr); ok {
+			if filterText == "" {
+				filterText = remembered.Filter
+				filterKind = filter.Kind(remembered.FilterKind)
+			}
+			previewOpen = previewOpen || remembered.Preview
+			anchor = remembered.Anchor
+		}
+	}
+
+	// Table mode's column layout is saved per preset, or else per command
+	layoutProfile := cmdStr
+	if presetName != "" {
+		layoutProfile = "preset:" + presetName
+	}
+	layoutPath := state.LayoutsPath()
+	columns, _ := state.LoadLayout(layoutPath, layoutProfile)
+
+	// Subcommands built on run: "daemon <command>", "attach", "snapshot" and
+	// "replay <dir|file>"
+	var replay []runlog.Run
+	switch sub {
+	case "daemon":
+		var r *runner.Runner
+		if interactive {
+			r = runner.NewInteractiveRunner(shell, cmdStr)
+		} else {
+			r = runner.NewRunner(shell, cmdStr)
+		}
+		r.KeepCRFrames = keepProgressFrames
+		r.ControlChars = controlChars
+		r.TabWidth = tabWidth
+		r.SSH = sshHost
+		r.Docker = dockerContainer
+		r.KubectlPod = kubectlPod
+		r.RemoteShell = remoteShell
+		r.PreRun = preRun
+		r.PostRun = postRun
+		d := &daemon.Daemon{Runner: r, Interval: refreshInterval, LogDir: logOutput, OnTransition: onTransition}
+		run := runDaemon
+		if detach && os.Getenv(detachedEnv) == "" {
+			run = detachDaemon
+		}
+		if err := run(d); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "snapshot":
+		socket, err := daemon.Find(daemon.Dir(), cmdStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		snap, err := daemon.Fetch(socket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, line := range snap.Lines {
+			fmt.Println(line)
+		}
+		if snap.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", snap.Error)
+		}
+		os.Exit(snap.ExitCode)
+	case "attach":
+		socket, err := daemon.Find(daemon.Dir(), cmdStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		snap, err := daemon.Fetch(socket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// The attached UI re-runs "watchr snapshot", following the daemon's interval
+		cmdStr = runner.QuoteFor("sh", exe) + " snapshot " + runner.QuoteFor("sh", snap.Command)
+		shell = "sh"
+		interactive = false
+		sshHost, dockerContainer, kubectlPod = "", "", ""
+		if refreshInterval == 0 {
+			refreshInterval = snap.Interval
+		}
+		journal = false
+	case "replay":
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: replay requires a log directory or file")
+			os.Exit(1)
+		}
+		runs, err := loadReplay(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		replay = runs
+		cmdStr = replay[len(replay)-1].Command
+		refreshInterval = 0
+		initialRun = false
+		journal = false
+		logOutput = ""
+		record = ""
+	}
+
+	if dryRun {
+		var r *runner.Runner
+		if interactive {
+			r = runner.NewInteractiveRunner(shell, cmdStr)
+		} else {
+			r = runner.NewRunner(shell, cmdStr)
+		}
+		r.SSH = sshHost
+		r.Docker = dockerContainer
+		r.KubectlPod = kubectlPod
+		r.RemoteShell = remoteShell
+		r.PreRun = preRun
+		r.PostRun = postRun
+		printDryRun(r)
+		os.Exit(0)
+	}
 
 	// Parse preview size (e.g., "40" for lines/cols, "40%" for percentage)
 	previewSizeIsPercent := strings.HasSuffix(previewSize, "%")
@@ -134,16 +585,252 @@ func main() {
 		PreviewSize:          previewSizeVal,
 		PreviewSizeIsPercent: previewSizeIsPercent,
 		PreviewPosition:      ui.PreviewPosition(previewPosition),
+		AutoPreview:          autoPreview,
+		PreviewOpen:          previewOpen,
+		PreviewCommand:       previewCommand,
+		PreviewContext:       config.GetInt(config.KeyPreviewContext),
+		FilePreview:          config.FilePreview(),
 		ShowLineNums:         showLineNums,
+		RelativeLineNums:     config.GetBool(config.KeyRelativeNumbers),
+		DualLineNums:         config.GetBool(config.KeyDualNumbers),
 		LineNumWidth:         lineNumWidth,
+		Differences:          config.GetBool(config.KeyDifferences),
 		Prompt:               prompt,
 		RefreshInterval:      refreshInterval,
 		RefreshFromStart:     refreshFromStart,
+		Precise:              precise,
+		RefreshJitter:        refreshJitter,
 		Interactive:          interactive,
+		SSH:                  sshHost,
+		Docker:               dockerContainer,
+		KubectlPod:           kubectlPod,
+		RemoteShell:          remoteShell,
+		PreRun:               preRun,
+		PostRun:              postRun,
+		TransitionBell:       transitionBell,
+		OnTransition:         onTransition,
+		WebhookURL:           webhookURL,
+		WebhookOnChange:      webhookOnChange,
+		WebhookMatch:         webhookMatch,
+		KeepProgressFrames:   keepProgressFrames,
+		ControlChars:         controlChars,
+		TabWidth:             tabWidth,
+		AnomalyAlert:         anomalyAlert,
+		LogOutput:            logOutput,
+		MinInterval:          minInterval,
+		Replay:               replay,
+		ExitCode:             exitCodePolicy,
+		NoInitialRun:         !initialRun,
+		Mouse:                mouse,
+		Scrollbar:            config.Scrollbar(),
+		Wrap:                 wrap,
+		Ellipsis:             config.GetString(config.KeyEllipsis),
+		WrapMarker:           config.GetString(config.KeyWrapMarker),
+		Filler:               config.GetString(config.KeyFiller),
+		Append:               config.GetBool(config.KeyAppend),
+		AppendRuns:           config.GetInt(config.KeyAppendRuns),
+		MaxRuns:              maxRuns,
+		RunFor:               runFor,
+		ExitOnLimit:          exitOnLimit,
+		Bindings:             bindings,
+		Keymap:               keymap,
+		PrintSelection:       printSelect,
+		Delimiter:            delimiter,
+		WithNth:              withNth,
+		YankDisplayed:        config.GetBool(config.KeyYankDisplayed),
+		Table:                config.GetBool(config.KeyTable),
+		HideTitle:            !config.Title(),
+		HideHints:            !config.Hints(),
+		StatusBar:            statusBar,
+		HighlightNew:         config.GetDuration(config.KeyHighlightNew),
+		DimAge:               dimAge,
+		NoLogLevels:          !config.LogLevels(),
+		Filter:               filterText,
+		FilterKind:           filterKind,
+		FilterMode:           filterMode,
+		FilterCase:           filterCase,
+		ScrollOff:            config.GetInt(config.KeyScrollOff),
+		PinnedFilters:        pinnedFilters,
+		Notes:                notes,
+		Baseline:             baseline,
+		HeaderLines:          max(config.GetInt(config.KeyHeaderLines), 0),
+		LayoutPath:           layoutPath,
+		Profile:              layoutProfile,
+		Columns:              columns,
+		YankFormat:           yankFormat,
+		TimeFormat:           timeFormat,
+		Theme:                theme,
+		Border:               border,
+		Compact:              config.GetBool(config.KeyCompact),
+		HeaderTemplate:       config.GetString(config.KeyHeaderTemplate),
+		Marks:                marks,
+		StatePath:            statePath,
+		Anchor:               anchor,
+	}
+	if journal {
+		uiConfig.JournalPath = state.SessionPath(cmdStr)
+	}
+	if record != "" {
+		info := runlog.SessionInfo{Command: cmdStr, Shell: shell, Started: time.Now()}
+		if err := runlog.CreateSession(record, info); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to create session file: %v\n", err)
+			os.Exit(1)
+		}
+		uiConfig.Record = record
 	}
 
-	if err := ui.Run(uiConfig); err != nil {
+	exitCode, err := ui.Run(uiConfig)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	os.Exit(exitCode)
+}
+
+// loadReplay loads the runs recorded by --log-output from a directory, the
+// runs of a --record session file, or a single run file.
+func loadReplay(path string) ([]runlog.Run, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() && runlog.IsSession(path) {
+		_, runs, err := runlog.ReadSession(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("no recorded runs in %s", path)
+		}
+		return runs, nil
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		if paths, err = runlog.List(path); err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no recorded runs in %s", path)
+		}
+	}
+
+	runs := make([]runlog.Run, 0, len(paths))
+	for _, p := range paths {
+		run, err := runlog.Read(p)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// countSet returns how many of values are non-empty.
+func countSet(values ...string) int {
+	n := 0
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// runDaemon runs the refresh loop headlessly until interrupted, serving
+// snapshots on the daemon socket and appending them to the daemon log.
+func runDaemon(d *daemon.Daemon) error {
+	if d.Interval <= 0 {
+		return fmt.Errorf("daemon requires --refresh")
+	}
+	logPath := daemon.LogPath(d.Runner.Command)
+	if logPath == "" {
+		return fmt.Errorf("no state directory available")
+	}
+	log, err := daemon.OpenLog(logPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = log.Close() }()
+	d.Log = log
+	d.Socket = daemon.SocketPath(d.Runner.Command)
+
+	// Closing the terminal the daemon was started from doesn't stop it
+	signal.Ignore(syscall.SIGHUP)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "watchr daemon: running %q every %s (snapshots logged to %s)\n", d.Runner.Command, d.Interval, logPath)
+	return d.Run(ctx)
+}
+
+// detachedEnv is set for the daemon detachDaemon starts, so it runs instead
+// of detaching again
+const detachedEnv = "WATCHR_DAEMON_DETACHED"
+
+// detachDaemon starts watchr again with the same arguments in the
+// background, with no terminal, and returns once the daemon is serving
+// snapshots. If it fails to start, its error is returned instead.
+func detachDaemon(d *daemon.Daemon) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), detachedEnv+"=1")
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	socket := daemon.SocketPath(d.Runner.Command)
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case err := <-exited:
+			// The last line is the error it exited with
+			if out := strings.TrimSpace(stderr.String()); out != "" {
+				lines := strings.Split(out, "\n")
+				return errors.New(strings.TrimPrefix(lines[len(lines)-1], "Error: "))
+			}
+			return fmt.Errorf("daemon exited: %v", err)
+		case <-deadline:
+			return fmt.Errorf("daemon did not start (pid %d)", cmd.Process.Pid)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if snap, err := daemon.Fetch(socket); err == nil && snap.Command == d.Runner.Command {
+			fmt.Fprintf(os.Stderr, "watchr daemon: running %q every %s in the background (pid %d)\n", d.Runner.Command, d.Interval, cmd.Process.Pid)
+			return nil
+		}
+	}
+}
+
+// printDryRun prints the effective invocation of the command without running it.
+func printDryRun(r *runner.Runner) {
+	inv := r.Invocation()
+	args := make([]string, len(inv.Args))
+	for i, arg := range inv.Args {
+		args[i] = strconv.Quote(arg)
+	}
+	pty := "no (stdout and stderr are piped)"
+	if inv.PTY {
+		pty = "yes"
+	}
+
+	fmt.Println("Dry run (command not executed):")
+	fmt.Printf("  %-14s %s\n", "shell:", inv.Shell)
+	fmt.Printf("  %-14s [%s]\n", "args:", strings.Join(args, ", "))
+	fmt.Printf("  %-14s %v\n", "interactive:", r.Interactive)
+	fmt.Printf("  %-14s %s (added to the current environment)\n", "env:", strings.Join(inv.Env, " "))
+	fmt.Printf("  %-14s %s\n", "cwd:", inv.Dir)
+	fmt.Printf("  %-14s %s\n", "pty:", pty)
+	if r.PreRun != "" {
+		fmt.Printf("  %-14s %s\n", "pre-run:", r.PreRun)
+	}
+	if r.PostRun != "" {
+		fmt.Printf("  %-14s %s\n", "post-run:", r.PostRun)
+	}
+	fmt.Printf("\nEquivalent command line:\n  %s\n", inv.CommandLine())
 }